@@ -25,6 +25,10 @@ else
       version="$1"
 fi
 
+commit=$(git rev-parse HEAD 2>/dev/null || echo unknown)
+date=$(date -u +%Y-%m-%dT%H:%M:%SZ)
+ldflags="-X 'github.com/americanexpress/earlybird/v4/pkg/buildflags.Version=$version' -X 'github.com/americanexpress/earlybird/v4/pkg/buildflags.Commit=$commit' -X 'github.com/americanexpress/earlybird/v4/pkg/buildflags.Date=$date'"
+
 echo "Running Unit Tests and Building binaries... version: $version"
 echo "-----------------------------------------------------------------"
 echo
@@ -41,15 +45,15 @@ if [ $testOutput -ne 0 ]; then
 fi
 
 echo "Building Linux binary"
-env GOOS=linux GOARCH=amd64 go build -ldflags="-X 'github.com/americanexpress/earlybird/v4/pkg/buildflags.Version=$version'" -o binaries/go-earlybird-linux
+env GOOS=linux GOARCH=amd64 go build -ldflags="$ldflags" -o binaries/go-earlybird-linux
 echo "Building Linux binary - Completed!!!"
 echo "Building Windows binary"
-env GOOS=windows GOARCH=amd64 go build -ldflags="-X 'github.com/americanexpress/earlybird/v4/pkg/buildflags.Version=$version'" -o binaries/go-earlybird.exe
+env GOOS=windows GOARCH=amd64 go build -ldflags="$ldflags" -o binaries/go-earlybird.exe
 echo "Building Windows binary - Completed!!!"
 echo "Building MacOS binary"
-env GOOS=darwin GOARCH=amd64 go build -ldflags="-X 'github.com/americanexpress/earlybird/v4/pkg/buildflags.Version=$version'" -o binaries/go-earlybird
+env GOOS=darwin GOARCH=amd64 go build -ldflags="$ldflags" -o binaries/go-earlybird
 echo "Building MacOS binary - Completed!!!"
 echo "Building MacOS Silicon binary"
-env GOOS=darwin GOARCH=arm64 go build -ldflags="-X 'github.com/americanexpress/earlybird/v4/pkg/buildflags.Version=$version'" -o binaries/go-earlybird-arm64
+env GOOS=darwin GOARCH=arm64 go build -ldflags="$ldflags" -o binaries/go-earlybird-arm64
 echo "Building MacOS Silicon binary - Completed!!!"
 echo "Build Completed ..."
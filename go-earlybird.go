@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/americanexpress/earlybird/v4/pkg/core"
+	"github.com/americanexpress/earlybird/v4/pkg/git"
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
 	"github.com/americanexpress/earlybird/v4/pkg/utils"
 )
@@ -32,7 +33,7 @@ var (
 )
 
 func main() {
-	//Define HTTP server cli params
+	//Define HTTP server cli params, before the subcommands as rules list takes the flags of a scan
 	ptr.HTTP = flag.String("http", "", "Listen IP and Port for HTTP API e.g. 127.0.0.1:8080")
 	ptr.HTTPConfig = flag.String("http-config", "", "Path to webserver config JSON file")
 	ptr.HTTPS = flag.String("https", "", "Listen IP and Port for HTTPS/2 API e.g. 127.0.0.1:8080 (Don't forget the https-cert and https-key flags)")
@@ -43,6 +44,33 @@ func main() {
 	gitcfg.Repo = flag.String("git", "", "Full URL to a git repo to scan e.g. github.com/user/repo")
 	gitcfg.RepoUser = flag.String("git-user", os.Getenv("gituser"), "If the git repository is private, enter an authorized username")
 	gitcfg.RepoBranch = flag.String("git-branch", "", "Name of branch to be scanned")
+	gitcfg.Token = flag.String("git-token", "", "Access token to clone the private repository of -git, sent as the password of -git-user -- prefer the EARLYBIRD_GIT_TOKEN environment variable to keep it out of the shell history")
+	gitcfg.Depth = flag.Int("git-clone-depth", git.DefaultCloneDepth, "Number of commits of the history cloned by -git (0 for the whole history)")
+
+	//Run the subcommands, which have their own flags
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case core.InstallHookCommand:
+			if err := core.InstallHook(os.Args[2:]); err != nil {
+				utils.Fatal(err)
+			}
+			return
+		case core.VersionCommand:
+			core.PrintVersion(os.Stdout)
+			return
+		case core.RulesCommand:
+			// The rules are selected with the flags of the scan, which follow the action
+			if len(os.Args) < 3 || os.Args[2] != core.RulesListCommand {
+				utils.Fatalf("Usage: go-earlybird %s %s [flags]", core.RulesCommand, core.RulesListCommand)
+			}
+			os.Args = append(os.Args[:1], os.Args[3:]...)
+			eb.ConfigInit()
+			if err := eb.ListRules(os.Stdout); err != nil {
+				utils.Fatal(err)
+			}
+			return
+		}
+	}
 
 	//Load CLI params and Earlybird config
 	eb.ConfigInit()
@@ -28,52 +28,65 @@ import (
 	"github.com/americanexpress/earlybird/v4/pkg/git"
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
 	"github.com/americanexpress/earlybird/v4/pkg/utils"
+	"github.com/gorilla/mux"
 	"gopkg.in/src-d/go-git.v4/plumbing/transport"
 )
 
 //Scan uses the Earlybird config to search uploaded multipart files for secrets
 func Scan(cfg cfgreader.EarlybirdConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		err := r.ParseMultipartForm(1024 << 20) // 1GB upload limit
-		if err != nil {
-			http.Error(w, "File upload too large: "+err.Error(), http.StatusInternalServerError)
+		fileList, ok := uploadedFiles(w, r, cfg)
+		if !ok {
 			return
 		}
-		start := time.Now()
+		report := scanUpload(cfg, fileList)
 
-		//Get files from req
-		formdata := r.MultipartForm
-		fileList, err := file.MultipartToScanFiles(formdata.File["scan"], cfg)
+		//Encode and send JSON response
+		response, err := json.MarshalIndent(report, "", "\t")
 		if err != nil {
-			http.Error(w, "Failed to parse file upload: "+err.Error(), http.StatusInternalServerError)
+			http.Error(w, "Failed to encode JSON response: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
+		fmt.Fprint(w, string(response))
+	}
+}
 
-		// Define our result objects and start scan process
-		var Hits []scan.Hit
-		HitChannel := make(chan scan.Hit)
-		go scan.SearchFiles(&cfg, fileList, []string{}, []string{}, HitChannel)
+//ScanAsync queues the scan of the uploaded multipart files and returns its job immediately, the job is polled with ScanJob
+func ScanAsync(cfg cfgreader.EarlybirdConfig, store *JobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		fileList, ok := uploadedFiles(w, r, cfg)
+		if !ok {
+			return
+		}
+		job, err := store.Start(func() (scan.Report, error) {
+			return scanUpload(cfg, fileList), nil
+		})
+		if err != nil {
+			http.Error(w, "Failed to queue scan: "+err.Error(), http.StatusInternalServerError)
+			return
+		}
 
-		for hit := range HitChannel {
-			Hits = append(Hits, hit)
+		response, err := json.MarshalIndent(job, "", "\t")
+		if err != nil {
+			http.Error(w, "Failed to encode JSON response: "+err.Error(), http.StatusInternalServerError)
+			return
 		}
+		w.Header().Set("Location", "/scan/"+job.ID)
+		w.WriteHeader(http.StatusAccepted)
+		fmt.Fprint(w, string(response))
+	}
+}
 
-		//Format our results into an Earlybird report
-		report := scan.Report{
-			Hits:          Hits,
-			HitCount:      len(Hits),
-			Version:       cfg.Version,
-			Modules:       cfg.EnabledModules,
-			Threshold:     cfg.SeverityDisplayLevel,
-			FilesScanned:  len(fileList),
-			RulesObserved: len(scan.CombinedRules),
-			StartTime:     start.UTC().Format(time.RFC3339),
-			EndTime:       time.Now().UTC().Format(time.RFC3339),
-			Duration:      fmt.Sprintf("%d ms", time.Since(start)/time.Millisecond),
+//ScanJob returns the status of an asynchronous scan, and its report once it's done
+func ScanJob(store *JobStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		job, ok := store.Get(mux.Vars(r)["id"])
+		if !ok {
+			http.Error(w, "Scan job not found, it may have expired", http.StatusNotFound)
+			return
 		}
 
-		//Encode and send JSON response
-		response, err := json.MarshalIndent(report, "", "\t")
+		response, err := json.MarshalIndent(job, "", "\t")
 		if err != nil {
 			http.Error(w, "Failed to encode JSON response: "+err.Error(), http.StatusInternalServerError)
 			return
@@ -82,6 +95,59 @@ func Scan(cfg cfgreader.EarlybirdConfig) http.HandlerFunc {
 	}
 }
 
+//uploadedFiles reads the files of the multipart upload, the error response is sent when they can't be read
+func uploadedFiles(w http.ResponseWriter, r *http.Request, cfg cfgreader.EarlybirdConfig) (fileList []scan.File, ok bool) {
+	err := r.ParseMultipartForm(1024 << 20) // 1GB upload limit
+	if err != nil {
+		http.Error(w, "File upload too large: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+
+	//Get files from req
+	formdata := r.MultipartForm
+	fileList, err = file.MultipartToScanFiles(formdata.File["scan"], cfg)
+	if err != nil {
+		http.Error(w, "Failed to parse file upload: "+err.Error(), http.StatusInternalServerError)
+		return nil, false
+	}
+	return fileList, true
+}
+
+//scanUpload searches the uploaded files for secrets and formats the results into an Earlybird report
+func scanUpload(cfg cfgreader.EarlybirdConfig, fileList []scan.File) scan.Report {
+	start := time.Now()
+	// Define our result objects and start scan process
+	var Hits, Suppressed []scan.Hit
+	HitChannel := make(chan scan.Hit)
+	go scan.SearchFiles(&cfg, fileList, []string{}, []string{}, HitChannel)
+
+	for hit := range scan.SortHits(HitChannel) {
+		if hit.Suppressed {
+			Suppressed = append(Suppressed, hit)
+			continue
+		}
+		Hits = append(Hits, hit)
+	}
+
+	summary := file.Context{Files: fileList, Start: start}.Summary(Hits)
+	return scan.Report{
+		Hits:          Hits,
+		HitCount:      len(Hits),
+		Suppressed:    Suppressed,
+		Version:       cfg.Version,
+		Commit:        cfg.Commit,
+		BuildDate:     cfg.BuildDate,
+		Modules:       cfg.EnabledModules,
+		Threshold:     cfg.SeverityDisplayLevel,
+		FilesScanned:  len(fileList),
+		RulesObserved: len(scan.CombinedRules),
+		StartTime:     start.UTC().Format(time.RFC3339),
+		EndTime:       time.Now().UTC().Format(time.RFC3339),
+		Duration:      fmt.Sprintf("%d ms", time.Since(start)/time.Millisecond),
+		Summary:       &summary,
+	}
+}
+
 //GITScan searches for secrets in git repositories based off the Earlybird config, supports authentication via env variables "gituser" and "gitpassword"
 func GITScan(cfg cfgreader.EarlybirdConfig) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -99,40 +165,48 @@ func GITScan(cfg cfgreader.EarlybirdConfig) http.HandlerFunc {
 		giturl := giturls[0]
 		gitbranch := r.URL.Query().Get("branch")
 		utils.GetGitURL(&giturl, &blank)
-		mycfg.SearchDir, err = git.CloneGitRepos([]string{giturl}, os.Getenv("gituser"), os.Getenv("gitpassword"), gitbranch, (cfg.OutputFormat == "json"))
+		mycfg.SearchDir, err = git.CloneGitRepos([]string{giturl}, os.Getenv("gituser"), os.Getenv("gitpassword"), gitbranch, git.DefaultCloneDepth, (cfg.OutputFormat == "json"))
 		if err != nil {
 			if err == transport.ErrAuthenticationRequired {
 				http.Error(w, "Failed to clone, repository is private. Please enter a public repository URL.", http.StatusInternalServerError)
 			} else {
 				http.Error(w, "Failed to clone, please verify your repository is available", http.StatusInternalServerError)
 			}
-			utils.DeleteGit(giturl, mycfg.SearchDir)
 			return
 		}
+		//Delete our tmp directory when done
+		defer utils.DeleteGit(giturl, mycfg.SearchDir)
 
-		fileContext, err := file.GetFiles(mycfg.SearchDir, mycfg.IgnoreFile, mycfg.VerboseEnabled, cfg.MaxFileSize)
+		fileContext, err := file.GetFiles(&mycfg)
 		if err != nil {
 			http.Error(w, "Failed to load scan files: "+err.Error(), http.StatusInternalServerError)
 			return
 		}
-		//Delete our tmp directory when done
-		defer utils.DeleteGit(giturl, mycfg.SearchDir)
 		// Start building a list of hits.  The module go routines will all dump back to this
-		var Hits []scan.Hit
+		var Hits, Suppressed []scan.Hit
 		HitChannel := make(chan scan.Hit)
 		//Create pointer to reduce memory overhead
 		go scan.SearchFiles(&mycfg, fileContext.Files, fileContext.CompressPaths, fileContext.ConvertPaths, HitChannel)
 
-		for hit := range HitChannel {
+		for hit := range scan.SortHits(HitChannel) {
+			if hit.Suppressed {
+				Suppressed = append(Suppressed, hit)
+				continue
+			}
 			Hits = append(Hits, hit)
 		}
 
+		fileContext.Start = start
+		summary := fileContext.Summary(Hits)
 		report := scan.Report{
 			Hits:          Hits,
 			HitCount:      len(Hits),
+			Suppressed:    Suppressed,
 			Skipped:       fileContext.SkippedFiles,
 			Ignore:        fileContext.IgnorePatterns,
 			Version:       cfg.Version,
+			Commit:        cfg.Commit,
+			BuildDate:     cfg.BuildDate,
 			Modules:       cfg.EnabledModules,
 			Threshold:     mycfg.SeverityDisplayLevel,
 			FilesScanned:  len(fileContext.Files),
@@ -140,6 +214,7 @@ func GITScan(cfg cfgreader.EarlybirdConfig) http.HandlerFunc {
 			StartTime:     start.UTC().Format(time.RFC3339),
 			EndTime:       time.Now().UTC().Format(time.RFC3339),
 			Duration:      fmt.Sprintf("%d ms", time.Since(start)/time.Millisecond),
+			Summary:       &summary,
 		}
 
 		response, err := json.MarshalIndent(report, "", "\t")
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireToken(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantStatus    int
+	}{
+		{
+			name:       "No token configured",
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "Missing token",
+			token:      "s3cr3t-token",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:          "Wrong token",
+			token:         "s3cr3t-token",
+			authorization: "Bearer wrong-token",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Prefix of the token",
+			token:         "s3cr3t-token",
+			authorization: "Bearer s3cr3t",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Token with another scheme",
+			token:         "s3cr3t-token",
+			authorization: "Basic s3cr3t-token",
+			wantStatus:    http.StatusUnauthorized,
+		},
+		{
+			name:          "Correct token",
+			token:         "s3cr3t-token",
+			authorization: "Bearer s3cr3t-token",
+			wantStatus:    http.StatusOK,
+		},
+		{
+			name:          "Correct token with a lower case scheme",
+			token:         "s3cr3t-token",
+			authorization: "bearer s3cr3t-token",
+			wantStatus:    http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/labels", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+			RequireToken(tt.token, next).ServeHTTP(rr, req)
+			if rr.Code != tt.wantStatus {
+				t.Errorf("RequireToken() status = %v, want %v", rr.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && rr.Header().Get("WWW-Authenticate") == "" {
+				t.Errorf("RequireToken() didn't ask for a bearer token")
+			}
+		})
+	}
+}
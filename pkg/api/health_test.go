@@ -0,0 +1,84 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+func TestHealthz(t *testing.T) {
+	rr := httptest.NewRecorder()
+	Healthz().ServeHTTP(rr, httptest.NewRequest("GET", "/healthz", nil))
+	if rr.Code != http.StatusOK {
+		t.Errorf("Healthz returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+	}
+}
+
+func TestReadyz(t *testing.T) {
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	tests := []struct {
+		name       string
+		ready      func() error
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "Rules loaded",
+			ready:      scan.Ready,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "No rules loaded",
+			ready: func() error {
+				scan.CombinedRules = nil
+				return scan.Ready()
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantError:  "no rules loaded",
+		},
+		{
+			name: "Rule file failed to load",
+			ready: func() error {
+				return errors.New("module content: open content.yaml: no such file or directory")
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			wantError:  "module content: open content.yaml: no such file or directory",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			Readyz(tt.ready).ServeHTTP(rr, httptest.NewRequest("GET", "/readyz", nil))
+			if rr.Code != tt.wantStatus {
+				t.Errorf("Readyz returned wrong status code: got %v want %v", rr.Code, tt.wantStatus)
+			}
+			var health HealthResponse
+			if err := json.NewDecoder(rr.Body).Decode(&health); err != nil {
+				t.Fatalf("Failed to parse result from Readyz: %v", err)
+			}
+			if health.Error != tt.wantError {
+				t.Errorf("Readyz error = %q, want %q", health.Error, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+//Job statuses of an asynchronous scan
+const (
+	JobQueued  = "queued"
+	JobRunning = "running"
+	JobDone    = "done"
+	JobFailed  = "failed"
+)
+
+//Job is an asynchronous scan, its report is set once it's done
+type Job struct {
+	ID       string       `json:"id"`
+	Status   string       `json:"status"`
+	Error    string       `json:"error,omitempty"`
+	Report   *scan.Report `json:"report,omitempty"`
+	finished time.Time
+}
+
+//JobStore holds the asynchronous scans, the jobs are garbage-collected once their TTL has passed since they finished
+type JobStore struct {
+	mu      sync.Mutex
+	jobs    map[string]*Job
+	ttl     time.Duration
+	running chan struct{}
+	now     func() time.Time
+}
+
+//NewJobStore creates a job store running at most maxRunning scans at a time, the rest are queued
+func NewJobStore(ttl time.Duration, maxRunning int) *JobStore {
+	if maxRunning < 1 {
+		maxRunning = 1
+	}
+	return &JobStore{
+		jobs:    make(map[string]*Job),
+		ttl:     ttl,
+		running: make(chan struct{}, maxRunning),
+		now:     time.Now,
+	}
+}
+
+//Start queues the scan and runs it in the background, it returns the queued job
+func (store *JobStore) Start(run func() (scan.Report, error)) (Job, error) {
+	id, err := newJobID()
+	if err != nil {
+		return Job{}, err
+	}
+	job := &Job{ID: id, Status: JobQueued}
+	store.mu.Lock()
+	store.collect()
+	store.jobs[id] = job
+	queued := *job
+	store.mu.Unlock()
+
+	go func() {
+		store.running <- struct{}{}
+		defer func() { <-store.running }()
+		store.update(id, func(job *Job) { job.Status = JobRunning })
+		report, err := run()
+		store.update(id, func(job *Job) {
+			job.Status = JobDone
+			job.Report = &report
+			if err != nil {
+				job.Status = JobFailed
+				job.Error = err.Error()
+				job.Report = nil
+			}
+			job.finished = store.now()
+		})
+	}()
+	return queued, nil
+}
+
+//Get returns a copy of the job, false when it doesn't exist or has expired
+func (store *JobStore) Get(id string) (Job, bool) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	store.collect()
+	job, ok := store.jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+//update changes the job under the lock
+func (store *JobStore) update(id string, change func(job *Job)) {
+	store.mu.Lock()
+	defer store.mu.Unlock()
+	if job, ok := store.jobs[id]; ok {
+		change(job)
+	}
+}
+
+//collect deletes the finished jobs whose TTL has passed, the lock must be held
+func (store *JobStore) collect() {
+	now := store.now()
+	for id, job := range store.jobs {
+		if !job.finished.IsZero() && now.Sub(job.finished) > store.ttl {
+			delete(store.jobs, id)
+		}
+	}
+}
+
+//newJobID returns a random job ID
+func newJobID() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
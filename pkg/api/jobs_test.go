@@ -0,0 +1,174 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/gorilla/mux"
+)
+
+// waitForStatus polls the job until it reaches the status
+func waitForStatus(t *testing.T, store *JobStore, id, status string) Job {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, ok := store.Get(id)
+		if ok && job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job %s = %+v (found %v), want status %s", id, job, ok, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobStore(t *testing.T) {
+	var (
+		clockMu sync.Mutex
+		clock   = time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	)
+	advance := func(d time.Duration) {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		clock = clock.Add(d)
+	}
+	store := NewJobStore(time.Minute, 1)
+	store.now = func() time.Time {
+		clockMu.Lock()
+		defer clockMu.Unlock()
+		return clock
+	}
+
+	release := make(chan struct{})
+	first, err := store.Start(func() (scan.Report, error) {
+		<-release
+		return scan.Report{HitCount: 1}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Status != JobQueued || first.ID == "" {
+		t.Errorf("Start() = %+v, want a queued job with an ID", first)
+	}
+	waitForStatus(t, store, first.ID, JobRunning)
+
+	// Only one scan runs at a time, the second one waits in the queue
+	second, err := store.Start(func() (scan.Report, error) {
+		return scan.Report{}, errors.New("scan failed")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second.ID == first.ID {
+		t.Errorf("Start() returned the ID %s twice", second.ID)
+	}
+	if job, _ := store.Get(second.ID); job.Status != JobQueued {
+		t.Errorf("Get() status = %s, want %s while the first scan runs", job.Status, JobQueued)
+	}
+
+	close(release)
+	done := waitForStatus(t, store, first.ID, JobDone)
+	if done.Report == nil || done.Report.HitCount != 1 {
+		t.Errorf("Get() report = %+v, want the report of the scan", done.Report)
+	}
+	failed := waitForStatus(t, store, second.ID, JobFailed)
+	if failed.Error != "scan failed" || failed.Report != nil {
+		t.Errorf("Get() = %+v, want the error of the scan without report", failed)
+	}
+
+	// The results are kept for the TTL once the job is done
+	advance(time.Minute)
+	if _, ok := store.Get(first.ID); !ok {
+		t.Errorf("Get() didn't find the job before its TTL")
+	}
+	advance(time.Second)
+	if _, ok := store.Get(first.ID); ok {
+		t.Errorf("Get() found the job after its TTL")
+	}
+	if _, ok := store.Get("unknown"); ok {
+		t.Errorf("Get() found an unknown job")
+	}
+}
+
+func TestScanAsync(t *testing.T) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("scan", "sample.py")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = part.Write([]byte(`password="SampleFinding678#"`)); err != nil {
+		t.Fatal(err)
+	}
+	writer.Close()
+
+	store := NewJobStore(time.Minute, 1)
+	router := mux.NewRouter()
+	router.HandleFunc("/scan", ScanAsync(cfg, store)).Methods("POST")
+	router.HandleFunc("/scan/{id}", ScanJob(store)).Methods("GET")
+
+	req := httptest.NewRequest("POST", "/scan", body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	rr := httptest.NewRecorder()
+	router.ServeHTTP(rr, req)
+	if rr.Code != http.StatusAccepted {
+		t.Fatalf("ScanAsync returned wrong status code: got %v want %v", rr.Code, http.StatusAccepted)
+	}
+	var job Job
+	if err := json.NewDecoder(rr.Body).Decode(&job); err != nil {
+		t.Fatalf("Failed to parse result from ScanAsync: %v", err)
+	}
+	if location := rr.Header().Get("Location"); location != "/scan/"+job.ID {
+		t.Errorf("ScanAsync returned location %q, want /scan/%s", location, job.ID)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for job.Status != JobDone {
+		if time.Now().After(deadline) {
+			t.Fatalf("ScanJob status = %s, want %s", job.Status, JobDone)
+		}
+		time.Sleep(5 * time.Millisecond)
+		rr = httptest.NewRecorder()
+		router.ServeHTTP(rr, httptest.NewRequest("GET", "/scan/"+job.ID, nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("ScanJob returned wrong status code: got %v want %v", rr.Code, http.StatusOK)
+		}
+		if err := json.NewDecoder(rr.Body).Decode(&job); err != nil {
+			t.Fatalf("Failed to parse result from ScanJob: %v", err)
+		}
+	}
+	if job.Report == nil || len(job.Report.Hits) == 0 {
+		t.Errorf("Failed to locate secrets in example file: %+v", job.Report)
+	}
+
+	rr = httptest.NewRecorder()
+	router.ServeHTTP(rr, httptest.NewRequest("GET", "/scan/unknown", nil))
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("ScanJob returned wrong status code for an unknown job: got %v want %v", rr.Code, http.StatusNotFound)
+	}
+}
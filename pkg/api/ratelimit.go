@@ -0,0 +1,117 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+//cleanupInterval is how often the buckets of the idle clients are deleted
+const cleanupInterval = time.Minute
+
+//RateLimiter limits the requests of each client IP with a token bucket, refilled at rate tokens per second up to burst tokens
+type RateLimiter struct {
+	mu          sync.Mutex
+	rate        float64
+	burst       float64
+	clients     map[string]*bucket
+	lastCleanup time.Time
+	now         func() time.Time
+}
+
+//bucket holds the tokens left to a client
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+//NewRateLimiter creates a rate limiter allowing rate requests per second with bursts of burst requests per client,
+//a rate of 0 doesn't limit the requests
+func NewRateLimiter(rate float64, burst int) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		clients: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+//Limit rejects the requests of the clients over their rate with 429 Too Many Requests and a Retry-After header
+func (limiter *RateLimiter) Limit(next http.HandlerFunc) http.HandlerFunc {
+	if limiter.rate <= 0 {
+		return next
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		if ok, retryAfter := limiter.allow(clientIP(r)); !ok {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			http.Error(w, "Too many requests, retry later", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
+
+//allow takes a token from the bucket of the client, otherwise it returns how long until the next token
+func (limiter *RateLimiter) allow(client string) (ok bool, retryAfter time.Duration) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	now := limiter.now()
+	limiter.cleanup(now)
+
+	b, found := limiter.clients[client]
+	if !found {
+		b = &bucket{tokens: limiter.burst, last: now}
+		limiter.clients[client] = b
+	}
+	b.tokens = math.Min(limiter.burst, b.tokens+now.Sub(b.last).Seconds()*limiter.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false, time.Duration((1 - b.tokens) / limiter.rate * float64(time.Second))
+	}
+	b.tokens--
+	return true, 0
+}
+
+//cleanup deletes the buckets which have refilled since, they are the same as the bucket of a new client, the lock must be held
+func (limiter *RateLimiter) cleanup(now time.Time) {
+	if now.Sub(limiter.lastCleanup) < cleanupInterval {
+		return
+	}
+	limiter.lastCleanup = now
+	for client, b := range limiter.clients {
+		if b.tokens+now.Sub(b.last).Seconds()*limiter.rate >= limiter.burst {
+			delete(limiter.clients, client)
+		}
+	}
+}
+
+//clientIP returns the IP of the client, the forwarded headers aren't trusted as clients could set them to evade the limit
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter(t *testing.T) {
+	clock := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	limiter := NewRateLimiter(2, 3)
+	limiter.now = func() time.Time { return clock }
+	handler := limiter.Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/scan", nil)
+		req.RemoteAddr = remoteAddr
+		rr := httptest.NewRecorder()
+		handler(rr, req)
+		return rr
+	}
+
+	// The burst is allowed, the next request is over the limit
+	for i := 0; i < 3; i++ {
+		if rr := request("10.0.0.1:40000"); rr.Code != http.StatusOK {
+			t.Fatalf("Request %d within the burst status = %v, want %v", i+1, rr.Code, http.StatusOK)
+		}
+	}
+	rr := request("10.0.0.1:40001")
+	if rr.Code != http.StatusTooManyRequests {
+		t.Fatalf("Request over the limit status = %v, want %v", rr.Code, http.StatusTooManyRequests)
+	}
+	if retryAfter := rr.Header().Get("Retry-After"); retryAfter != "1" {
+		t.Errorf("Request over the limit Retry-After = %q, want 1", retryAfter)
+	}
+
+	// Other clients have their own bucket
+	if rr := request("10.0.0.2:40000"); rr.Code != http.StatusOK {
+		t.Errorf("Request of another client status = %v, want %v", rr.Code, http.StatusOK)
+	}
+
+	// The bucket refills at the rate
+	clock = clock.Add(500 * time.Millisecond)
+	if rr := request("10.0.0.1:40000"); rr.Code != http.StatusOK {
+		t.Errorf("Request after a refill status = %v, want %v", rr.Code, http.StatusOK)
+	}
+	if rr := request("10.0.0.1:40000"); rr.Code != http.StatusTooManyRequests {
+		t.Errorf("Second request after a refill status = %v, want %v", rr.Code, http.StatusTooManyRequests)
+	}
+
+	// The buckets of the idle clients are cleaned up
+	clock = clock.Add(cleanupInterval)
+	request("10.0.0.3:40000")
+	if _, ok := limiter.clients["10.0.0.1"]; ok || len(limiter.clients) != 1 {
+		t.Errorf("Clients after the cleanup = %v, want only the last client", limiter.clients)
+	}
+}
+
+func TestRateLimiterDisabled(t *testing.T) {
+	handler := NewRateLimiter(0, 1).Limit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for i := 0; i < 10; i++ {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest("POST", "/scan", nil))
+		if rr.Code != http.StatusOK {
+			t.Fatalf("Request %d status = %v, want %v", i+1, rr.Code, http.StatusOK)
+		}
+	}
+}
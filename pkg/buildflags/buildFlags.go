@@ -1,4 +1,29 @@
 package buildflags
 
+import "runtime/debug"
+
 // Version the default value would be dev and this would be injected via ldflags
 var Version = "dev"
+
+// Commit is the git commit the binary was built from, injected via ldflags
+var Commit = "unknown"
+
+// Date is the UTC build date in RFC 3339 format, injected via ldflags
+var Date = "unknown"
+
+// init falls back on the version control information stamped by the go toolchain when the commit and the date aren't
+// injected, e.g. for the binaries built with go build, the date then being the date of the commit
+func init() {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+	for _, setting := range info.Settings {
+		switch {
+		case setting.Key == "vcs.revision" && Commit == "unknown":
+			Commit = setting.Value
+		case setting.Key == "vcs.time" && Date == "unknown":
+			Date = setting.Value
+		}
+	}
+}
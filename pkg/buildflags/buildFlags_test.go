@@ -0,0 +1,28 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package buildflags
+
+import "testing"
+
+func TestDefaults(t *testing.T) {
+	// The test binary isn't built with ldflags, the defaults or the version control information of the toolchain apply
+	for name, value := range map[string]string{"Version": Version, "Commit": Commit, "Date": Date} {
+		if value == "" {
+			t.Errorf("%s is empty, want a default when it isn't set via ldflags", name)
+		}
+	}
+}
@@ -19,6 +19,7 @@ package cfgreader
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"unicode"
@@ -47,14 +48,14 @@ func LoadConfig(cfg interface{}, path string) (err error) {
 
 	byteValue, err := toJson(data)
 	if err != nil {
-		return err
+		return &ConfigError{Kind: ErrConfigParse, File: path, Err: err}
 	}
 
 	// we unmarshal our byteArray which contains our
 	// jsonFile's content into arrays which we defined above
 	err = json.Unmarshal(byteValue, &cfg)
 	if err != nil {
-		return err
+		return &ConfigError{Kind: ErrConfigParse, File: path, Err: err}
 	}
 
 	return err
@@ -83,24 +84,52 @@ func hasPrefix(data, prefix []byte) bool {
 	return bytes.HasPrefix(trimmed, prefix)
 }
 
+//defaultLevelName is the level the thresholds default to when they aren't set
+const defaultLevelName = "low"
+
+//levelID returns the ID of the i-th level of the scale, its position in finding_levels when it has no level_id, so a
+//custom scale can be listed from the highest to the lowest level without IDs
+func (cfg *Configs) levelID(i int) int {
+	if cfg.LevelConfigs[i].ID != 0 {
+		return cfg.LevelConfigs[i].ID
+	}
+	return i + 1
+}
+
 //TranslateLevelID returns the text value of a level integer (e.g., 2 --> high)
 func (cfg *Configs) TranslateLevelID(level int) string {
-	for _, levelConfig := range cfg.LevelConfigs {
-		if levelConfig.ID == level {
+	for i, levelConfig := range cfg.LevelConfigs {
+		if cfg.levelID(i) == level {
 			return levelConfig.Name
 		}
 	}
-	return "low"
+	return cfg.DefaultLevelName()
 }
 
 //TranslateLevelName returns the int level of a string value (e.g., high --> 2)
 func (cfg *Configs) TranslateLevelName(level string) int {
-	for _, levelConfig := range cfg.LevelConfigs {
+	for i, levelConfig := range cfg.LevelConfigs {
 		if levelConfig.Name == level {
-			return levelConfig.ID
+			return cfg.levelID(i)
 		}
 	}
-	return 4
+	return cfg.GetLevelMap()[cfg.DefaultLevelName()]
+}
+
+//DefaultLevelName returns the name of the level the thresholds default to, low unless the scale doesn't have it, its
+//lowest level otherwise
+func (cfg *Configs) DefaultLevelName() string {
+	levelMap := cfg.GetLevelMap()
+	if _, ok := levelMap[defaultLevelName]; ok || len(levelMap) == 0 {
+		return defaultLevelName
+	}
+	lowest := ""
+	for name, id := range levelMap {
+		if lowest == "" || id > levelMap[lowest] {
+			lowest = name
+		}
+	}
+	return lowest
 }
 
 //GetLevelNames returns a slice of strings for the level names
@@ -114,8 +143,59 @@ func (cfg *Configs) GetLevelNames() (levels []string) {
 //GetLevelMap returns a map of severity levels
 func (cfg *Configs) GetLevelMap() (levelMap map[string]int) {
 	levelMap = make(map[string]int)
-	for _, levelConfig := range cfg.LevelConfigs {
-		levelMap[levelConfig.Name] = levelConfig.ID
+	for i, levelConfig := range cfg.LevelConfigs {
+		levelMap[levelConfig.Name] = cfg.levelID(i)
 	}
 	return levelMap
 }
+
+//GetFailSeverityLevel returns the level ID of the lowest severity failing the scan.  The severity name from the CLI takes
+//precedence over the fail_severity of the config file, followed by its fail_threshold_level, failing on low without them.
+func (cfg *Configs) GetFailSeverityLevel(cliSeverity string) (int, error) {
+	levelName := cliSeverity
+	if levelName == "" {
+		levelName = cfg.FailSeverity
+	}
+	if levelName == "" {
+		if cfg.FailThreshold != 0 {
+			return cfg.FailThreshold, nil
+		}
+		levelName = cfg.DefaultLevelName()
+	}
+	level, ok := cfg.GetLevelMap()[levelName]
+	if !ok {
+		return 0, fmt.Errorf("invalid fail severity %q, expected one of %v", levelName, cfg.GetLevelNames())
+	}
+	return level, nil
+}
+
+//GetMinConfidenceLevel returns the level ID of the lowest confidence reported.  The confidence name from the CLI takes
+//precedence over the min_confidence of the config file, 0 reports the findings of every confidence.
+func (cfg *Configs) GetMinConfidenceLevel(cliConfidence string) (int, error) {
+	levelName := cliConfidence
+	if levelName == "" {
+		levelName = cfg.MinConfidence
+	}
+	if levelName == "" {
+		return 0, nil
+	}
+	level, ok := cfg.GetLevelMap()[levelName]
+	if !ok {
+		return 0, fmt.Errorf("invalid minimum confidence %q, expected one of %v", levelName, cfg.GetLevelNames())
+	}
+	return level, nil
+}
+
+//GetSeverityOverrides returns the rule severity overrides as level IDs, failing on unknown level names
+func (cfg *Configs) GetSeverityOverrides() (overrides map[int]int, err error) {
+	levelMap := cfg.GetLevelMap()
+	overrides = make(map[int]int, len(cfg.SeverityOverrides))
+	for code, levelName := range cfg.SeverityOverrides {
+		level, ok := levelMap[levelName]
+		if !ok {
+			return nil, fmt.Errorf("invalid severity %q for rule %d, expected one of %v", levelName, code, cfg.GetLevelNames())
+		}
+		overrides[code] = level
+	}
+	return overrides, nil
+}
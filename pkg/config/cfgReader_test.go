@@ -19,6 +19,9 @@ package cfgreader
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"path"
+	"reflect"
 	"testing"
 
 	"github.com/americanexpress/earlybird/v4/pkg/utils"
@@ -88,6 +91,212 @@ func TestGetLevelMap(t *testing.T) {
 	}
 }
 
+func TestCustomLevelScale(t *testing.T) {
+	tests := []struct {
+		name        string
+		levels      string
+		wantMap     map[string]int
+		wantDefault string
+	}{
+		{
+			name:        "Levels with IDs",
+			levels:      `[{"level_name": "high", "level_id": 2}, {"level_name": "medium", "level_id": 3}, {"level_name": "low", "level_id": 4}]`,
+			wantMap:     map[string]int{"high": 2, "medium": 3, "low": 4},
+			wantDefault: "low",
+		},
+		{
+			name:        "Levels ordered by their position",
+			levels:      `[{"level_name": "blocker"}, {"level_name": "major"}, {"level_name": "minor"}]`,
+			wantMap:     map[string]int{"blocker": 1, "major": 2, "minor": 3},
+			wantDefault: "minor",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configFile := path.Join(t.TempDir(), "earlybird.json")
+			if err := os.WriteFile(configFile, []byte(`{"finding_levels": `+tt.levels+`}`), 0644); err != nil {
+				t.Fatal(err)
+			}
+			var settings Configs
+			if err := LoadConfig(&settings, configFile); err != nil {
+				t.Fatal(err)
+			}
+			if got := settings.GetLevelMap(); !reflect.DeepEqual(got, tt.wantMap) {
+				t.Errorf("GetLevelMap() = %v, want %v", got, tt.wantMap)
+			}
+			for name, id := range tt.wantMap {
+				if got := settings.TranslateLevelID(id); got != name {
+					t.Errorf("TranslateLevelID(%d) = %v, want %v", id, got, name)
+				}
+				if got := settings.TranslateLevelName(name); got != id {
+					t.Errorf("TranslateLevelName(%s) = %v, want %v", name, got, id)
+				}
+			}
+			if got := settings.DefaultLevelName(); got != tt.wantDefault {
+				t.Errorf("DefaultLevelName() = %v, want %v", got, tt.wantDefault)
+			}
+			// The unknown levels and the thresholds left out default to the lowest level of the scale
+			if got := settings.TranslateLevelName("critical"); got != tt.wantMap[tt.wantDefault] {
+				t.Errorf("TranslateLevelName(critical) = %v, want %v", got, tt.wantMap[tt.wantDefault])
+			}
+			if got, err := settings.GetFailSeverityLevel(""); err != nil || got != tt.wantMap[tt.wantDefault] {
+				t.Errorf("GetFailSeverityLevel() = %v, %v, want %v", got, err, tt.wantMap[tt.wantDefault])
+			}
+			if _, err := settings.GetFailSeverityLevel("critical"); err == nil {
+				t.Errorf("GetFailSeverityLevel(critical) error = nil, want the level missing from the scale")
+			}
+		})
+	}
+}
+
+func TestGetSeverityOverrides(t *testing.T) {
+	tests := []struct {
+		name      string
+		overrides map[int]string
+		want      map[int]int
+		wantErr   bool
+	}{
+		{
+			name:      "Level names are converted to IDs",
+			overrides: map[int]string{3001: "critical", 3029: "low"},
+			want:      map[int]int{3001: 1, 3029: 4},
+		},
+		{
+			name:      "Unknown level name fails",
+			overrides: map[int]string{3001: "urgent"},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := config
+			settings.SeverityOverrides = tt.overrides
+			got, err := settings.GetSeverityOverrides()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSeverityOverrides() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSeverityOverrides() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFailSeverityLevel(t *testing.T) {
+	tests := []struct {
+		name          string
+		cliSeverity   string
+		failSeverity  string
+		failThreshold int
+		want          int
+		wantErr       bool
+	}{
+		{
+			name:          "CLI severity takes precedence",
+			cliSeverity:   "critical",
+			failSeverity:  "medium",
+			failThreshold: 2,
+			want:          1,
+		},
+		{
+			name:          "Config fail severity",
+			failSeverity:  "medium",
+			failThreshold: 2,
+			want:          3,
+		},
+		{
+			name:          "Config fail threshold level",
+			failThreshold: 2,
+			want:          2,
+		},
+		{
+			name: "Fails on low by default",
+			want: 4,
+		},
+		{
+			name:        "Unknown level name fails",
+			cliSeverity: "urgent",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := config
+			settings.FailSeverity = tt.failSeverity
+			settings.FailThreshold = tt.failThreshold
+			got, err := settings.GetFailSeverityLevel(tt.cliSeverity)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFailSeverityLevel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetFailSeverityLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMinConfidenceLevel(t *testing.T) {
+	tests := []struct {
+		name          string
+		cliConfidence string
+		minConfidence string
+		want          int
+		wantErr       bool
+	}{
+		{
+			name:          "CLI confidence takes precedence",
+			cliConfidence: "high",
+			minConfidence: "medium",
+			want:          2,
+		},
+		{
+			name:          "Config minimum confidence",
+			minConfidence: "medium",
+			want:          3,
+		},
+		{
+			name: "Reports every confidence by default",
+			want: 0,
+		},
+		{
+			name:          "Unknown level name fails",
+			minConfidence: "certain",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := config
+			settings.MinConfidence = tt.minConfidence
+			got, err := settings.GetMinConfidenceLevel(tt.cliConfidence)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetMinConfidenceLevel() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("GetMinConfidenceLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigSeverityOverrides(t *testing.T) {
+	configFile := path.Join(t.TempDir(), "earlybird.json")
+	if err := os.WriteFile(configFile, []byte(`{"rule_severity_overrides": {"3001": "low"}}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var settings Configs
+	if err := LoadConfig(&settings, configFile); err != nil {
+		t.Fatal(err)
+	}
+	if got := settings.SeverityOverrides[3001]; got != "low" {
+		t.Errorf("LoadConfig() severity override = %q, want %q", got, "low")
+	}
+}
+
 func TestHasJSONPrefix(t *testing.T) {
 	cases := map[string]struct {
 		expected bool
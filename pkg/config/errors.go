@@ -0,0 +1,59 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package cfgreader
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The kinds of configuration errors, the errors of the configuration files match one of them with errors.Is
+var (
+	// ErrConfigParse is a configuration, rules, labels, false positives or solutions file which isn't valid JSON or YAML
+	ErrConfigParse = errors.New("invalid configuration file")
+	// ErrRuleCompile is a rule whose pattern, allowlist or glob doesn't compile
+	ErrRuleCompile = errors.New("invalid rule")
+	// ErrMissingRuleDir is a rules directory, built-in or custom, which can't be read
+	ErrMissingRuleDir = errors.New("missing rules directory")
+)
+
+// ConfigError is a configuration file which failed to load, exposing the file and the rule at fault along with the
+// underlying cause.  It matches its kind and its cause with errors.Is, e.g. errors.Is(err, ErrRuleCompile).
+type ConfigError struct {
+	Kind  error  // ErrConfigParse, ErrRuleCompile or ErrMissingRuleDir
+	File  string // File or directory at fault
+	Rule  int    // Code of the rule at fault, 0 when the error isn't about a single rule
+	Field string // Field of the rule at fault when it's not its pattern, e.g. allowlist or glob
+	Err   error
+}
+
+// Error describes the rule, or the kind of error and the file, at fault followed by the cause
+func (e *ConfigError) Error() string {
+	at := e.Kind.Error() + " " + e.File
+	if e.Rule != 0 {
+		at = fmt.Sprintf("rule %d in %s", e.Rule, e.File)
+		if e.Field != "" {
+			at = e.Field + " of " + at
+		}
+	}
+	return at + ": " + e.Err.Error()
+}
+
+// Unwrap returns the kind of the error and its cause, so errors.Is and errors.As match both
+func (e *ConfigError) Unwrap() []error {
+	return []error{e.Kind, e.Err}
+}
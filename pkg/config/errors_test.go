@@ -0,0 +1,86 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package cfgreader
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestConfigError(t *testing.T) {
+	cause := errors.New("error parsing regexp: missing closing ]")
+	tests := []struct {
+		name    string
+		err     *ConfigError
+		wantMsg string
+	}{
+		{
+			name:    "File",
+			err:     &ConfigError{Kind: ErrConfigParse, File: "config/earlybird.json", Err: cause},
+			wantMsg: "invalid configuration file config/earlybird.json: error parsing regexp: missing closing ]",
+		},
+		{
+			name:    "Rule pattern",
+			err:     &ConfigError{Kind: ErrRuleCompile, File: "rules/acme.json", Rule: 9903, Err: cause},
+			wantMsg: "rule 9903 in rules/acme.json: error parsing regexp: missing closing ]",
+		},
+		{
+			name:    "Rule allowlist",
+			err:     &ConfigError{Kind: ErrRuleCompile, File: "rules/acme.json", Rule: 9903, Field: "allowlist", Err: cause},
+			wantMsg: "allowlist of rule 9903 in rules/acme.json: error parsing regexp: missing closing ]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.wantMsg {
+				t.Errorf("Error() = %q, want %q", got, tt.wantMsg)
+			}
+			// The kind and the cause are matched through the wrapping errors
+			wrapped := errors.Join(errors.New("other"), errors.Join(tt.err))
+			if !errors.Is(wrapped, tt.err.Kind) || !errors.Is(wrapped, cause) {
+				t.Errorf("errors.Is(%v) doesn't match its kind and cause", wrapped)
+			}
+			var configErr *ConfigError
+			if !errors.As(wrapped, &configErr) || configErr != tt.err {
+				t.Errorf("errors.As(%v) = %v, want %v", wrapped, configErr, tt.err)
+			}
+		})
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	dir := t.TempDir()
+	broken := path.Join(dir, "broken.json")
+	if err := os.WriteFile(broken, []byte(`{"finding_levels": [`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var cfg Configs
+	err := LoadConfig(&cfg, broken)
+	var configErr *ConfigError
+	if !errors.Is(err, ErrConfigParse) || !errors.As(err, &configErr) || configErr.File != broken {
+		t.Errorf("LoadConfig() error = %v, want %v of %s", err, ErrConfigParse, broken)
+	}
+
+	// A missing file isn't a parse error
+	err = LoadConfig(&cfg, path.Join(dir, "missing.json"))
+	if !errors.Is(err, fs.ErrNotExist) || errors.Is(err, ErrConfigParse) {
+		t.Errorf("LoadConfig() error = %v, want %v", err, fs.ErrNotExist)
+	}
+}
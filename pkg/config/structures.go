@@ -16,13 +16,26 @@
 
 package cfgreader
 
-import "regexp"
+import (
+	"regexp"
+	"time"
+)
 
 // ServerConfig is the timeout configuration for the Earlybird REST API server
 type ServerConfig struct {
 	WriteTimeout int `json:"write-timeout"`
 	ReadTimeout  int `json:"read-timeout"`
 	IdleTimeout  int `json:"idle-timeout"`
+	// JobTTL is the number of seconds the results of an asynchronous scan are kept once it's done
+	JobTTL int `json:"job-ttl"`
+	// MaxRunningJobs is the number of asynchronous scans running at a time, the others are queued
+	MaxRunningJobs int `json:"max-running-jobs"`
+	// Token is the bearer token required by every request, the API is unauthenticated when it's empty
+	Token string `json:"token"`
+	// RateLimit is the number of scan requests per second allowed to each client IP, 0 doesn't limit them
+	RateLimit float64 `json:"rate-limit"`
+	// RateBurst is the number of scan requests a client IP can send at once within the rate limit
+	RateBurst int `json:"rate-burst"`
 }
 
 type AdjustedSeverityCategory struct {
@@ -52,6 +65,34 @@ type Configs struct {
 	ConfigFileURL              string                     `json:"earlybird_config_url"`
 	Version                    string                     `json:"version"`
 	AdjustedSeverityCategories []AdjustedSeverityCategory `json:"adjusted_severity_categories_patterns"`
+	// SeverityOverrides maps a rule code to the level name replacing the packaged severity of the rule
+	SeverityOverrides map[int]string `json:"rule_severity_overrides"`
+	// FailSeverity is the lowest severity name failing the scan when -fail-severity isn't set, it takes precedence over
+	// fail_threshold_level
+	FailSeverity string `json:"fail_severity"`
+	// MinConfidence is the lowest confidence name of the findings reported when -min-confidence isn't set, the findings
+	// of a lower confidence are dropped
+	MinConfidence string `json:"min_confidence"`
+	//BinaryScanExtensions lists the file extensions whose content is scanned even when it looks binary
+	BinaryScanExtensions []string `json:"binary_scan_extensions"`
+	//IncludeExtensions lists the only file extensions scanned when -include-extensions isn't set, all of them by default
+	IncludeExtensions []string `json:"include_extensions"`
+	//ExcludeExtensions lists the file extensions left out of the scan when -exclude-extensions isn't set
+	ExcludeExtensions []string `json:"exclude_extensions"`
+	//ConfidenceKeywords raise or lower the confidence of the findings whose line contains them around the match
+	ConfidenceKeywords ConfidenceKeywords `json:"confidence_keywords"`
+	//MaxMatchLength is the longest match value of a finding, in bytes, for the rules without a MaxMatchLength, 0 for no limit
+	MaxMatchLength int `json:"max_match_length"`
+	//DropLongMatches drops the findings whose match value is longer than the maximum rather than truncating it
+	DropLongMatches bool `json:"drop_long_matches"`
+}
+
+// ConfidenceKeywords are looked for on the line of a finding, around its match, to adjust its confidence by one level
+type ConfidenceKeywords struct {
+	//Boost raises the confidence, e.g. the match is assigned to a variable named password
+	Boost []string `json:"boost"`
+	//Lower lowers the confidence, e.g. the match is an example
+	Lower []string `json:"lower"`
 }
 
 // Config from -module-config-file flag
@@ -71,7 +112,14 @@ type EarlybirdConfig struct {
 	AvailableModules           []string
 	RuleModulesFilenameMap     map[string]string
 	SearchDir                  string
+	RelativePaths              bool     // Report the file paths of the findings relative to BaseDir
+	BaseDir                    string   // Directory the file paths of the findings are relative to, SearchDir when it's empty
+	SearchDirs                 []string // Directories scanned into a single report when there are several, SearchDir being their common parent
+	FilesFrom                  string   // File of the paths to scan, one per line, or - for the standard input, instead of walking SearchDir
 	Gitrepo                    string
+	GitRange                   string
+	DiffOnly                   bool // Only scan the lines added by a unified diff, of GitRange or of the standard input
+	GitHistoryDepth            int
 	TargetType                 string
 	EnabledModulesMap          map[string]string
 	EnabledModules             []string
@@ -79,34 +127,86 @@ type EarlybirdConfig struct {
 	OutputFormat               string
 	OutputFile                 string
 	IgnoreFile                 string
+	IgnoreCaseSensitive        bool
+	ForceInclude               []string // Patterns of the paths scanned even when the ignore patterns exclude them, they take precedence
+	FollowSymlinks             bool
+	MaxDepth                   int
+	SkipHidden                 bool // Skip the hidden files and directories, their name starting with a dot, e.g. .env
+	ScanGitDir                 bool // Scan the .git directories of the repositories, skipped by default
 	IgnoreFailure              bool
 	SeverityFailLevel          int
 	SeverityDisplayLevel       int
 	ConfidenceFailLevel        int
 	ConfidenceDisplayLevel     int
+	MinConfidence              int // Findings of a lower confidence are dropped, 0 keeps them all
 	ConfigDir                  string
 	RulesConfigDir             string
+	CustomRulesDir             string
 	FalsePositivesConfigDir    string
 	SolutionsConfigDir         string
 	LabelsConfigDir            string
 	LevelMap                   map[string]int
 	Suppress                   bool
+	ShowSecrets                bool // Report the matched secrets unmasked
 	VerboseEnabled             bool
+	UnsafeLog                  bool // Log the file content, e.g. the match values, unredacted
+	Quiet                      bool // Only print the findings and the errors
+	Progress                   bool // Report the progress of the scan to stderr
 	GitStream                  bool
+	Stdin                      bool
+	StdinName                  string
 	MaxFileSize                int64
+	ModifiedSince              time.Time // Only the files modified after it are scanned, all of them when it's zero
+	MaxArchiveSize             int64
+	MaxFindings                int // The scan stops once this many findings are reported, 0 for no limit
+	FileTimeout                time.Duration
 	ShowFullLine               bool
+	ContextLines               int
+	DedupFindings              bool
+	Blame                      bool
+	BaselineFile               string
+	WriteBaselineFile          string
+	Baseline                   map[string]bool
+	AllowlistFile              string
+	Allowlist                  map[string]bool // Hashes of the normalized known safe values, their findings are dropped whatever the rule
+	ColorOutput                bool
 	FailScan                   bool
+	Strict                     bool // The scan fails when a file couldn't be read, or was skipped for its size or encoding
 	RulesOnly                  bool
+	ListFiles                  bool   // The files which would be scanned are printed instead of scanning them
+	JoinContinuations          bool   // The lines continued with a backslash, and the YAML block scalars, are joined before they're scanned
+	ExplainIgnore              string // The ignore pattern deciding if this file is scanned is printed instead of scanning
 	ExtensionsToSkipScan       []string
+	BinaryScanExtensions       []string // Extensions of the files scanned even when their content looks binary
+	BinaryThreshold            float64  // Files with a higher ratio of non-printable characters are skipped as binary
+	IncludeExtensions          []string // Only the files with these extensions are scanned, all of them when it's empty
+	ExcludeExtensions          []string // The files with these extensions aren't scanned, even when they're included
+	EntropyBase64Threshold     float64
+	EntropyHexThreshold        float64
+	ConfidenceKeywords         ConfidenceKeywords // Keywords around the match raising or lowering the confidence of a finding
+	MaxMatchLength             int                // Longest match value of a finding for the rules without their own, 0 for no limit
+	DropLongMatches            bool               // The findings with a longer match value are dropped rather than truncated
+	EnabledRuleCodes           []int
+	DisabledRuleCodes          []int
+	IncludeCategories          []string // Categories of the rules to run, all the categories when empty
+	ExcludeCategories          []string // Categories of the rules to skip, takes precedence over IncludeCategories
 	AnnotationsToSkipLine      []string
 	SkipComments               bool
 	IgnoreFPRules              bool
 	ShowSolutions              bool
 	Version                    string
+	Commit                     string // Git commit the binary was built from
+	BuildDate                  string // Date the binary was built
 	WorkerCount                int
 	WorkLength                 int
 	HideMeta                   bool
 	StrictJKS                  bool
 	ModuleConfigs              ModuleConfigs
 	AdjustedSeverityCategories []AdjustedSeverityCategory
+	SeverityOverrides          map[int]int
+	CacheFile                  string // File caching the findings of the unchanged files between scans, none when it's empty
+	RefreshCache               bool   // Ignore the findings cached in CacheFile and scan every file again
+	SlackWebhook               string // Slack incoming webhook notified with the summary of the scan, none when it's empty
+	SlackThreshold             int    // Lowest number of findings notified to Slack
+	SlackLink                  string // Link to the report in the Slack notification
 }
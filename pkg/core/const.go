@@ -19,8 +19,11 @@ package core
 import (
 	"flag"
 	"os"
+	"runtime"
+	"time"
 
 	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/earlybird"
 	"github.com/americanexpress/earlybird/v4/pkg/utils"
 )
 
@@ -29,8 +32,14 @@ const (
 	falsePositivesDir = "falsepositives"
 	labelsDir         = "labels"
 	solutionsDir      = "solutions"
+	envPrefix         = "EARLYBIRD_"
+	//gitTokenUser is the user name sent along with the token of -git-token when -git-user isn't set
+	gitTokenUser = "x-access-token"
 )
 
+// gzipFormats are the output formats whose report file is compressed with gzip when its name ends with .gz
+var gzipFormats = []string{"json", "ndjson", "sarif", "sonarqube", "gitlab"}
+
 type arrayFlags []string
 
 func (i *arrayFlags) String() string {
@@ -42,37 +51,134 @@ func (i *arrayFlags) Set(value string) error {
 	return nil
 }
 
+// byteSize is a size in bytes flag, which accepts units, e.g. 5MB
+type byteSize int64
+
+func (b *byteSize) String() string {
+	return utils.FormatByteSize(int64(*b))
+}
+
+func (b *byteSize) Set(value string) error {
+	size, err := utils.ParseByteSize(value)
+	*b = byteSize(size)
+	return err
+}
+
+// byteSizeFlag defines a size in bytes flag with the default size
+func byteSizeFlag(name string, size int64, usage string) *byteSize {
+	b := byteSize(size)
+	flag.Var(&b, name, usage)
+	return &b
+}
+
+// since is a point in time flag, which accepts a timestamp or a duration before now, e.g. 7d
+type since struct{ time.Time }
+
+func (s *since) String() string {
+	if s.IsZero() {
+		return ""
+	}
+	return s.Format(time.RFC3339)
+}
+
+func (s *since) Set(value string) (err error) {
+	s.Time, err = utils.ParseSince(value, time.Now())
+	return err
+}
+
+// sinceFlag defines a point in time flag, unset by default
+func sinceFlag(name, usage string) *since {
+	s := new(since)
+	flag.Var(s, name, usage)
+	return s
+}
+
 // Define our static CLI flags
 var (
 	userHomeDir, _                = os.UserHomeDir()
 	levelOptions                  = utils.GetDisplayList(cfgreader.Settings.GetLevelNames())
 	ptrStreamInput                = flag.Bool("stream", false, "Use stream IO as input instead of file(s)")
 	enableFlags                   arrayFlags
+	disableFlags                  arrayFlags
+	pathFlags                     arrayFlags
 	ptrUpdateFlag                 = flag.Bool("update", false, "Update module configurations")
+	ptrStdin                      = flag.Bool("stdin", false, "Scan the standard input line by line as it's read, without buffering it -- e.g., 'cat secrets.env | go-earlybird --stdin'")
+	ptrStdinName                  = flag.String("stdin-name", "stdin", "File name of the standard input in the findings of --stdin")
+	ptrFilesFrom                  = flag.String("files-from", "", "File of the paths to scan, one per line, or - to read them from the standard input -- e.g., the files changed in CI, the directories aren't walked but the ignore patterns and filters still apply")
 	ptrGitStreamInput             = flag.Bool("git-commit-stream", false, "Use stream IO of Git commit log as input instead of file(s) -- e.g., 'cat secrets.text > go-earlybird'")
 	ptrVerbose                    = flag.Bool("verbose", false, "Reports details about file reads")
+	ptrProgress                   = flag.Bool("progress", false, "Report the number of files scanned, the scan rate and the remaining time to stderr while scanning")
+	ptrUnsafeLog                  = flag.Bool("unsafe-log", false, "Log the matched values of the findings in verbose mode rather than redacting them -- the logs then contain the secrets, for local debugging only")
+	ptrQuiet                      = flag.Bool("quiet", false, "Only print the findings and the errors, without the progress and informational messages -- takes precedence over --verbose")
 	ptrSuppressSecret             = flag.Bool("suppress", false, "Suppress reporting of the secret found (important if output is going to Slack or other logs)")
+	ptrShowSecrets                = flag.Bool("show-secrets", false, "Report the matched secrets unmasked, for local debugging -- the secrets are masked by default, keeping their first characters")
 	ptrStrictJKS                  = flag.Bool("strict-jks", false, "Checks for private keys in the JKS file and return hits only if found")
-	ptrWorkerCount                = flag.Int("workers", 100, "Set number of workers.")
-	ptrWorkLength                 = flag.Int("worksize", 2500, "Set Line Wrap Length.")
-	ptrMaxFileSize                = flag.Int64("max-file-size", 10240000, "Maximum file size to scan (in bytes)")
+	ptrWorkerCount                = flag.Int("workers", runtime.NumCPU(), "Set number of files scanned in parallel, 1 scans the files one at a time.")
+	ptrWorkLength                 = flag.Int("worksize", earlybird.DefaultWorkLength, "Set Line Wrap Length.")
+	ptrMaxFileSize                = byteSizeFlag("max-file-size", earlybird.DefaultMaxFileSize, "Maximum file size to scan, in bytes or with a unit, e.g. 5MB -- larger files are skipped with a notice")
+	ptrMaxFindings                = flag.Int("max-findings", 0, "Stop the scan once this many findings are reported, to cap the size of the report (0 for no limit)")
+	ptrMaxArchiveSize             = byteSizeFlag("max-archive-size", earlybird.DefaultMaxArchiveSize, "Maximum number of bytes to decompress from a single tar or gzip archive, protects against decompression bombs, e.g. 512MB (0 for no limit)")
+	ptrFileTimeout                = flag.Duration("file-timeout", 0, "Maximum time to spend scanning a single file, e.g. 30s -- the rest of the file is skipped with a warning finding (0 for no limit)")
+	ptrColorOutput                = flag.Bool("color", false, "Print the console findings grouped by file with colored severities, colors are disabled when the output isn't a terminal or NO_COLOR is set")
+	ptrDedupFindings              = flag.Bool("dedup", false, "Collapse the findings of the same rule matching the same value into a single finding listing all of its locations")
+	ptrBlame                      = flag.Bool("blame", false, "Attribute the findings of git tracked files to the commit and author of their line with git blame")
+	ptrBaselineFile               = flag.String("baseline", "", "Baseline file of accepted findings, only the findings which aren't in the baseline are reported and fail the scan")
+	ptrWriteBaselineFile          = flag.String("write-baseline", "", "Write the findings of the scan to this baseline file instead of reporting them")
+	ptrAllowlistFile              = flag.String("allowlist", "", "File of known safe values, e.g. the fake keys of test fixtures, one value or sha256:<hash of the value> per line -- the findings of these values are dropped whatever the rule")
+	ptrCustomRulesDir             = flag.String("rules-dir", "", "Directory of additional JSON or YAML rule files, a custom rule replaces the built-in rule with the same code")
+	ptrEntropyBase64Threshold     = flag.Float64("entropy-base64-threshold", earlybird.DefaultEntropyBase64Threshold, "Lowest Shannon entropy of the base64 tokens reported by the entropy module")
+	ptrEntropyHexThreshold        = flag.Float64("entropy-hex-threshold", earlybird.DefaultEntropyHexThreshold, "Lowest Shannon entropy of the hex tokens reported by the entropy module")
+	ptrBinaryThreshold            = flag.Float64("binary-threshold", earlybird.DefaultBinaryThreshold, "Highest ratio of non-printable characters in the first 8KB of a file scanned as text, files above it or containing null bytes are skipped as binary (1 only skips the files containing null bytes)")
+	ptrIncludeExtensions          = flag.String("include-extensions", "", "Comma separated file extensions to scan, e.g. .properties,.yml -- the other files are skipped, defaults to the include_extensions of earlybird.json")
+	ptrExcludeExtensions          = flag.String("exclude-extensions", "", "Comma separated file extensions to skip, e.g. .png,.woff -- takes precedence over --include-extensions, defaults to the exclude_extensions of earlybird.json")
+	ptrEnableRules                = flag.String("enable-rules", "", "Comma separated rule codes to run, e.g. 3001,3002 -- all the rules of the enabled modules run by default")
+	ptrDisableRules               = flag.String("disable-rules", "", "Comma separated rule codes to skip, e.g. 3005 -- takes precedence over --enable-rules")
+	ptrIncludeCategories          = flag.String("include-categories", "", "Comma separated rule categories to run, e.g. pii,password-secret -- the rules of every category run by default")
+	ptrExcludeCategories          = flag.String("exclude-categories", "", "Comma separated rule categories to skip, e.g. inclusivity -- takes precedence over --include-categories")
+	ptrContextLines               = flag.Int("context-lines", 0, "Number of lines before and after each finding to include in the JSON and HTML reports, the secret being masked on the line of the finding")
 	ptrShowFullLine               = flag.Bool("show-full-line", false, "Display the full line where the pattern match was found (warning: this can be dangerous with minified script files)")
 	ptrConfigDir                  = flag.String("config", utils.GetConfigDir(), "Directory where configuration files are stored")
+	ptrOptionsFile                = flag.String("config-file", "earlybird.yaml", "YAML or JSON file of options named after the flags, e.g. 'display-severity: high' -- the flags passed on the command line take precedence, the default file is only read when it exists")
 	ptrRulesOnly                  = flag.Bool("show-rules-only", false, "Display rules that would be run, but do not execute a scan")
+	ptrJoinContinuations          = flag.Bool("join-continuations", false, "Join the lines continued with a trailing backslash, and the YAML block scalars with their key, before scanning them -- the findings are reported on the first line")
+	ptrExplainIgnore              = flag.String("explain-ignore", "", "Print the ignore pattern, and its ignore file, which leaves the file out of the scan, but do not execute a scan")
+	ptrStrict                     = flag.Bool("strict", false, "Fail the scan when a file couldn't be read, or was skipped as too large or binary, as the scan is incomplete")
+	ptrListFiles                  = flag.Bool("list-files", false, "Print the files which would be scanned, after the ignore patterns and the filters, but do not execute a scan")
 	ptrSkipComments               = flag.Bool("skip-comments", false, "Skip scanning comments in files -- applies only to the 'content' module")
 	ptrIgnoreFPRules              = flag.Bool("ignore-fp-rules", false, "Ignore the false positive post-process rules")
 	ptrShowSolutions              = flag.Bool("show-solutions", false, "Display recommended solution for each finding")
 	ptrGitStagedFlag              = flag.Bool("git-staged", false, "Scan only git staged files")
 	ptrGitTrackedFlag             = flag.Bool("git-tracked", false, "Scan only git tracked files")
-	ptrPath                       = flag.String("path", utils.MustGetWD(), "Directory to scan (defaults to CWD) -- ABSOLUTE PATH ONLY")
-	ptrOutputFormat               = flag.String("format", "console", "Output format [ console | json | csv ]")
+	ptrPreCommitFlag              = flag.Bool("pre-commit", false, "Scan the content of the git staged files from the index rather than the working tree, for pre-commit hooks")
+	ptrGitRange                   = flag.String("git-range", "", "Scan only the files changed in a git revision range of the --path repository, e.g. origin/main...HEAD")
+	ptrDiffOnly                   = flag.Bool("diff-only", false, "Scan only the lines added by a unified diff read from the standard input, e.g. 'git diff origin/main... | go-earlybird --diff-only', or by the diff of --git-range -- the findings are reported at their line of the new file")
+	ptrGitHistoryFlag             = flag.Bool("git-history", false, "Scan the files introduced by each commit of the git history of the --path repository, within --git-range if set")
+	ptrGitHistoryDepth            = flag.Int("git-history-depth", 0, "Only scan the last N commits of the git history (0 for all commits)")
+	ptrRelativePaths              = flag.Bool("relative-paths", false, "Report the file paths of the findings relative to --base-dir, e.g. for the CI annotations -- the files outside of it keep their absolute path")
+	ptrBaseDir                    = flag.String("base-dir", "", "Directory the file paths of the findings are relative to, implies --relative-paths (defaults to the --path directory)")
+	ptrOutputFormat               = flag.String("format", "console", "Output format [ console | json | ndjson | csv | sarif | junit | html | sonarqube | gitlab | github ], defaults to github in the GitHub Actions workflows")
 	ptrWithConsole                = flag.Bool("with-console", false, "While using --format, this flag will help to print findings in console")
+	ptrCacheFile                  = flag.String("cache", "", "File caching the findings of each file scanned, the files unchanged since then are not scanned again, e.g. to resume an interrupted scan -- the file holds the unmasked secrets")
+	ptrRefreshCache               = flag.Bool("refresh-cache", false, "Ignore the findings cached in --cache and scan every file again")
+	ptrSlackWebhook               = flag.String("slack-webhook", "", "Slack incoming webhook URL to post the summary of the scan to, best-effort -- prefer the EARLYBIRD_SLACK_WEBHOOK environment variable to keep it out of the shell history")
+	ptrSlackThreshold             = flag.Int("slack-threshold", 1, "Lowest number of findings notified to --slack-webhook")
+	ptrSlackLink                  = flag.String("slack-link", "", "Link to the report in the Slack notification, e.g. the URL of the CI job")
 	ptrOutputFile                 = flag.String("file", "", "Output file -- e.g., 'go-earlybird --file=/home/jdoe/myfile.csv'")
+	ptrOutput                     = flag.String("output", "", "Output file the report is written to instead of the standard output, its missing parent directories are created -- same as --file, e.g. 'go-earlybird --output=reports/earlybird.json'")
+	ptrGzip                       = flag.Bool("gzip", false, "Compress the report of --file with gzip, adding the .gz extension to it -- a --file ending with .gz is always compressed, for the json, ndjson, sarif, sonarqube and gitlab formats")
 	ptrIgnoreFile                 = flag.String("ignorefile", userHomeDir+string(os.PathSeparator)+".ge_ignore", "Patterns File (including wildcards) for files to ignore.  (e.g. *.jpg)")
+	ptrForceInclude               = flag.String("force-include", "", "Comma separated patterns of the paths to scan even when the ignore patterns exclude them, e.g. vendor/acme/** -- takes precedence over the ignore patterns")
+	ptrIgnoreCaseSensitive        = flag.Bool("ignore-case-sensitive", false, "Match ignore patterns case-sensitively, so *.jpg no longer ignores PHOTO.JPG -- by default Thumbs.db and thumbs.db are matched alike")
+	ptrFollowSymlinks             = flag.Bool("follow-symlinks", false, "Follow symlinked directories, each directory is scanned once to protect against symlink loops")
+	ptrModifiedSince              = sinceFlag("modified-since", "Only scan the files modified since this time, a timestamp, e.g. 2024-05-02T10:14:03Z or 2024-05-02, or a duration before now, e.g. 36h or 7d")
+	ptrSkipHidden                 = flag.Bool("skip-hidden", false, "Skip the hidden files and directories, e.g. .env or .aws/ -- they're scanned by default as they often hold secrets")
+	ptrScanGitDir                 = flag.Bool("scan-git-dir", false, "Scan the .git directories, e.g. to look for secrets in the git internals -- they're skipped by default")
+	ptrMaxDepth                   = flag.Int("max-depth", 0, "Maximum number of directory levels below --path to scan, 1 only scans the files directly in --path (0 for no limit)")
 	ptrIgnoreFailure              = flag.Bool("ignore-failure", false, "Avoid the exit code 1 in case of scanner finds valid findings and meets fail threshold")
-	ptrFailSeverityThreshold      = flag.String("fail-severity", cfgreader.Settings.TranslateLevelID(cfgreader.Settings.FailThreshold), "Lowest severity level at which to fail "+levelOptions)
+	ptrFailSeverityThreshold      = flag.String("fail-severity", "", "Lowest severity level at which to fail, defaults to the fail_severity of earlybird.json "+levelOptions)
 	ptrDisplaySeverityThreshold   = flag.String("display-severity", cfgreader.Settings.TranslateLevelID(cfgreader.Settings.DisplayThreshold), "Lowest severity level to display "+levelOptions)
 	ptrDisplayConfidenceThreshold = flag.String("display-confidence", cfgreader.Settings.TranslateLevelID(cfgreader.Settings.DisplayConfidenceThreshold), "Lowest confidence level to display "+levelOptions)
+	ptrMinConfidence              = flag.String("min-confidence", "", "Lowest confidence level of the findings reported by every output format, the findings of a lower confidence are dropped and don't fail the scan, defaults to the min_confidence of earlybird.json "+levelOptions)
 	ptrFailConfidenceThreshold    = flag.String("fail-confidence", cfgreader.Settings.TranslateLevelID(cfgreader.Settings.FailThreshold), "Lowest confidence level at which to fail "+levelOptions)
 	ptrModuleConfigFile           = flag.String("module-config-file", "", "Path to file with per module config settings")
 	ptrDisableHttpKeepAlives      = flag.Bool("disable-keep-alives", false, "To disable keep-alives when running as http Server. By default, keep-alives are always enabled")
@@ -17,10 +17,11 @@
 package core
 
 import (
-	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"net/http"
 	"os"
@@ -35,8 +36,10 @@ import (
 
 	"github.com/americanexpress/earlybird/v4/pkg/api"
 	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/earlybird"
 	"github.com/americanexpress/earlybird/v4/pkg/file"
 	"github.com/americanexpress/earlybird/v4/pkg/git"
+	"github.com/americanexpress/earlybird/v4/pkg/notify"
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
 	configupdate "github.com/americanexpress/earlybird/v4/pkg/update"
 	"github.com/americanexpress/earlybird/v4/pkg/utils"
@@ -57,66 +60,91 @@ func (eb *EarlybirdCfg) GitClone(ptr PTRGitConfig) {
 
 	if *ptr.Project != "" {
 		if *ptr.RepoUser == "" {
-			fmt.Println("Please use the -git-user flag to scan a Git Project or Organisation ")
-			os.Exit(1)
+			utils.Fatal("Please use the -git-user flag to scan a Git Project or Organisation")
 		}
 
 		gitPassword = utils.GetGitURL(ptr.Repo, ptr.RepoUser)
 		scanRepos = git.ReposPerProject(*ptr.Project, *ptr.RepoUser, gitPassword)
 
 		if eb.Config.OutputFormat != "json" && !(*ptrStreamInput) {
-			log.Println("Cloning", len(scanRepos), "Repositories in", utils.GetGitProject(*ptr.Project))
+			utils.InfoLog.Println("Cloning", len(scanRepos), "Repositories in", utils.GetGitProject(*ptr.Project))
 		}
 	}
 
 	// Display the directory or repo being scanned
 	if len(scanRepos) != 0 {
-		if gitPassword == "" {
+		if *ptr.Token != "" {
+			// The token replaces the password, the git hosts accept any user name along with it
+			gitPassword = *ptr.Token
+			if *ptr.RepoUser == "" {
+				*ptr.RepoUser = gitTokenUser
+			}
+		} else if gitPassword == "" {
 			gitPassword = utils.GetGitURL(ptr.Repo, ptr.RepoUser)
 		}
 		var err error
-		if *ptr.RepoUser != "" { // use auth
-			eb.Config.SearchDir, err = git.CloneGitRepos(scanRepos, *ptr.RepoUser, gitPassword, *ptr.RepoBranch, (eb.Config.OutputFormat == "json"))
-		} else {
-			eb.Config.SearchDir, err = git.CloneGitRepos(scanRepos, "", "", "", (eb.Config.OutputFormat == "json")) //Blank no auth
-		}
+		eb.Config.SearchDir, err = git.CloneGitRepos(scanRepos, *ptr.RepoUser, gitPassword, *ptr.RepoBranch, *ptr.Depth, (eb.Config.OutputFormat == "json" || eb.Config.Quiet))
 		if err != nil {
-			log.Println("Failed to clone repository:", err)
-			os.Exit(1)
+			utils.Fatal("Failed to clone repository: ", err)
 		}
+		eb.Config.SearchDirs = nil
 	} else {
-		if eb.Config.OutputFormat != "json" && !(*ptrStreamInput) {
-			log.Println("Scanning directory: ", eb.Config.SearchDir)
+		if eb.Config.OutputFormat != "json" && !(*ptrStreamInput) && !eb.Config.Stdin {
+			if len(eb.Config.SearchDirs) > 1 {
+				utils.InfoLog.Println("Scanning directories: ", strings.Join(eb.Config.SearchDirs, ", "))
+			} else if info, err := os.Stat(eb.Config.SearchDir); err == nil && info.Mode().IsRegular() {
+				utils.InfoLog.Println("Scanning file: ", eb.Config.SearchDir)
+			} else {
+				utils.InfoLog.Println("Scanning directory: ", eb.Config.SearchDir)
+			}
 		}
 	}
 }
 
 // StartHTTP spins up the Earlybird REST API server
 func (eb *EarlybirdCfg) StartHTTP(ptr PTRHTTPConfig) {
-	// Set up http server
-	r := mux.NewRouter()
-	r.HandleFunc("/scan/git", api.GITScan(eb.Config)).Methods("GET")
-	r.HandleFunc("/scan", api.Scan(eb.Config)).Methods("POST")
-	r.HandleFunc("/labels", api.Labels(eb.Config.Version, scan.Labels)).Methods("GET")
-	r.HandleFunc("/categorylabels", api.LabelsPerCategory(eb.Config.Version, scan.Labels)).Methods("GET")
-	r.HandleFunc("/categories", api.Categories(eb.Config.Version, scan.CombinedRules)).Methods("GET")
-	// Catch-all: Serve our JavaScript application's entry-point (index.html) and static assets directly.
-	r.PathPrefix("/").Handler(http.FileServer(http.Dir(userHomeDir + string(os.PathSeparator) + ".eb-wa-build" + string(os.PathSeparator))))
-
 	var serverconfig cfgreader.ServerConfig
 	//Default time out settings
 	serverconfig = cfgreader.ServerConfig{
-		WriteTimeout: 60,
-		ReadTimeout:  60,
-		IdleTimeout:  120,
+		WriteTimeout:   60,
+		ReadTimeout:    60,
+		IdleTimeout:    120,
+		JobTTL:         3600,
+		MaxRunningJobs: 2,
+		RateBurst:      1,
 	}
 
 	if *ptr.HTTPConfig != "" {
 		err := cfgreader.LoadConfig(&serverconfig, *ptr.HTTPConfig)
 		if err != nil {
-			log.Fatal(err)
+			utils.Fatal(err)
 		}
 	}
+	if token := os.Getenv(api.TokenEnv); token != "" {
+		serverconfig.Token = token
+	}
+
+	// Set up http server
+	r := mux.NewRouter()
+	// The scans of uploads are asynchronous, the synchronous scan is kept for the existing clients with ?sync=true
+	jobs := api.NewJobStore(time.Second*time.Duration(serverconfig.JobTTL), serverconfig.MaxRunningJobs)
+	// Polling the scan jobs isn't rate limited, only starting scans is
+	limiter := api.NewRateLimiter(serverconfig.RateLimit, serverconfig.RateBurst)
+	r.HandleFunc("/scan/git", limiter.Limit(api.GITScan(eb.Config))).Methods("GET")
+	r.HandleFunc("/scan", limiter.Limit(api.Scan(eb.Config))).Methods("POST").Queries("sync", "true")
+	r.HandleFunc("/scan", limiter.Limit(api.ScanAsync(eb.Config, jobs))).Methods("POST")
+	r.HandleFunc("/scan/{id}", api.ScanJob(jobs)).Methods("GET")
+	r.HandleFunc("/labels", api.Labels(eb.Config.Version, scan.Labels)).Methods("GET")
+	r.HandleFunc("/categorylabels", api.LabelsPerCategory(eb.Config.Version, scan.Labels)).Methods("GET")
+	r.HandleFunc("/categories", api.Categories(eb.Config.Version, scan.CombinedRules)).Methods("GET")
+	// Catch-all: Serve our JavaScript application's entry-point (index.html) and static assets directly.
+	r.PathPrefix("/").Handler(http.FileServer(http.Dir(userHomeDir + string(os.PathSeparator) + ".eb-wa-build" + string(os.PathSeparator))))
+
+	// The probes are served without the token, Kubernetes doesn't authenticate them
+	root := http.NewServeMux()
+	root.Handle("GET /healthz", api.Healthz())
+	root.Handle("GET /readyz", api.Readyz(scan.Ready))
+	root.Handle("/", api.RequireToken(serverconfig.Token, r))
 
 	srv := &http.Server{
 		Addr: *ptr.HTTP,
@@ -124,23 +152,27 @@ func (eb *EarlybirdCfg) StartHTTP(ptr PTRHTTPConfig) {
 		WriteTimeout: time.Second * time.Duration(serverconfig.WriteTimeout),
 		ReadTimeout:  time.Second * time.Duration(serverconfig.ReadTimeout),
 		IdleTimeout:  time.Second * time.Duration(serverconfig.IdleTimeout),
-		Handler:      r,
+		Handler:      root,
 	}
 
 	// To control whether HTTP keep-alives are enabled or not.
 	srv.SetKeepAlivesEnabled(!*ptrDisableHttpKeepAlives)
 
+	// The token itself is never logged
+	if serverconfig.Token != "" {
+		log.Println("go-earlybird API requires a bearer token")
+	}
 	if *ptr.HTTPS != "" {
 		srv.Addr = *ptr.HTTPS
 		err := http2.ConfigureServer(srv, &http2.Server{})
 		if err != nil {
-			log.Fatal("Failed to configure HTTP server", err)
+			utils.Fatal("Failed to configure HTTP server", err)
 		}
 		log.Println("go-earlybird HTTPS/2 API Listening on", *ptr.HTTPS)
-		log.Fatal(srv.ListenAndServeTLS(*ptr.HTTPSCert, *ptr.HTTPSKey))
+		utils.Fatal(srv.ListenAndServeTLS(*ptr.HTTPSCert, *ptr.HTTPSKey))
 	} else {
 		log.Println("go-earlybird HTTP API Listening on", *ptr.HTTP)
-		log.Fatal(srv.ListenAndServe())
+		utils.Fatal(srv.ListenAndServe())
 	}
 }
 
@@ -148,22 +180,7 @@ func (eb *EarlybirdCfg) StartHTTP(ptr PTRHTTPConfig) {
 // for example { content: 'content.json', ccnumber: 'ccnumber.json' },
 // and generates a list of the available modules in the `rules` directory
 func (eb *EarlybirdCfg) GetRuleModulesMap() (err error) {
-	rulesPath := filepath.Join(eb.Config.ConfigDir, "rules")
-
-	eb.Config.RuleModulesFilenameMap = make(map[string]string)
-
-	err = filepath.Walk(rulesPath, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() {
-			return nil
-		}
-
-		moduleName := strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
-
-		eb.Config.RuleModulesFilenameMap[moduleName] = info.Name()
-		eb.Config.AvailableModules = append(eb.Config.AvailableModules, moduleName)
-
-		return nil
-	})
+	eb.Config.RuleModulesFilenameMap, eb.Config.AvailableModules, err = earlybird.RuleModules(eb.Config.ConfigDir)
 	return err
 }
 
@@ -171,9 +188,25 @@ func (eb *EarlybirdCfg) GetRuleModulesMap() (err error) {
 func (eb *EarlybirdCfg) ConfigInit() {
 	// Set the version from ldflags
 	eb.Config.Version = buildflags.Version
+	eb.Config.Commit = buildflags.Commit
+	eb.Config.BuildDate = buildflags.Date
 	//Load CLI arguments and parse
 	flag.Var(&enableFlags, "enable", "Enable individual scanning modules "+utils.GetDisplayList(eb.Config.AvailableModules))
+	flag.Var(&disableFlags, "disable", "Disable individual scanning modules, the other modules stay enabled -- takes precedence over --enable "+utils.GetDisplayList(eb.Config.AvailableModules))
+	flag.Var(&pathFlags, "path", "Directory or file to scan (defaults to CWD) -- ABSOLUTE PATH ONLY, repeat it to scan several directories into a single report")
 	flag.Parse()
+	// The environment variables, then the options file, set the flags which weren't passed on the command line
+	explicit := explicitFlags(flag.CommandLine)
+	if err := loadEnvOptions(flag.CommandLine, os.Environ(), explicit); err != nil {
+		utils.Fatal("failed to load the environment options ", err)
+	}
+	if err := loadOptionsFile(flag.CommandLine, *ptrOptionsFile, explicit["config-file"], explicit); err != nil {
+		utils.Fatal("failed to load the options file ", err)
+	}
+	// Annotate the findings in the GitHub Actions workflows, unless an output format was chosen
+	if !explicit["format"] && os.Getenv("GITHUB_ACTIONS") == "true" {
+		*ptrOutputFormat = "github"
+	}
 
 	// Print version and exit if version flag used
 	if *ptrVersion {
@@ -186,12 +219,12 @@ func (eb *EarlybirdCfg) ConfigInit() {
 	earlybirdConfigPath := path.Join(eb.Config.ConfigDir, "earlybird.json")
 	err := cfgreader.LoadConfig(&cfgreader.Settings, earlybirdConfigPath)
 	if err != nil {
-		log.Fatal("failed to load Earlybird config", err)
+		utils.Fatal("failed to load Earlybird config", err)
 	}
 
 	err = eb.GetRuleModulesMap()
 	if err != nil {
-		log.Fatal("error getting rule modules", err)
+		utils.Fatal("error getting rule modules", err)
 	}
 
 	//Assign CLI arguments to our global configuration
@@ -199,30 +232,89 @@ func (eb *EarlybirdCfg) ConfigInit() {
 	eb.Config.WorkerCount = *ptrWorkerCount
 	eb.Config.WorkLength = *ptrWorkLength
 	eb.Config.ShowFullLine = *ptrShowFullLine
-	eb.Config.MaxFileSize = *ptrMaxFileSize
+	eb.Config.ContextLines = *ptrContextLines
+	eb.Config.DedupFindings = *ptrDedupFindings
+	eb.Config.Blame = *ptrBlame
+	eb.Config.BaselineFile = *ptrBaselineFile
+	eb.Config.WriteBaselineFile = *ptrWriteBaselineFile
+	if eb.Config.BaselineFile != "" {
+		if eb.Config.Baseline, err = scan.LoadBaseline(eb.Config.BaselineFile); err != nil {
+			utils.Fatal("failed to load baseline file ", err)
+		}
+	}
+	eb.Config.AllowlistFile = *ptrAllowlistFile
+	if eb.Config.AllowlistFile != "" {
+		if eb.Config.Allowlist, err = scan.LoadAllowlist(eb.Config.AllowlistFile); err != nil {
+			utils.Fatal("failed to load allowlist file ", err)
+		}
+	}
+	eb.Config.ColorOutput = *ptrColorOutput
+	eb.Config.MaxFileSize = int64(*ptrMaxFileSize)
+	eb.Config.EntropyBase64Threshold = *ptrEntropyBase64Threshold
+	eb.Config.EntropyHexThreshold = *ptrEntropyHexThreshold
+	eb.Config.BinaryThreshold = *ptrBinaryThreshold
+	eb.Config.MaxArchiveSize = int64(*ptrMaxArchiveSize)
+	eb.Config.MaxFindings = *ptrMaxFindings
+	eb.Config.FileTimeout = *ptrFileTimeout
 	eb.Config.VerboseEnabled = *ptrVerbose
+	eb.Config.UnsafeLog = *ptrUnsafeLog
+	eb.Config.Quiet = *ptrQuiet
+	eb.Config.Progress = *ptrProgress
+	utils.SetQuiet(eb.Config.Quiet)
 	eb.Config.Suppress = *ptrSuppressSecret
+	eb.Config.ShowSecrets = *ptrShowSecrets
 	eb.Config.StrictJKS = *ptrStrictJKS
 	eb.Config.OutputFormat = *ptrOutputFormat
 	eb.Config.WithConsole = *ptrWithConsole
-	eb.Config.OutputFile = *ptrOutputFile
-	eb.Config.SearchDir = *ptrPath
+	outputFile, err := reportFile(*ptrOutputFile, *ptrOutput)
+	if err != nil {
+		utils.Fatal(err)
+	}
+	eb.Config.OutputFile = outputFile
+	if *ptrGzip {
+		if eb.Config.OutputFile, err = gzipOutputFile(eb.Config.OutputFile, eb.Config.OutputFormat); err != nil {
+			utils.Fatal(err)
+		}
+	}
+	eb.Config.CacheFile = *ptrCacheFile
+	eb.Config.RefreshCache = *ptrRefreshCache
+	eb.Config.SlackWebhook = *ptrSlackWebhook
+	eb.Config.SlackThreshold = *ptrSlackThreshold
+	eb.Config.SlackLink = *ptrSlackLink
+	eb.Config.SearchDir, eb.Config.SearchDirs = searchDirs(pathFlags)
+	eb.Config.RelativePaths = *ptrRelativePaths || *ptrBaseDir != ""
+	eb.Config.BaseDir = *ptrBaseDir
 	eb.Config.IgnoreFile = *ptrIgnoreFile
+	eb.Config.IgnoreCaseSensitive = *ptrIgnoreCaseSensitive
+	eb.Config.ForceInclude = utils.ParseList(*ptrForceInclude)
+	eb.Config.FollowSymlinks = *ptrFollowSymlinks
+	eb.Config.MaxDepth = *ptrMaxDepth
+	eb.Config.SkipHidden = *ptrSkipHidden
+	eb.Config.ScanGitDir = *ptrScanGitDir
+	eb.Config.ModifiedSince = ptrModifiedSince.Time
 	eb.Config.IgnoreFailure = *ptrIgnoreFailure
 	eb.Config.GitStream = *ptrGitStreamInput
+	eb.Config.Stdin = *ptrStdin
+	eb.Config.StdinName = *ptrStdinName
+	eb.Config.FilesFrom = *ptrFilesFrom
 	eb.Config.RulesOnly = *ptrRulesOnly
+	eb.Config.ListFiles = *ptrListFiles
+	eb.Config.Strict = *ptrStrict
+	eb.Config.ExplainIgnore = *ptrExplainIgnore
 	eb.Config.SkipComments = *ptrSkipComments
 	eb.Config.IgnoreFPRules = *ptrIgnoreFPRules
 	eb.Config.ShowSolutions = *ptrShowSolutions
+	eb.Config.JoinContinuations = *ptrJoinContinuations
 
 	eb.Config.RulesConfigDir = path.Join(eb.Config.ConfigDir, rulesDir)
 	eb.Config.FalsePositivesConfigDir = path.Join(eb.Config.ConfigDir, falsePositivesDir)
 	eb.Config.LabelsConfigDir = path.Join(eb.Config.ConfigDir, labelsDir)
 	eb.Config.SolutionsConfigDir = path.Join(eb.Config.ConfigDir, solutionsDir)
+	eb.Config.CustomRulesDir = *ptrCustomRulesDir
 
 	// If the streaming IO flag was specified, accept the streaming input
-	if *ptrStreamInput || eb.Config.GitStream {
-		eb.Config.SearchDir = ""
+	if *ptrStreamInput || eb.Config.GitStream || eb.Config.Stdin {
+		eb.Config.SearchDir, eb.Config.SearchDirs = "", nil
 	}
 	// Check to see if the user opted to update config.  If they choose this option
 	// the configuration files will be updated and load config again.
@@ -230,23 +322,50 @@ func (eb *EarlybirdCfg) ConfigInit() {
 		doUpdate(eb.Config.ConfigDir, eb.Config.RulesConfigDir, earlybirdConfigPath, cfgreader.Settings.ConfigFileURL, eb.Config.RuleModulesFilenameMap)
 		err = cfgreader.LoadConfig(&cfgreader.Settings, earlybirdConfigPath)
 		if err != nil {
-			log.Fatal("failed to load Earlybird config", err)
+			utils.Fatal("failed to load Earlybird config", err)
 		}
 	}
 
 	// Set the skip options (what not to scan) from configs
 	eb.Config.AnnotationsToSkipLine = cfgreader.Settings.AnnotationsToSkip
 	eb.Config.ExtensionsToSkipScan = cfgreader.Settings.ExtensionsToSkipTextScan
+	eb.Config.BinaryScanExtensions = cfgreader.Settings.BinaryScanExtensions
+	eb.Config.ConfidenceKeywords = cfgreader.Settings.ConfidenceKeywords
+	eb.Config.MaxMatchLength = cfgreader.Settings.MaxMatchLength
+	eb.Config.DropLongMatches = cfgreader.Settings.DropLongMatches
+	eb.Config.IncludeExtensions = extensionList(*ptrIncludeExtensions, cfgreader.Settings.IncludeExtensions)
+	eb.Config.ExcludeExtensions = extensionList(*ptrExcludeExtensions, cfgreader.Settings.ExcludeExtensions)
 	// Determine which results to show and which to fail on
 	eb.Config.SeverityDisplayLevel = cfgreader.Settings.TranslateLevelName(*ptrDisplaySeverityThreshold)
-	eb.Config.SeverityFailLevel = cfgreader.Settings.TranslateLevelName(*ptrFailSeverityThreshold)
+	if eb.Config.SeverityFailLevel, err = cfgreader.Settings.GetFailSeverityLevel(*ptrFailSeverityThreshold); err != nil {
+		utils.Fatal("failed to set the fail severity ", err)
+	}
 	// Determine which results to show and which to fail on based on confidence
 	eb.Config.ConfidenceDisplayLevel = cfgreader.Settings.TranslateLevelName(*ptrDisplayConfidenceThreshold)
 	eb.Config.ConfidenceFailLevel = cfgreader.Settings.TranslateLevelName(*ptrFailConfidenceThreshold)
+	if eb.Config.MinConfidence, err = cfgreader.Settings.GetMinConfidenceLevel(*ptrMinConfidence); err != nil {
+		utils.Fatal("failed to set the minimum confidence ", err)
+	}
 	// Let's see if we have specified git tracked/staged files
-	eb.Config.TargetType = utils.GetTargetType(*ptrGitStagedFlag, *ptrGitTrackedFlag)
-	eb.Config.EnabledModulesMap = utils.GetEnabledModulesMap(enableFlags, eb.Config.RuleModulesFilenameMap)
+	eb.Config.GitRange = *ptrGitRange
+	eb.Config.GitHistoryDepth = *ptrGitHistoryDepth
+	eb.Config.DiffOnly = *ptrDiffOnly
+	eb.Config.TargetType = utils.GetTargetType(*ptrGitStagedFlag, *ptrGitTrackedFlag, *ptrPreCommitFlag, *ptrGitHistoryFlag, eb.Config.GitRange)
+	if eb.Config.EnabledModulesMap, err = utils.SelectModules(enableFlags, disableFlags, eb.Config.RuleModulesFilenameMap); err != nil {
+		utils.Fatal("failed to select the rule modules ", err)
+	}
+	if eb.Config.EnabledRuleCodes, err = utils.ParseCodes(*ptrEnableRules); err != nil {
+		utils.Fatal("failed to parse --enable-rules ", err)
+	}
+	if eb.Config.DisabledRuleCodes, err = utils.ParseCodes(*ptrDisableRules); err != nil {
+		utils.Fatal("failed to parse --disable-rules ", err)
+	}
+	eb.Config.IncludeCategories = utils.ParseList(*ptrIncludeCategories)
+	eb.Config.ExcludeCategories = utils.ParseList(*ptrExcludeCategories)
 	eb.Config.AdjustedSeverityCategories = cfgreader.Settings.AdjustedSeverityCategories
+	if eb.Config.SeverityOverrides, err = cfgreader.Settings.GetSeverityOverrides(); err != nil {
+		utils.Fatal("failed to load rule severity overrides ", err)
+	}
 
 	var enabledModuleNames []string
 	for moduleName := range eb.Config.EnabledModulesMap {
@@ -266,7 +385,7 @@ func (eb *EarlybirdCfg) LoadModuleConfig(moduleConfigFilePath string) {
 	err := cfgreader.LoadConfig(&eb.Config.ModuleConfigs, moduleConfigFilePath)
 
 	if err != nil {
-		log.Fatal("Error loading module config file", err)
+		utils.Fatal("Error loading module config file", err)
 	}
 }
 
@@ -293,56 +412,251 @@ func (eb *EarlybirdCfg) getDefaultModuleSettings() {
 
 // Scan Runs the scan by kicking off the different modules as go routines
 func (eb *EarlybirdCfg) Scan() {
-	// Validate the path passed in as the target directory to scan
+	if eb.Config.ExplainIgnore != "" {
+		if err := eb.ExplainIgnore(os.Stdout); err != nil {
+			eb.fatal("Failed to explain the ignore patterns: ", err)
+		}
+		return
+	}
+	if eb.Config.ListFiles {
+		if err := eb.ListFiles(os.Stdout); err != nil {
+			eb.fatal("Failed to list the files: ", err)
+		}
+		utils.DeleteGit(eb.Config.Gitrepo, eb.Config.SearchDir)
+		return
+	}
+	if err := createReportDir(eb.Config.OutputFile); err != nil {
+		eb.fatal("Failed to create the directory of the report file: ", err)
+	}
 	start := time.Now()
-	fileContext, err := eb.FileContext()
+	cfg := eb.Config
+	if cfg.WriteBaselineFile != "" {
+		// The baseline is made of the findings as they are found, with the paths of their files
+		cfg.DedupFindings, cfg.RelativePaths = false, false
+	}
+	// The newline-delimited JSON findings are written while they're found rather than once the scan is over
+	streamed := cfg.OutputFormat == "ndjson" && cfg.WriteBaselineFile == ""
+	var (
+		result        earlybird.Result
+		err, writeErr error
+	)
+	if streamed {
+		result, err, writeErr = eb.streamResults(&cfg)
+	} else {
+		result, err = earlybird.Scan(context.Background(), earlybird.Options{Config: &cfg})
+	}
 	if err != nil {
-		log.Fatal("Failed to get FileContext: ", err)
+		eb.fatal("Failed to scan: ", err)
+	}
+	// The scan didn't finish without its report, whatever its findings
+	if writeErr != nil {
+		eb.fatal("Writing Results failed: ", writeErr)
+	}
+	eb.Config.FailScan = result.Failed
+	fileContext := result.Files
+	HitChannel := hitChannel(result.Hits)
+	if eb.Config.WriteBaselineFile != "" {
+		if err := scan.WriteBaseline(eb.Config.WriteBaselineFile, HitChannel, &eb.Config); err != nil {
+			eb.fatal("Failed to write baseline file: ", err)
+		}
+		utils.InfoLog.Println("Baseline written to", eb.Config.WriteBaselineFile)
+		utils.DeleteGit(eb.Config.Gitrepo, eb.Config.SearchDir)
+		return
 	}
-	HitChannel := make(chan scan.Hit)
-	go scan.SearchFiles(&eb.Config, fileContext.Files, fileContext.CompressPaths, fileContext.ConvertPaths, HitChannel)
 
-	// Send output to a writer
-	eb.WriteResults(start, HitChannel, fileContext)
+	// Count the findings written for the Slack notification
+	var notified []scan.Hit
+	if eb.Config.SlackWebhook != "" && !streamed {
+		HitChannel = notify.Collect(HitChannel, &notified)
+	}
+
+	// Send output to a writer, the streamed findings are already written
+	if !streamed {
+		if err := eb.WriteResults(start, HitChannel, fileContext); err != nil {
+			eb.fatal("Writing Results failed: ", err)
+		}
+	}
+
+	if eb.Config.SlackWebhook != "" {
+		summary := result.Summary
+		if !streamed {
+			summary = fileContext.Summary(notified)
+		}
+		// The notification is best-effort, it doesn't fail the scan
+		if err := notify.Slack(summary, eb.Config); err != nil {
+			log.Println("Slack notification failed:", err)
+		}
+	}
 
 	utils.DeleteGit(eb.Config.Gitrepo, eb.Config.SearchDir)
+	if fileContext.Truncated && !eb.Config.Quiet {
+		fmt.Fprintf(os.Stderr, "Scan stopped at the first %d findings of --max-findings -- the report is truncated.\n", eb.Config.MaxFindings)
+	}
 	if eb.Config.FailScan {
-		if eb.Config.OutputFormat == "console" {
-			fmt.Fprintln(os.Stderr, "Scan detected findings above the accepted threshold -- Failing.")
-		}
-		if !eb.Config.IgnoreFailure {
-			os.Exit(1)
+		if eb.Config.OutputFormat == "console" && !eb.Config.Quiet {
+			// The configuration of the scan only fails for the findings, the incomplete files fail the result
+			if cfg.FailScan {
+				fmt.Fprintln(os.Stderr, "Scan detected findings above the accepted threshold -- Failing.")
+			}
+			if incomplete := result.Summary.FilesIncomplete; eb.Config.Strict && incomplete > 0 {
+				fmt.Fprintf(os.Stderr, "%d files couldn't be read or were skipped as too large or binary -- Failing in strict mode.\n", incomplete)
+			}
 		}
 	}
+	if code := exitCode(eb.Config); code != utils.ExitClean {
+		os.Exit(code)
+	}
 }
 
-// FileContext provides an inclusive file system context of our scan
-func (eb *EarlybirdCfg) FileContext() (fileContext file.Context, err error) {
-	cfg := eb.Config
-	if cfg.SearchDir != "" {
-		// We're going to load a 'files' slice based on the CLI args
-		switch cfg.TargetType {
-		case utils.Tracked:
-			return file.GetGitFiles(utils.Tracked, &cfg)
-		case utils.Staged:
-			return file.GetGitFiles(utils.Staged, &cfg)
-		default:
-			return file.GetFiles(cfg.SearchDir, cfg.IgnoreFile, cfg.VerboseEnabled, cfg.MaxFileSize)
+// fatal deletes the repository cloned for the scan, which exiting would leave behind, then exits like log.Fatal
+func (eb *EarlybirdCfg) fatal(v ...interface{}) {
+	utils.DeleteGit(eb.Config.Gitrepo, eb.Config.SearchDir)
+	utils.Fatal(v...)
+}
+
+// ListFiles prints the files which would be scanned, one per line, once the ignore patterns and the filters are applied,
+// without running the rules.  With verbose, the files skipped are logged with the reason they were skipped.
+func (eb *EarlybirdCfg) ListFiles(w io.Writer) error {
+	fileContext, err := eb.FileContext()
+	if err != nil {
+		return err
+	}
+	defer scan.DeleteFiles(fileContext.CompressPaths)
+	defer scan.DeleteFiles(fileContext.ConvertPaths)
+	for _, f := range fileContext.Files {
+		// The plain text copies of the converted documents are listed as their document
+		if utils.Contains(fileContext.ConvertPaths, filepath.Dir(f.Path)) {
+			continue
+		}
+		if _, err := fmt.Fprintln(w, f.Path); err != nil {
+			return err
 		}
 	}
-	if cfg.GitStream {
-		var err error
-		fileContext.Files, err = git.ParseGitLog(bufio.NewReader(os.Stdin))
-		if err != nil {
-			return fileContext, err
+	if eb.Config.VerboseEnabled {
+		for _, skipped := range fileContext.SkippedFiles {
+			utils.InfoLog.Printf("Skipping %s (%s)\n", skipped, fileContext.SkipReasons[skipped])
+		}
+	}
+	return nil
+}
+
+// ExplainIgnore prints whether the file of the configuration is left out of the scan by the ignore patterns, and the
+// pattern and the ignore file deciding it
+func (eb *EarlybirdCfg) ExplainIgnore(w io.Writer) error {
+	explained, err := file.ExplainIgnore(&eb.Config, eb.Config.ExplainIgnore)
+	if err != nil {
+		return err
+	}
+	switch {
+	case explained.Ignored:
+		_, err = fmt.Fprintf(w, "%s is ignored by the pattern %q of %s, matching %s\n", explained.Path, explained.Pattern, explained.Source, explained.Match)
+	case explained.Pattern != "":
+		_, err = fmt.Fprintf(w, "%s isn't ignored, it's re-included by the pattern %q of %s\n", explained.Path, explained.Pattern, explained.Source)
+	default:
+		_, err = fmt.Fprintf(w, "%s isn't ignored, no ignore pattern matches it\n", explained.Path)
+	}
+	return err
+}
+
+// hitChannel sends the findings of the scan to the writers
+func hitChannel(hits []scan.Hit) chan scan.Hit {
+	hitChannel := make(chan scan.Hit)
+	go func() {
+		defer close(hitChannel)
+		for _, hit := range hits {
+			hitChannel <- hit
 		}
+	}()
+	return hitChannel
+}
+
+// exitCode returns the exit code of the scan, utils.ExitFindings when a finding is at or above the fail severity and
+// confidence
+func exitCode(cfg cfgreader.EarlybirdConfig) int {
+	if cfg.FailScan && !cfg.IgnoreFailure {
+		return utils.ExitFindings
 	}
-	fileContext.Files = file.GetFileFromStream(&cfg)
-	return fileContext, nil
+	return utils.ExitClean
 }
 
-// WriteResults reads hits from the channel to the console or target file
-func (eb *EarlybirdCfg) WriteResults(start time.Time, HitChannel chan scan.Hit, fileContext file.Context) {
+// reportFile returns the report file of --file or --output, which are the same option
+func reportFile(file, output string) (string, error) {
+	if file != "" && output != "" && file != output {
+		return "", fmt.Errorf("--file %s and --output %s are different report files, pass only one of them", file, output)
+	}
+	if output != "" {
+		return output, nil
+	}
+	return file, nil
+}
+
+// createReportDir creates the missing parent directories of the report file, so the writers can create it
+func createReportDir(outputFile string) error {
+	if outputFile == "" {
+		return nil
+	}
+	return os.MkdirAll(filepath.Dir(outputFile), 0755)
+}
+
+// gzipOutputFile returns the report file of --gzip, the report file of the configuration with the .gz extension which
+// the writers compress
+func gzipOutputFile(outputFile, format string) (string, error) {
+	if outputFile == "" {
+		return "", errors.New("--gzip needs the report file of --file")
+	}
+	if !utils.Contains(gzipFormats, format) {
+		return "", fmt.Errorf("--gzip doesn't apply to the %s format, only to %s", format, strings.Join(gzipFormats, ", "))
+	}
+	if !strings.HasSuffix(outputFile, writers.GzipExtension) {
+		outputFile += writers.GzipExtension
+	}
+	return outputFile, nil
+}
+
+// searchDirs returns the directory to scan, the working directory by default, or the common parent directory of the
+// directories to scan when several are passed, along with them
+func searchDirs(paths []string) (searchDir string, roots []string) {
+	switch len(paths) {
+	case 0:
+		return utils.MustGetWD(), nil
+	case 1:
+		return paths[0], nil
+	}
+	return utils.CommonDir(paths), paths
+}
+
+// extensionList returns the file extensions of the comma separated CLI list, or the extensions of earlybird.json when the
+// flag isn't set
+func extensionList(cliList string, configList []string) []string {
+	if cliList == "" {
+		cliList = strings.Join(configList, ",")
+	}
+	return utils.ParseExtensions(cliList)
+}
+
+// FileContext provides an inclusive file system context of our scan
+func (eb *EarlybirdCfg) FileContext() (fileContext file.Context, err error) {
+	return earlybird.FileContext(context.Background(), eb.Config)
+}
+
+// streamResults scans with the findings written as newline-delimited JSON while they're found, so they aren't held in
+// memory until the end of the scan.  It returns the error of the scan and the error of writing the findings.
+func (eb *EarlybirdCfg) streamResults(cfg *cfgreader.EarlybirdConfig) (result earlybird.Result, err, writeErr error) {
+	hits := make(chan scan.Hit)
+	written := make(chan error)
+	go func() {
+		err := writers.WriteNDJSON(hits, eb.Config.OutputFile)
+		// Keep receiving the findings once the writer failed, so the scan still completes
+		for range hits {
+		}
+		written <- err
+	}()
+	result, err = earlybird.Scan(context.Background(), earlybird.Options{Config: cfg, Hits: hits})
+	return result, err, <-written
+}
+
+// WriteResults reads hits from the channel to the console or target file, returning the error of the writer
+func (eb *EarlybirdCfg) WriteResults(start time.Time, HitChannel chan scan.Hit, fileContext file.Context) error {
 	// Send output to a writer
 	var err error
 
@@ -354,39 +668,63 @@ func (eb *EarlybirdCfg) WriteResults(start time.Time, HitChannel chan scan.Hit,
 		broadcaster := broadcast.NewBroadcastServer(ctx, HitChannel)
 		listener1 := broadcaster.Subscribe()
 		listener2 := broadcaster.Subscribe()
+		var consoleErr, jsonErr error
 		go func() {
 			defer wg.Done()
-			err = writers.WriteConsole(listener1, "", eb.Config.ShowFullLine)
-			log.Printf("\n%d files scanned in %s", len(fileContext.Files), time.Since(start))
-			log.Printf("\n%d rules observed\n", len(scan.CombinedRules))
+			consoleErr = writers.WriteConsole(listener1, "", eb.Config.ShowFullLine)
+			utils.InfoLog.Printf("\n%d files scanned in %s", len(fileContext.Files), time.Since(start))
+			utils.InfoLog.Printf("\n%d rules observed\n", len(scan.CombinedRules))
 		}()
 		go func() {
 			defer wg.Done()
-			err = writers.WriteJSON(listener2, eb.Config, fileContext, eb.Config.OutputFile)
+			jsonErr = writers.WriteJSON(listener2, eb.Config, fileContext, eb.Config.OutputFile)
 		}()
 		wg.Wait()
+		// The JSON report is the one written to the report file
+		err = jsonErr
+		if err == nil {
+			err = consoleErr
+		}
 	} else {
+		if eb.Config.VerboseEnabled && eb.Config.OutputFormat != "json" && (eb.Config.OutputFormat != "console" || eb.Config.ColorOutput) {
+			// Only the JSON and console writers list the suppressed findings separately
+			HitChannel = scan.DropSuppressed(HitChannel)
+		}
 		switch {
 		case eb.Config.OutputFormat == "json":
 			err = writers.WriteJSON(HitChannel, eb.Config, fileContext, eb.Config.OutputFile)
 		case eb.Config.OutputFormat == "csv":
 			err = writers.WriteCSV(HitChannel, eb.Config.OutputFile)
+		case eb.Config.OutputFormat == "sarif":
+			err = writers.WriteSARIF(HitChannel, eb.Config, fileContext, eb.Config.OutputFile)
+		case eb.Config.OutputFormat == "junit":
+			err = writers.WriteJUnit(HitChannel, eb.Config.OutputFile)
+		case eb.Config.OutputFormat == "html":
+			err = writers.WriteHTML(HitChannel, eb.Config, eb.Config.OutputFile)
+		case eb.Config.OutputFormat == "sonarqube":
+			err = writers.WriteSonarQube(HitChannel, eb.Config, eb.Config.OutputFile)
+		case eb.Config.OutputFormat == "gitlab":
+			err = writers.WriteGitLab(HitChannel, eb.Config, eb.Config.OutputFile)
+		case eb.Config.OutputFormat == "github":
+			err = writers.WriteGitHub(HitChannel, eb.Config, eb.Config.OutputFile)
+		case eb.Config.ColorOutput:
+			err = writers.WriteColorConsole(HitChannel, eb.Config.OutputFile, len(fileContext.Files), eb.Config.ShowFullLine)
+			utils.InfoLog.Printf("\n%d files scanned in %s", len(fileContext.Files), time.Since(start))
+			utils.InfoLog.Printf("\n%d rules observed\n", len(scan.CombinedRules))
 		default:
 			err = writers.WriteConsole(HitChannel, eb.Config.OutputFile, eb.Config.ShowFullLine)
-			log.Printf("\n%d files scanned in %s", len(fileContext.Files), time.Since(start))
-			log.Printf("\n%d rules observed\n", len(scan.CombinedRules))
+			utils.InfoLog.Printf("\n%d files scanned in %s", len(fileContext.Files), time.Since(start))
+			utils.InfoLog.Printf("\n%d rules observed\n", len(scan.CombinedRules))
 		}
 	}
-	if err != nil {
-		log.Println("Writing Results failed:", err)
-	}
+	return err
 }
 
 // Update configs from the latest in the repo
 func doUpdate(configDir, rulesConfigDir, configPath, appConfigURL string, ruleModulesFilenameMap map[string]string) {
 	err := configupdate.UpdateConfigFiles(configDir, rulesConfigDir, configPath, appConfigURL, ruleModulesFilenameMap)
 	if err != nil {
-		log.Fatal("Failed to update config:", err)
+		utils.Fatal("Failed to update config:", err)
 	}
 	log.Println("Configurations updated.  Exiting")
 }
@@ -17,13 +17,27 @@
 package core
 
 import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
 	"testing"
 
 	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/earlybird"
+	"github.com/americanexpress/earlybird/v4/pkg/file"
+	"github.com/americanexpress/earlybird/v4/pkg/git"
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
 	"github.com/americanexpress/earlybird/v4/pkg/utils"
+	"github.com/americanexpress/earlybird/v4/pkg/writers"
 )
 
 var eb EarlybirdCfg
@@ -56,6 +70,103 @@ func TestEarlybirdCfg_Scan(t *testing.T) {
 	eb.Scan()
 }
 
+func TestEarlybirdCfg_FileContextSearchDirs(t *testing.T) {
+	parent := t.TempDir()
+	app, lib := filepath.Join(parent, "app"), filepath.Join(parent, "lib")
+	for filePath, content := range map[string]string{
+		filepath.Join(app, ".gitignore"):      "*.env\n",
+		filepath.Join(app, "settings.txt"):    "certain_secret\n",
+		filepath.Join(app, "local.env"):       "certain_secret\n",
+		filepath.Join(lib, "config", "a.env"): "certain_secret\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	scan.CombinedRules = []scan.Rule{
+		{Code: 1, Severity: 2, Confidence: 2, Caption: "Secret", CompiledPattern: regexp.MustCompile("certain_secret")},
+	}
+
+	multiRoot := EarlybirdCfg{Config: cfgReader.EarlybirdConfig{
+		SeverityDisplayLevel:   4,
+		ConfidenceDisplayLevel: 4,
+		MaxFileSize:            1000000,
+		WorkLength:             2500,
+		IgnoreFile:             filepath.Join(parent, ".ge_ignore"),
+	}}
+	multiRoot.Config.SearchDir, multiRoot.Config.SearchDirs = searchDirs([]string{app, lib})
+	if multiRoot.Config.SearchDir != parent {
+		t.Fatalf("searchDirs() = %s, want the common parent %s", multiRoot.Config.SearchDir, parent)
+	}
+	fileContext, err := multiRoot.FileContext()
+	if err != nil {
+		t.Fatalf("FileContext() err = %v", err)
+	}
+	hits := make(chan scan.Hit)
+	go scan.SearchFiles(&multiRoot.Config, fileContext.Files, fileContext.CompressPaths, fileContext.ConvertPaths, hits)
+
+	// The .gitignore of app doesn't apply to lib, and the paths are reported relative to the common parent, starting with
+	// the directory scanned
+	var got []string
+	for hit := range hits {
+		if !filepath.IsAbs(hit.Filename) {
+			t.Errorf("SearchFiles() hit file %s, want an absolute path", hit.Filename)
+		}
+		got = append(got, scan.RelativeFileName(hit.Filename, multiRoot.Config.SearchDir))
+	}
+	sort.Strings(got)
+	if want := []string{"app/settings.txt", "lib/config/a.env"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchFiles() found %v, want %v", got, want)
+	}
+	if reason := fileContext.SkipReasons[filepath.Join(app, "local.env")]; reason != "ignored" {
+		t.Errorf("FileContext() skipped app/local.env as %q, want ignored", reason)
+	}
+}
+
+func TestEarlybirdCfg_ListFiles(t *testing.T) {
+	dir := t.TempDir()
+	for filePath, content := range map[string]string{
+		filepath.Join(dir, ".gitignore"):              "*.log\nbuild/\n",
+		filepath.Join(dir, "settings.py"):             "db_password = 'SecretValue1673'\n",
+		filepath.Join(dir, "debug.log"):               "db_password = 'SecretValue1673'\n",
+		filepath.Join(dir, "logo.png"):                "png",
+		filepath.Join(dir, "build", "settings.py"):    "db_password = 'SecretValue1673'\n",
+		filepath.Join(dir, "config", "database.yaml"): "password: SecretValue1673\n",
+		filepath.Join(dir, "config", "large.json"):    strings.Repeat("{}", 1000),
+	} {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	listEB := EarlybirdCfg{Config: cfgReader.EarlybirdConfig{
+		SearchDir:         dir,
+		IgnoreFile:        filepath.Join(dir, ".ge_ignore"),
+		ExcludeExtensions: []string{".png"},
+		MaxFileSize:       1000,
+		ListFiles:         true,
+	}}
+	var output strings.Builder
+	if err := listEB.ListFiles(&output); err != nil {
+		t.Fatalf("ListFiles() err = %v", err)
+	}
+	want := []string{
+		filepath.Join(dir, ".gitignore"),
+		filepath.Join(dir, "config", "database.yaml"),
+		filepath.Join(dir, "settings.py"),
+	}
+	if got := strings.Split(strings.TrimSpace(output.String()), "\n"); !reflect.DeepEqual(got, want) {
+		t.Errorf("ListFiles() = %v, want %v", got, want)
+	}
+}
+
 func TestEarlybirdCfg_GitClone(t *testing.T) {
 	if os.Getenv("local") == "" {
 		t.Skip("If test cases not running locally, skip cloning external repositories for CI/CD purposes.")
@@ -65,11 +176,15 @@ func TestEarlybirdCfg_GitClone(t *testing.T) {
 		FakeRepo = "https://github.com/carnal0wnage/fake_commited_secrets"
 		RepoUser string
 		Project  string
+		Token    string
+		Depth    = git.DefaultCloneDepth
 	)
 	ptr := PTRGitConfig{
 		Repo:     &FakeRepo,
 		RepoUser: &RepoUser,
 		Project:  &Project,
+		Token:    &Token,
+		Depth:    &Depth,
 	}
 
 	eb.GitClone(ptr)
@@ -95,3 +210,544 @@ func TestEarlybirdCfg_getDefaultModuleSettings(t *testing.T) {
 
 	cleanup()
 }
+
+func TestExitCode(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "settings.txt")
+	if err := os.WriteFile(filePath, []byte("high_secret\nlow_secret\nhigh_guess\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	scan.CombinedRules = []scan.Rule{
+		{Code: 1, Severity: 2, Confidence: 2, Caption: "High severity", CompiledPattern: regexp.MustCompile("high_secret")},
+		{Code: 2, Severity: 4, Confidence: 2, Caption: "Low severity", CompiledPattern: regexp.MustCompile("low_secret")},
+		{Code: 3, Severity: 2, Confidence: 4, Caption: "High severity, low confidence", CompiledPattern: regexp.MustCompile("high_guess")},
+	}
+
+	tests := []struct {
+		name           string
+		codes          []int
+		failSeverity   int
+		failConfidence int
+		ignoreFailure  bool
+		want           int
+	}{
+		{
+			name:           "Finding at the fail severity",
+			codes:          []int{1, 2},
+			failSeverity:   2,
+			failConfidence: 4,
+			want:           utils.ExitFindings,
+		},
+		{
+			name:           "Findings below the fail severity",
+			codes:          []int{2},
+			failSeverity:   3,
+			failConfidence: 4,
+			want:           0,
+		},
+		{
+			name:           "Finding above the fail severity",
+			codes:          []int{1},
+			failSeverity:   4,
+			failConfidence: 4,
+			want:           utils.ExitFindings,
+		},
+		{
+			name:           "Finding at the fail severity below the fail confidence",
+			codes:          []int{3},
+			failSeverity:   2,
+			failConfidence: 2,
+			want:           0,
+		},
+		{
+			name:           "Failure ignored",
+			codes:          []int{1},
+			failSeverity:   2,
+			failConfidence: 4,
+			ignoreFailure:  true,
+			want:           0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var rules []scan.Rule
+			for _, rule := range scan.CombinedRules {
+				for _, code := range tt.codes {
+					if rule.Code == code {
+						rules = append(rules, rule)
+					}
+				}
+			}
+			defer func(all []scan.Rule) { scan.CombinedRules = all }(scan.CombinedRules)
+			scan.CombinedRules = rules
+
+			cfg := cfgReader.EarlybirdConfig{
+				SeverityFailLevel:      tt.failSeverity,
+				ConfidenceFailLevel:    tt.failConfidence,
+				SeverityDisplayLevel:   4,
+				ConfidenceDisplayLevel: 4,
+				IgnoreFailure:          tt.ignoreFailure,
+				MaxFileSize:            1000000,
+				WorkLength:             2500,
+				WorkerCount:            1,
+			}
+			hits := make(chan scan.Hit)
+			go scan.SearchFiles(&cfg, []scan.File{{Name: filePath, Path: filePath}}, nil, nil, hits)
+			for range hits {
+			}
+			if got := exitCode(cfg); got != tt.want {
+				t.Errorf("exitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// exitScenarioEnv runs the scan of the exit code scenario named by its value in the process of TestScanExitCodes
+const exitScenarioEnv = "EB_TEST_EXIT_SCENARIO"
+
+func TestScanExitCodes(t *testing.T) {
+	if scenario := os.Getenv(exitScenarioEnv); scenario != "" {
+		scenarioDir := os.Getenv(exitScenarioEnv + "_DIR")
+		scan.CombinedRules = []scan.Rule{{Code: 1, Severity: 2, Confidence: 2, Caption: "High severity", CompiledPattern: regexp.MustCompile("high_secret")}}
+		exiting := EarlybirdCfg{Config: cfgReader.EarlybirdConfig{
+			SearchDir:              filepath.Join(scenarioDir, scenario),
+			SeverityFailLevel:      2,
+			ConfidenceFailLevel:    4,
+			SeverityDisplayLevel:   4,
+			ConfidenceDisplayLevel: 4,
+			OutputFormat:           "json",
+			MaxFileSize:            1000000,
+			WorkLength:             2500,
+			WorkerCount:            1,
+		}}
+		if format, unwritable := strings.CutPrefix(scenario, "unwritable-"); unwritable {
+			// The report file is a directory, the findings of the scan can't be written to it
+			exiting.Config.SearchDir = filepath.Join(scenarioDir, "findings")
+			exiting.Config.OutputFormat = format
+			exiting.Config.OutputFile = filepath.Join(scenarioDir, "clean")
+		}
+		exiting.Scan()
+		return
+	}
+
+	dir := t.TempDir()
+	for filePath, content := range map[string]string{
+		filepath.Join(dir, "clean", "app.py"):    "print('hello')\n",
+		filepath.Join(dir, "findings", "app.py"): "token = 'high_secret'\n",
+	} {
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		scenario   string
+		want       int
+		wantOutput string
+	}{
+		{scenario: "clean", want: utils.ExitClean},
+		{scenario: "findings", want: utils.ExitFindings, wantOutput: `"code": 1`},
+		// The directory to scan can't be read, a panic would exit with the same code
+		{scenario: "missing", want: utils.ExitError, wantOutput: "Failed to scan"},
+		// The report is part of the scan, the findings aren't reported with exit code 1 when it can't be written
+		{scenario: "unwritable-json", want: utils.ExitError, wantOutput: "Writing Results failed"},
+		{scenario: "unwritable-ndjson", want: utils.ExitError, wantOutput: "Writing Results failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scenario, func(t *testing.T) {
+			// The scan exits the process, run it in a process of its own
+			cmd := exec.Command(os.Args[0], "-test.run=^TestScanExitCodes$")
+			cmd.Env = append(os.Environ(), exitScenarioEnv+"="+tt.scenario, exitScenarioEnv+"_DIR="+dir)
+			output, err := cmd.CombinedOutput()
+			got := 0
+			if exitErr, ok := err.(*exec.ExitError); ok {
+				got = exitErr.ExitCode()
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want || !strings.Contains(string(output), tt.wantOutput) {
+				t.Errorf("Scan() of %s exited with %d, want %d\n%s", tt.scenario, got, tt.want, output)
+			}
+		})
+	}
+}
+
+// testWriters writes the hits with each output format to the file
+var testWriters = map[string]func(hits <-chan scan.Hit, cfg cfgReader.EarlybirdConfig, fileName string) error{
+	"console": func(hits <-chan scan.Hit, cfg cfgReader.EarlybirdConfig, fileName string) error {
+		return writers.WriteConsole(hits, fileName, true)
+	},
+	"csv": func(hits <-chan scan.Hit, cfg cfgReader.EarlybirdConfig, fileName string) error {
+		return writers.WriteCSV(hits, fileName)
+	},
+	"html": writers.WriteHTML,
+	"json": func(hits <-chan scan.Hit, cfg cfgReader.EarlybirdConfig, fileName string) error {
+		return writers.WriteJSON(hits, cfg, file.Context{}, fileName)
+	},
+	"junit": func(hits <-chan scan.Hit, cfg cfgReader.EarlybirdConfig, fileName string) error {
+		return writers.WriteJUnit(hits, fileName)
+	},
+	"sarif": func(hits <-chan scan.Hit, cfg cfgReader.EarlybirdConfig, fileName string) error {
+		return writers.WriteSARIF(hits, cfg, file.Context{}, fileName)
+	},
+	"sonarqube": writers.WriteSonarQube,
+}
+
+func TestMinConfidence(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "settings.txt")
+	if err := os.WriteFile(filePath, []byte("certain_secret\nguessed_secret\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	scan.CombinedRules = []scan.Rule{
+		{Code: 1, Severity: 4, Confidence: 2, Caption: "High confidence", CompiledPattern: regexp.MustCompile("certain_secret")},
+		{Code: 2, Severity: 1, Confidence: 4, Caption: "Low confidence", CompiledPattern: regexp.MustCompile("guessed_secret")},
+		{Code: 3, Severity: 1, Confidence: 4, Caption: "Low confidence file name", Searcharea: "filename", CompiledPattern: regexp.MustCompile("settings")},
+	}
+
+	for _, format := range []string{"json", "console"} {
+		write := testWriters[format]
+		t.Run(format, func(t *testing.T) {
+			cfg := cfgReader.EarlybirdConfig{
+				SeverityFailLevel:      1,
+				ConfidenceFailLevel:    4,
+				SeverityDisplayLevel:   4,
+				ConfidenceDisplayLevel: 4,
+				MinConfidence:          2,
+				MaxFileSize:            1000000,
+				WorkLength:             2500,
+				WorkerCount:            1,
+			}
+			hits := make(chan scan.Hit)
+			go scan.SearchFiles(&cfg, []scan.File{{Name: filePath, Path: filePath}}, nil, nil, hits)
+			outputFile := filepath.Join(dir, "report."+format)
+			if err := write(hits, cfg, outputFile); err != nil {
+				t.Fatal(err)
+			}
+			output, err := os.ReadFile(outputFile)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(output), "High confidence") {
+				t.Errorf("output is missing the high confidence finding:\n%s", output)
+			}
+			if strings.Contains(string(output), "Low confidence") {
+				t.Errorf("output reports a low confidence finding:\n%s", output)
+			}
+			if cfg.FailScan {
+				t.Error("low confidence findings failed the scan")
+			}
+		})
+	}
+}
+
+func TestMaskedSecrets(t *testing.T) {
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "settings.txt")
+	if err := os.WriteFile(filePath, []byte("db_password=SecretValue1673\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	scan.CombinedRules = []scan.Rule{
+		{Code: 1, Severity: 2, Confidence: 2, Caption: "Password", CompiledPattern: regexp.MustCompile("password=SecretValue1673")},
+	}
+
+	for format, write := range testWriters {
+		for _, showSecrets := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s show secrets %v", format, showSecrets), func(t *testing.T) {
+				cfg := cfgReader.EarlybirdConfig{
+					SeverityFailLevel:      1,
+					ConfidenceFailLevel:    4,
+					SeverityDisplayLevel:   4,
+					ConfidenceDisplayLevel: 4,
+					ShowSecrets:            showSecrets,
+					MaxFileSize:            1000000,
+					WorkLength:             2500,
+					WorkerCount:            1,
+				}
+				hits := make(chan scan.Hit)
+				go scan.SearchFiles(&cfg, []scan.File{{Name: filePath, Path: filePath}}, nil, nil, hits)
+				outputFile := filepath.Join(dir, "report."+format)
+				if err := write(hits, cfg, outputFile); err != nil {
+					t.Fatal(err)
+				}
+				output, err := os.ReadFile(outputFile)
+				if err != nil {
+					t.Fatal(err)
+				}
+				// The SARIF and SonarQube reports don't include the value
+				wantSecret := showSecrets && format != "sarif" && format != "sonarqube"
+				if got := strings.Contains(string(output), "SecretValue1673"); got != wantSecret {
+					t.Errorf("output contains the secret = %v, want %v:\n%s", got, wantSecret, output)
+				}
+			})
+		}
+	}
+}
+
+func TestQuiet(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "settings.txt"), []byte("db_password=SecretValue1673\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	defer func(stdout, stderr *os.File) { os.Stdout, os.Stderr = stdout, stderr }(os.Stdout, os.Stderr)
+	defer utils.SetQuiet(false)
+
+	for _, quiet := range []bool{false, true} {
+		t.Run(fmt.Sprintf("quiet %v", quiet), func(t *testing.T) {
+			quietEB := EarlybirdCfg{Config: cfgReader.EarlybirdConfig{
+				SearchDir:               dir,
+				IgnoreFile:              filepath.Join(dir, ".ge_ignore"),
+				OutputFormat:            "console",
+				LabelsConfigDir:         filepath.Join(utils.MustGetWD(), "../../config/labels"),
+				FalsePositivesConfigDir: filepath.Join(utils.MustGetWD(), "../../config/falsepositives"),
+				SeverityFailLevel:       4,
+				ConfidenceFailLevel:     4,
+				SeverityDisplayLevel:    4,
+				ConfidenceDisplayLevel:  4,
+				IgnoreFailure:           true,
+				VerboseEnabled:          true,
+				Quiet:                   quiet,
+				MaxFileSize:             1000000,
+				WorkLength:              2500,
+				WorkerCount:             1,
+			}}
+			stdout, stderr := captureOutput(t, func() {
+				utils.SetQuiet(quietEB.Config.Quiet)
+				scan.Init(quietEB.Config)
+				scan.CombinedRules = []scan.Rule{
+					{Code: 1, Severity: 2, Confidence: 2, Caption: "Password in file", CompiledPattern: regexp.MustCompile("password=\\w+")},
+				}
+				quietEB.Scan()
+			})
+			if !strings.Contains(stdout, "Password in file") {
+				t.Errorf("Scan() stdout is missing the finding:\n%s", stdout)
+			}
+			if got := strings.Contains(stdout, "threshold"); got == quiet {
+				t.Errorf("Scan() stdout shows the thresholds = %v, want %v:\n%s", got, !quiet, stdout)
+			}
+			if got := strings.Contains(stderr, "Reading file"); got == quiet {
+				t.Errorf("Scan() stderr shows the files read = %v, want %v:\n%s", got, !quiet, stderr)
+			}
+		})
+	}
+}
+
+// captureOutput returns what run printed to stdout and stderr
+func captureOutput(t *testing.T, run func()) (stdout, stderr string) {
+	t.Helper()
+	read := func(target **os.File) func() string {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		original := *target
+		*target = w
+		output := make(chan string)
+		go func() {
+			b, _ := io.ReadAll(r)
+			output <- string(b)
+		}()
+		return func() string {
+			w.Close()
+			*target = original
+			return <-output
+		}
+	}
+	stopStdout, stopStderr := read(&os.Stdout), read(&os.Stderr)
+	run()
+	return stopStdout(), stopStderr()
+}
+
+func TestExitCodeStrict(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "settings.txt"), []byte("nothing to report\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// The entry of the archive is compressed with a method which can't be decompressed, so it can't be read
+	archive, err := os.Create(filepath.Join(dir, "bundle.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := zip.NewWriter(archive)
+	entry, err := w.CreateRaw(&zip.FileHeader{Name: "app.env", Method: 99, CompressedSize64: 4, UncompressedSize64: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := entry.Write([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive.Close()
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	scan.CombinedRules = []scan.Rule{{Code: 1, Severity: 2, Confidence: 2, Caption: "High severity", CompiledPattern: regexp.MustCompile("high_secret")}}
+
+	tests := []struct {
+		name          string
+		strict        bool
+		ignoreFailure bool
+		want          int
+	}{
+		{
+			name: "Unreadable file in lenient mode",
+			want: 0,
+		},
+		{
+			name:   "Unreadable file in strict mode",
+			strict: true,
+			want:   utils.ExitFindings,
+		},
+		{
+			name:          "Failure ignored in strict mode",
+			strict:        true,
+			ignoreFailure: true,
+			want:          0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cfgReader.EarlybirdConfig{
+				SearchDir:              dir,
+				SeverityFailLevel:      2,
+				ConfidenceFailLevel:    4,
+				SeverityDisplayLevel:   4,
+				ConfidenceDisplayLevel: 4,
+				Strict:                 tt.strict,
+				IgnoreFailure:          tt.ignoreFailure,
+				MaxFileSize:            1000000,
+				WorkLength:             2500,
+				WorkerCount:            1,
+			}
+			result, err := earlybird.Scan(context.Background(), earlybird.Options{Config: &cfg})
+			if err != nil {
+				t.Fatalf("Scan() error = %v", err)
+			}
+			if result.Summary.FilesIncomplete != 1 || result.Summary.SkipReasons["unreadable"] != 1 {
+				t.Errorf("Scan() summary = %+v, want 1 unreadable file", result.Summary)
+			}
+			cfg.FailScan = result.Failed
+			if got := exitCode(cfg); got != tt.want {
+				t.Errorf("exitCode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGzipOutputFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		outputFile string
+		format     string
+		want       string
+		wantErr    bool
+	}{
+		{
+			name:       "Extension added",
+			outputFile: "/reports/earlybird.json",
+			format:     "json",
+			want:       "/reports/earlybird.json.gz",
+		},
+		{
+			name:       "Extension already there",
+			outputFile: "/reports/earlybird.sarif.gz",
+			format:     "sarif",
+			want:       "/reports/earlybird.sarif.gz",
+		},
+		{
+			name:    "Without report file",
+			format:  "json",
+			wantErr: true,
+		},
+		{
+			name:       "Format which isn't compressed",
+			outputFile: "/reports/earlybird.csv",
+			format:     "csv",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := gzipOutputFile(tt.outputFile, tt.format)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("gzipOutputFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("gzipOutputFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{name: "Standard output"},
+		{name: "File", file: "earlybird.json", want: "earlybird.json"},
+		{name: "Output", output: "reports/earlybird.json", want: "reports/earlybird.json"},
+		{name: "Same file", file: "earlybird.json", output: "earlybird.json", want: "earlybird.json"},
+		{name: "Different files", file: "earlybird.json", output: "reports/earlybird.json", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reportFile(tt.file, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("reportFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("reportFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScanOutputFile(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "app.py"), []byte("token = 'output_secret'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	scan.CombinedRules = []scan.Rule{{Code: 7, Severity: 2, Confidence: 2, Caption: "Output secret", CompiledPattern: regexp.MustCompile("output_secret")}}
+
+	// The parent directories of the report file don't exist yet
+	outputFile := filepath.Join(t.TempDir(), "reports", "scan", "earlybird.json")
+	reporting := EarlybirdCfg{Config: cfgReader.EarlybirdConfig{
+		SearchDir:              dir,
+		SeverityFailLevel:      2,
+		ConfidenceFailLevel:    4,
+		SeverityDisplayLevel:   4,
+		ConfidenceDisplayLevel: 4,
+		IgnoreFailure:          true,
+		OutputFormat:           "json",
+		OutputFile:             outputFile,
+		MaxFileSize:            1000000,
+		WorkLength:             2500,
+		WorkerCount:            1,
+	}}
+	reporting.Scan()
+
+	output, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Hits []scan.Hit `json:"hits"`
+	}
+	if err := json.Unmarshal(output, &report); err != nil {
+		t.Fatalf("report isn't JSON: %v\n%s", err, output)
+	}
+	if len(report.Hits) != 1 || report.Hits[0].Code != 7 || report.Hits[0].Filename != filepath.Join(dir, "app.py") {
+		t.Errorf("report hits = %+v, want the finding of app.py", report.Hits)
+	}
+}
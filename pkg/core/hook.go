@@ -0,0 +1,46 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package core
+
+import (
+	"flag"
+	"fmt"
+
+	"github.com/americanexpress/earlybird/v4/pkg/git"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// InstallHookCommand is the name of the subcommand installing the pre-commit hook
+const InstallHookCommand = "install-hook"
+
+// InstallHook runs the install-hook subcommand with its arguments, writing the Earlybird pre-commit hook to the git
+// repository of --path
+func InstallHook(args []string) error {
+	flags := flag.NewFlagSet(InstallHookCommand, flag.ExitOnError)
+	force := flags.Bool("force", false, "Overwrite the existing pre-commit hook")
+	dir := flags.String("path", utils.MustGetWD(), "Directory of the git repository to install the hook to (defaults to CWD)")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	hookPath, err := git.InstallHook(*dir, *force)
+	if err != nil {
+		return err
+	}
+	fmt.Println("Installed the Earlybird pre-commit hook to", hookPath)
+	return nil
+}
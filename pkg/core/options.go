@@ -0,0 +1,154 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+
+package core
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+)
+
+// explicitFlags returns the names of the flags set on the command line, they take precedence over the other sources
+func explicitFlags(flags *flag.FlagSet) map[string]bool {
+	explicit := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		explicit[f.Name] = true
+	})
+	return explicit
+}
+
+// loadEnvOptions sets the flags which weren't set on the command line from the EARLYBIRD_ environment variables named
+// after them, e.g. EARLYBIRD_DISPLAY_SEVERITY=high.  Lists are comma separated, setting a repeated flag, like enable,
+// once per value.  The flags set are added to explicit, so the options file doesn't override them.
+func loadEnvOptions(flags *flag.FlagSet, environ []string, explicit map[string]bool) (err error) {
+	variables := make(map[string]string)
+	for _, variable := range environ {
+		if name, value, found := strings.Cut(variable, "="); found && strings.HasPrefix(name, envPrefix) {
+			variables[name] = value
+		}
+	}
+	flags.VisitAll(func(f *flag.Flag) {
+		value, ok := variables[envOptionName(f.Name)]
+		if !ok || explicit[f.Name] || err != nil {
+			return
+		}
+		if _, repeated := f.Value.(*arrayFlags); repeated {
+			var values []interface{}
+			for _, item := range strings.Split(value, ",") {
+				if item = strings.TrimSpace(item); item != "" {
+					values = append(values, item)
+				}
+			}
+			err = setOption(f, values)
+		} else {
+			err = f.Value.Set(value)
+		}
+		if err != nil {
+			err = fmt.Errorf("invalid environment variable %s: %w", envOptionName(f.Name), err)
+			return
+		}
+		explicit[f.Name] = true
+	})
+	return err
+}
+
+// envOptionName is the name of the environment variable of the flag, e.g. EARLYBIRD_DISPLAY_SEVERITY
+func envOptionName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// loadOptionsFile sets the flags which weren't set explicitly from the options file, a YAML or JSON map of the
+// flag names to their values, e.g. "display-severity: high".  A missing file is only an error when required.  Unknown
+// options are warned about rather than failing, so the file can be shared with newer versions of Earlybird.
+func loadOptionsFile(flags *flag.FlagSet, filePath string, required bool, explicit map[string]bool) error {
+	data, err := os.ReadFile(filePath)
+	if errors.Is(err, fs.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var options map[string]interface{}
+	if err := yaml.Unmarshal(data, &options); err != nil {
+		return fmt.Errorf("invalid options file %s: %w", filePath, err)
+	}
+	// The options are set in a stable order, so the errors are the same from one run to the next
+	names := make([]string, 0, len(options))
+	for name := range options {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		f := flags.Lookup(name)
+		if f == nil {
+			log.Printf("Warning: unknown option %q in %s", name, filePath)
+			continue
+		}
+		if explicit[name] {
+			continue
+		}
+		if err := setOption(f, options[name]); err != nil {
+			return fmt.Errorf("invalid option %q in %s: %w", name, filePath, err)
+		}
+	}
+	return nil
+}
+
+// setOption sets the flag to the option value.  A list sets a repeated flag, like enable, once per value, and is joined
+// with commas for the other flags, e.g. enable-rules.
+func setOption(f *flag.Flag, value interface{}) error {
+	list, isList := value.([]interface{})
+	if !isList {
+		return f.Value.Set(optionString(value))
+	}
+	values := make([]string, len(list))
+	for i, item := range list {
+		values[i] = optionString(item)
+	}
+	if _, repeated := f.Value.(*arrayFlags); repeated {
+		for _, value := range values {
+			if err := f.Value.Set(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return f.Value.Set(strings.Join(values, ","))
+}
+
+// optionString formats a scalar option value the way it's passed on the command line
+func optionString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case nil:
+		return ""
+	default:
+		return fmt.Sprint(v)
+	}
+}
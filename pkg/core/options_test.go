@@ -0,0 +1,158 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+
+package core
+
+import (
+	"bytes"
+	"flag"
+	"io"
+	"log"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// testFlags defines a flag of every kind on a new flag set
+func testFlags() (flags *flag.FlagSet, format, severity, rules *string, workers *int, verbose *bool, modules *arrayFlags) {
+	flags = flag.NewFlagSet("earlybird", flag.ContinueOnError)
+	format = flags.String("format", "console", "")
+	severity = flags.String("display-severity", "medium", "")
+	rules = flags.String("enable-rules", "", "")
+	workers = flags.Int("workers", 8, "")
+	verbose = flags.Bool("verbose", false, "")
+	modules = new(arrayFlags)
+	flags.Var(modules, "enable", "")
+	return
+}
+
+func TestLoadOptionsFile(t *testing.T) {
+	optionsFile := path.Join(t.TempDir(), "earlybird.yaml")
+	options := `# CI options
+format: json
+display-severity: high
+enable-rules: [3001, 3002]
+workers: 4
+verbose: true
+enable:
+  - content
+  - password-secret
+future-option: 10
+`
+	if err := os.WriteFile(optionsFile, []byte(options), 0644); err != nil {
+		t.Fatal(err)
+	}
+	flags, format, severity, rules, workers, verbose, modules := testFlags()
+	if err := flags.Parse([]string{"-format=csv", "-workers", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	var warnings bytes.Buffer
+	log.SetOutput(&warnings)
+	defer log.SetOutput(os.Stderr)
+
+	if err := loadOptionsFile(flags, optionsFile, true, explicitFlags(flags)); err != nil {
+		t.Fatalf("loadOptionsFile() err = %v", err)
+	}
+	// The flags passed on the command line take precedence over the file
+	if *format != "csv" || *workers != 2 {
+		t.Errorf("loadOptionsFile() format = %s and workers = %d, want the flags csv and 2", *format, *workers)
+	}
+	if *severity != "high" || *rules != "3001,3002" || !*verbose {
+		t.Errorf("loadOptionsFile() display-severity = %s, enable-rules = %s and verbose = %v, want the file values", *severity, *rules, *verbose)
+	}
+	if want := (arrayFlags{"content", "password-secret"}); !reflect.DeepEqual(*modules, want) {
+		t.Errorf("loadOptionsFile() enable = %v, want %v", *modules, want)
+	}
+	if !strings.Contains(warnings.String(), `unknown option "future-option"`) {
+		t.Errorf("loadOptionsFile() warnings = %q, want the unknown option", warnings.String())
+	}
+}
+
+func TestLoadOptionsFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	invalid := path.Join(dir, "invalid.yaml")
+	if err := os.WriteFile(invalid, []byte("workers: many\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(os.Stderr)
+	tests := []struct {
+		name     string
+		file     string
+		required bool
+		wantErr  bool
+	}{
+		{name: "Missing default file is skipped", file: path.Join(dir, "earlybird.yaml")},
+		{name: "Missing required file", file: path.Join(dir, "earlybird.yaml"), required: true, wantErr: true},
+		{name: "Invalid value", file: invalid, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flags, _, _, _, _, _, _ := testFlags()
+			if err := loadOptionsFile(flags, tt.file, tt.required, map[string]bool{}); (err != nil) != tt.wantErr {
+				t.Errorf("loadOptionsFile() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadEnvOptions(t *testing.T) {
+	optionsFile := path.Join(t.TempDir(), "earlybird.yaml")
+	if err := os.WriteFile(optionsFile, []byte("format: json\ndisplay-severity: high\nworkers: 4\nenable: [entropy]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	environ := []string{
+		"EARLYBIRD_FORMAT=sarif",
+		"EARLYBIRD_DISPLAY_SEVERITY=critical",
+		"EARLYBIRD_VERBOSE=1",
+		"EARLYBIRD_ENABLE=content, password-secret",
+		"EARLYBIRD_ENABLE_RULES=3001,3002",
+		"EARLYBIRD_UNKNOWN=ignored",
+		"PATH=/usr/bin",
+	}
+	flags, format, severity, rules, workers, verbose, modules := testFlags()
+	if err := flags.Parse([]string{"-format=csv"}); err != nil {
+		t.Fatal(err)
+	}
+	explicit := explicitFlags(flags)
+	if err := loadEnvOptions(flags, environ, explicit); err != nil {
+		t.Fatalf("loadEnvOptions() err = %v", err)
+	}
+	if err := loadOptionsFile(flags, optionsFile, true, explicit); err != nil {
+		t.Fatalf("loadOptionsFile() err = %v", err)
+	}
+	// The flags take precedence over the environment, which takes precedence over the file
+	if *format != "csv" {
+		t.Errorf("format = %s, want the flag csv", *format)
+	}
+	if *severity != "critical" || *rules != "3001,3002" || !*verbose {
+		t.Errorf("display-severity = %s, enable-rules = %s and verbose = %v, want the environment values", *severity, *rules, *verbose)
+	}
+	if want := (arrayFlags{"content", "password-secret"}); !reflect.DeepEqual(*modules, want) {
+		t.Errorf("enable = %v, want the environment values %v", *modules, want)
+	}
+	if *workers != 4 {
+		t.Errorf("workers = %d, want the file value 4", *workers)
+	}
+
+	flags, _, _, _, _, _, _ = testFlags()
+	if err := loadEnvOptions(flags, []string{"EARLYBIRD_WORKERS=many"}, map[string]bool{}); err == nil || !strings.Contains(err.Error(), "EARLYBIRD_WORKERS") {
+		t.Errorf("loadEnvOptions() err = %v, want the invalid variable", err)
+	}
+}
@@ -0,0 +1,75 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// RulesCommand is the name of the subcommand documenting the rules, e.g. `go-earlybird rules list` for an audit
+const RulesCommand = "rules"
+
+// RulesListCommand is the action of the rules subcommand listing the active rules
+const RulesListCommand = "list"
+
+// ruleListing is a rule in the listing of the active rules
+type ruleListing struct {
+	Code       int    `json:"code"`
+	Caption    string `json:"caption"`
+	Category   string `json:"category"`
+	Severity   string `json:"severity"`
+	Confidence string `json:"confidence"`
+}
+
+// ListRules loads the rules of the configuration and writes the rules active once the modules, the rule codes and the
+// categories enabled or disabled are applied, sorted by code.  The rules are written as a JSON array with the json
+// format, else as a table.
+func (eb *EarlybirdCfg) ListRules(w io.Writer) error {
+	if err := scan.LoadRules(eb.Config); err != nil {
+		return err
+	}
+	listing := make([]ruleListing, 0, len(scan.CombinedRules))
+	for _, rule := range scan.CombinedRules {
+		listing = append(listing, ruleListing{
+			Code:       rule.Code,
+			Caption:    rule.Caption,
+			Category:   rule.Category,
+			Severity:   cfgreader.Settings.TranslateLevelID(rule.Severity),
+			Confidence: cfgreader.Settings.TranslateLevelID(rule.Confidence),
+		})
+	}
+	sort.SliceStable(listing, func(i, j int) bool { return listing[i].Code < listing[j].Code })
+
+	if eb.Config.OutputFormat == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(listing)
+	}
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "CODE\tCAPTION\tCATEGORY\tSEVERITY\tCONFIDENCE")
+	for _, rule := range listing {
+		fmt.Fprintf(table, "%d\t%s\t%s\t%s\t%s\n", rule.Code, rule.Caption, rule.Category, rule.Severity, rule.Confidence)
+	}
+	return table.Flush()
+}
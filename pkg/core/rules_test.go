@@ -0,0 +1,86 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/americanexpress/earlybird/v4/pkg/earlybird"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+func TestEarlybirdCfg_ListRules(t *testing.T) {
+	defer func(rules []scan.Rule) { scan.CombinedRules = rules }(scan.CombinedRules)
+	cfg, err := earlybird.NewConfig(earlybird.Options{ConfigDir: filepath.Join("..", "..", "config"), ExcludeCategories: []string{"inclusivity"}})
+	if err != nil {
+		t.Fatalf("NewConfig() error = %v", err)
+	}
+	cfg.DisabledRuleCodes = []int{1002}
+
+	t.Run("JSON", func(t *testing.T) {
+		cfg.OutputFormat = "json"
+		var out bytes.Buffer
+		if err := (&EarlybirdCfg{Config: cfg}).ListRules(&out); err != nil {
+			t.Fatalf("ListRules() error = %v", err)
+		}
+		var listing []ruleListing
+		if err := json.Unmarshal(out.Bytes(), &listing); err != nil {
+			t.Fatalf("ListRules() wrote invalid JSON: %v", err)
+		}
+		if len(listing) == 0 {
+			t.Fatal("ListRules() listed no rules")
+		}
+		for i, rule := range listing {
+			if rule.Code == 1002 {
+				t.Errorf("ListRules() listed the disabled rule %+v", rule)
+			}
+			if rule.Category == "inclusivity" {
+				t.Errorf("ListRules() listed the rule %+v of an excluded category", rule)
+			}
+			if rule.Caption == "" || rule.Severity == "" || rule.Confidence == "" {
+				t.Errorf("ListRules() listed the incomplete rule %+v", rule)
+			}
+			if i > 0 && listing[i-1].Code > rule.Code {
+				t.Errorf("ListRules() listed %d before %d, want the rules sorted by code", listing[i-1].Code, rule.Code)
+			}
+		}
+	})
+
+	t.Run("Table", func(t *testing.T) {
+		cfg.OutputFormat = "console"
+		var out bytes.Buffer
+		if err := (&EarlybirdCfg{Config: cfg}).ListRules(&out); err != nil {
+			t.Fatalf("ListRules() error = %v", err)
+		}
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "CODE CAPTION CATEGORY SEVERITY CONFIDENCE" {
+			t.Errorf("ListRules() header = %q", lines[0])
+		}
+		for _, line := range lines[1:] {
+			if strings.HasPrefix(line, "1002 ") || strings.Contains(line, " inclusivity ") {
+				t.Errorf("ListRules() listed the filtered rule %q", line)
+			}
+		}
+		if len(lines) < 2 {
+			t.Error("ListRules() listed no rules")
+		}
+	})
+}
@@ -47,4 +47,8 @@ type PTRGitConfig struct {
 	RepoUser   *string
 	RepoBranch *string
 	Project    *string
+	// Token authenticates the clone, as the password of RepoUser
+	Token *string
+	// Depth is the number of commits cloned, 0 for the whole history
+	Depth *int
 }
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package core
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+
+	"github.com/americanexpress/earlybird/v4/pkg/buildflags"
+)
+
+// VersionCommand is the name of the subcommand printing the build metadata, e.g. for support tickets
+const VersionCommand = "version"
+
+// PrintVersion writes the version, the git commit and the build date of the binary, and the go version it was built with
+func PrintVersion(w io.Writer) {
+	fmt.Fprintf(w, "go-earlybird %s\n", buildflags.Version)
+	fmt.Fprintf(w, "commit: %s\n", buildflags.Commit)
+	fmt.Fprintf(w, "built: %s\n", buildflags.Date)
+	fmt.Fprintf(w, "go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}
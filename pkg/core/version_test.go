@@ -0,0 +1,38 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package core
+
+import (
+	"bytes"
+	"runtime"
+	"testing"
+
+	"github.com/americanexpress/earlybird/v4/pkg/buildflags"
+)
+
+func TestPrintVersion(t *testing.T) {
+	version, commit, date := buildflags.Version, buildflags.Commit, buildflags.Date
+	defer func() { buildflags.Version, buildflags.Commit, buildflags.Date = version, commit, date }()
+	buildflags.Version, buildflags.Commit, buildflags.Date = "4.2.0", "0496f10c", "2024-05-02T10:14:03Z"
+
+	var output bytes.Buffer
+	PrintVersion(&output)
+	want := "go-earlybird 4.2.0\ncommit: 0496f10c\nbuilt: 2024-05-02T10:14:03Z\ngo: " + runtime.Version() + " " + runtime.GOOS + "/" + runtime.GOARCH + "\n"
+	if output.String() != want {
+		t.Errorf("PrintVersion() = %q, want %q", output.String(), want)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package earlybird
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+
+	"github.com/americanexpress/earlybird/v4/pkg/buildflags"
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// NewConfig builds the scan configuration of the options with the defaults of the CLI flags, loading earlybird.json from
+// the configuration directory.  The configuration can be adjusted and scanned with Options.Config once its rules are
+// loaded with scan.LoadRules.
+func NewConfig(opts Options) (cfg cfgreader.EarlybirdConfig, err error) {
+	cfg.Version = buildflags.Version
+	cfg.Commit = buildflags.Commit
+	cfg.BuildDate = buildflags.Date
+	cfg.ConfigDir = opts.ConfigDir
+	if cfg.ConfigDir == "" {
+		cfg.ConfigDir = utils.GetConfigDir()
+	}
+	// The settings are loaded from scratch, the settings left out of the file don't keep the values of the previous config
+	var settings cfgreader.Configs
+	if err = cfgreader.LoadConfig(&settings, filepath.Join(cfg.ConfigDir, earlybirdConfigFile)); err != nil {
+		return cfg, fmt.Errorf("failed to load Earlybird config: %w", err)
+	}
+	cfgreader.Settings = settings
+	if cfg.RuleModulesFilenameMap, cfg.AvailableModules, err = RuleModules(cfg.ConfigDir); err != nil {
+		return cfg, fmt.Errorf("error getting rule modules: %w", err)
+	}
+	if cfg.EnabledModulesMap, err = utils.SelectModules(opts.Modules, opts.DisabledModules, cfg.RuleModulesFilenameMap); err != nil {
+		return cfg, err
+	}
+	for moduleName := range cfg.EnabledModulesMap {
+		cfg.EnabledModules = append(cfg.EnabledModules, moduleName)
+	}
+	sort.Strings(cfg.EnabledModules)
+	cfg.IncludeCategories = opts.IncludeCategories
+	cfg.ExcludeCategories = opts.ExcludeCategories
+	if cfg.SearchDir, cfg.SearchDirs, err = searchDirs(opts.Paths); err != nil {
+		return cfg, err
+	}
+
+	cfg.LevelMap = cfgreader.Settings.GetLevelMap()
+	cfg.WorkerCount = runtime.NumCPU()
+	cfg.WorkLength = DefaultWorkLength
+	cfg.MaxFileSize = DefaultMaxFileSize
+	cfg.MaxArchiveSize = DefaultMaxArchiveSize
+	cfg.EntropyBase64Threshold = DefaultEntropyBase64Threshold
+	cfg.EntropyHexThreshold = DefaultEntropyHexThreshold
+	cfg.BinaryThreshold = DefaultBinaryThreshold
+	if userHomeDir, err := os.UserHomeDir(); err == nil {
+		cfg.IgnoreFile = filepath.Join(userHomeDir, ignoreFileName)
+	}
+	cfg.TargetType = utils.All
+	cfg.RulesConfigDir = filepath.Join(cfg.ConfigDir, rulesDir)
+	cfg.FalsePositivesConfigDir = filepath.Join(cfg.ConfigDir, falsePositivesDir)
+	cfg.LabelsConfigDir = filepath.Join(cfg.ConfigDir, labelsDir)
+	cfg.SolutionsConfigDir = filepath.Join(cfg.ConfigDir, solutionsDir)
+
+	// Set the skip options (what not to scan) from configs
+	cfg.AnnotationsToSkipLine = cfgreader.Settings.AnnotationsToSkip
+	cfg.ExtensionsToSkipScan = cfgreader.Settings.ExtensionsToSkipTextScan
+	cfg.BinaryScanExtensions = cfgreader.Settings.BinaryScanExtensions
+	cfg.ConfidenceKeywords = cfgreader.Settings.ConfidenceKeywords
+	cfg.MaxMatchLength = cfgreader.Settings.MaxMatchLength
+	cfg.DropLongMatches = cfgreader.Settings.DropLongMatches
+	cfg.IncludeExtensions = utils.ParseExtensions(strings.Join(cfgreader.Settings.IncludeExtensions, ","))
+	cfg.ExcludeExtensions = utils.ParseExtensions(strings.Join(cfgreader.Settings.ExcludeExtensions, ","))
+
+	// Determine which results to show and which to fail on
+	if cfg.SeverityDisplayLevel, err = level(opts.DisplaySeverity, cfgreader.Settings.DisplayThreshold); err != nil {
+		return cfg, fmt.Errorf("invalid display severity: %w", err)
+	}
+	if cfg.ConfidenceDisplayLevel, err = level(opts.DisplayConfidence, cfgreader.Settings.DisplayConfidenceThreshold); err != nil {
+		return cfg, fmt.Errorf("invalid display confidence: %w", err)
+	}
+	if cfg.SeverityFailLevel, err = cfgreader.Settings.GetFailSeverityLevel(opts.FailSeverity); err != nil {
+		return cfg, err
+	}
+	if cfg.ConfidenceFailLevel, err = level(opts.FailConfidence, 0); err != nil {
+		return cfg, fmt.Errorf("invalid fail confidence: %w", err)
+	}
+	if cfg.MinConfidence, err = cfgreader.Settings.GetMinConfidenceLevel(""); err != nil {
+		return cfg, err
+	}
+	cfg.AdjustedSeverityCategories = cfgreader.Settings.AdjustedSeverityCategories
+	if cfg.SeverityOverrides, err = cfgreader.Settings.GetSeverityOverrides(); err != nil {
+		return cfg, fmt.Errorf("failed to load rule severity overrides: %w", err)
+	}
+	return cfg, nil
+}
+
+// RuleModules lists the rule files of the `rules` directory of the configuration directory, returning the map of module
+// name to file name, e.g. { content: 'content.json', ccnumber: 'ccnumber.json' }, and the names of the modules
+func RuleModules(configDir string) (fileNames map[string]string, modules []string, err error) {
+	fileNames = make(map[string]string)
+	err = filepath.Walk(filepath.Join(configDir, rulesDir), func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+
+		moduleName := strings.TrimSuffix(info.Name(), filepath.Ext(info.Name()))
+		fileNames[moduleName] = info.Name()
+		modules = append(modules, moduleName)
+		return nil
+	})
+	if err != nil {
+		return fileNames, modules, &cfgreader.ConfigError{Kind: cfgreader.ErrMissingRuleDir, File: filepath.Join(configDir, rulesDir), Err: err}
+	}
+	return fileNames, modules, nil
+}
+
+// searchDirs returns the directory to scan, the working directory by default, or the common parent directory of the
+// directories to scan when several are passed, along with them
+func searchDirs(paths []string) (searchDir string, roots []string, err error) {
+	switch len(paths) {
+	case 0:
+		searchDir, err = os.Getwd()
+		return searchDir, nil, err
+	case 1:
+		return paths[0], nil, nil
+	}
+	return utils.CommonDir(paths), paths, nil
+}
+
+// level returns the ID of the level name, the default level ID when the name is empty, or the ID of the default level of
+// the scale, low, without it
+func level(name string, defaultLevel int) (int, error) {
+	levelMap := cfgreader.Settings.GetLevelMap()
+	if name == "" {
+		if defaultLevel != 0 {
+			return defaultLevel, nil
+		}
+		name = cfgreader.Settings.DefaultLevelName()
+	}
+	id, ok := levelMap[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown level %q, expected one of %v", name, cfgreader.Settings.GetLevelNames())
+	}
+	return id, nil
+}
@@ -0,0 +1,33 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package earlybird
+
+// Defaults of the scan configuration, shared with the CLI flags
+const (
+	DefaultWorkLength             = 2500
+	DefaultMaxFileSize            = 10240000
+	DefaultMaxArchiveSize         = 1073741824
+	DefaultEntropyBase64Threshold = 4.5
+	DefaultEntropyHexThreshold    = 3.0
+	DefaultBinaryThreshold        = 0.3
+	ignoreFileName                = ".ge_ignore"
+	earlybirdConfigFile           = "earlybird.json"
+	rulesDir                      = "rules"
+	falsePositivesDir             = "falsepositives"
+	labelsDir                     = "labels"
+	solutionsDir                  = "solutions"
+)
@@ -0,0 +1,176 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+// Package earlybird scans files for secrets from Go code, without running the go-earlybird CLI
+package earlybird
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/file"
+	"github.com/americanexpress/earlybird/v4/pkg/git"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// Scan searches the files of the options for secrets and returns the findings along with the summary of the scan.  It
+// doesn't exit nor write to stdout, the configuration and file errors are returned; the errors of the configuration
+// files match ErrConfigParse, ErrRuleCompile or ErrMissingRuleDir with errors.Is and expose the file and the rule at
+// fault as a ConfigError.  The rules are loaded into the scan package, so the scans with different rules must not run
+// concurrently.
+//
+// Cancelling the context stops the walk of the directories and the scan of the files promptly, Scan then returns the
+// error of the context along with the findings of the files scanned so far.
+func Scan(ctx context.Context, opts Options) (Result, error) {
+	if opts.Hits != nil {
+		defer close(opts.Hits)
+	}
+	var cfg cfgreader.EarlybirdConfig
+	if opts.Config != nil {
+		cfg = *opts.Config
+	} else {
+		var err error
+		if cfg, err = NewConfig(opts); err != nil {
+			return Result{}, err
+		}
+		if err = scan.LoadRules(cfg); err != nil {
+			return Result{}, err
+		}
+		// A rule file which failed to load leaves the scan incomplete, unlike the rules left out by the options
+		if err = scan.Ready(); err != nil && !errors.Is(err, scan.ErrNoRules) {
+			return Result{}, err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return Result{}, err
+	}
+
+	start := time.Now()
+	// The scan is stopped on its own once it reached the maximum number of findings
+	scanCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	hitChannel := make(chan scan.Hit)
+	var fileContext file.Context
+	if cfg.Stdin {
+		// The standard input is scanned as it's read, it's a single pseudo-file in the reports
+		fileContext.Files = []scan.File{{Name: cfg.StdinName, Path: cfg.StdinName}}
+		go scan.SearchStreamContext(scanCtx, &cfg, cfg.StdinName, os.Stdin, hitChannel)
+	} else {
+		var err error
+		if fileContext, err = FileContext(ctx, cfg); err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return Result{Files: fileContext}, ctxErr
+			}
+			return Result{}, fmt.Errorf("failed to get FileContext: %w", err)
+		}
+		go scan.SearchFilesContext(scanCtx, &cfg, fileContext.Files, fileContext.CompressPaths, fileContext.ConvertPaths, hitChannel)
+	}
+	fileContext.Start = start
+
+	var hits <-chan scan.Hit = hitChannel
+	if cfg.MaxFindings > 0 {
+		hits = scan.LimitHits(hits, cfg.MaxFindings, stop, &fileContext.Truncated)
+	}
+	// The findings streamed are sent as they're found, sorting or collapsing them would hold all of them first
+	if opts.Hits == nil {
+		hits = scan.SortHits(hits)
+		if cfg.DedupFindings {
+			hits = scan.CollapseDuplicates(hits)
+		}
+	}
+	if cfg.RelativePaths {
+		baseDir := cfg.BaseDir
+		if baseDir == "" {
+			baseDir = cfg.SearchDir
+		}
+		hits = scan.RelativeHits(hits, baseDir)
+	}
+
+	var result Result
+	// The findings reported are counted as they go, so the streamed findings are summarized as well
+	reported := scan.Summarize(nil, nil, nil, 0)
+	for hit := range hits {
+		if opts.Hits != nil {
+			opts.Hits <- hit
+		} else {
+			result.Hits = append(result.Hits, hit)
+		}
+		if !hit.Suppressed {
+			reported.Count(hit)
+		}
+	}
+	// The findings are all collected, so the context knows whether they were truncated
+	result.Files = fileContext
+	result.Summary = fileContext.Summary(nil)
+	result.Summary.Severities, result.Summary.Confidences = reported.Severities, reported.Confidences
+	result.Failed = cfg.FailScan || cfg.Strict && result.Summary.FilesIncomplete > 0
+	return result, ctx.Err()
+}
+
+// FileContext lists the files to scan of the configuration: the lines added by a diff, the files of the file list, of
+// the search directories, of their git targets, or of the standard input streams.  The walk of the directories stops with the error of the context once it's cancelled.
+func FileContext(ctx context.Context, cfg cfgreader.EarlybirdConfig) (fileContext file.Context, err error) {
+	if cfg.DiffOnly {
+		// The lines added by the diff are scanned rather than the files
+		return file.GetDiffFiles(&cfg)
+	}
+	if cfg.FilesFrom != "" {
+		// The files listed are scanned as is, the directories aren't walked
+		return file.GetListedFiles(&cfg)
+	}
+	if len(cfg.SearchDirs) > 1 {
+		// Each directory is scanned with its own ignore files, its files are reported with their absolute path
+		for _, root := range cfg.SearchDirs {
+			rootCfg := cfg
+			rootCfg.SearchDir, rootCfg.SearchDirs = root, nil
+			rootContext, err := FileContext(ctx, rootCfg)
+			if err != nil {
+				return fileContext, err
+			}
+			fileContext.Merge(rootContext)
+		}
+		return fileContext, nil
+	}
+	if cfg.SearchDir != "" {
+		// We're going to load a 'files' slice based on the target type
+		switch cfg.TargetType {
+		case utils.Tracked:
+			return file.GetGitFiles(utils.Tracked, &cfg)
+		case utils.Staged:
+			return file.GetGitFiles(utils.Staged, &cfg)
+		case utils.Range:
+			return file.GetGitFiles(utils.Range, &cfg)
+		case utils.Index:
+			return file.GetStagedFiles(&cfg)
+		case utils.History:
+			return file.GetHistoryFiles(&cfg)
+		default:
+			return file.GetFilesContext(ctx, &cfg)
+		}
+	}
+	if cfg.GitStream {
+		fileContext.Files, err = git.ParseGitLog(bufio.NewReader(os.Stdin))
+	} else {
+		fileContext.Files, err = file.GetFileFromStream(&cfg)
+	}
+	return fileContext, err
+}
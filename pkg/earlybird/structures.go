@@ -0,0 +1,78 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package earlybird
+
+import (
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/file"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// The kinds of configuration errors returned by Scan and NewConfig, to be matched with errors.Is, the file and rule at
+// fault are exposed by the ConfigError found with errors.As
+var (
+	ErrConfigParse    = cfgreader.ErrConfigParse
+	ErrRuleCompile    = cfgreader.ErrRuleCompile
+	ErrMissingRuleDir = cfgreader.ErrMissingRuleDir
+)
+
+// ConfigError is a configuration file which failed to load, see cfgreader.ConfigError
+type ConfigError = cfgreader.ConfigError
+
+// Options configures a scan, the zero value scans the working directory with every rule module of the default
+// configuration directory
+type Options struct {
+	// Paths are the directories to scan, the working directory by default
+	Paths []string
+	// ConfigDir is the directory of earlybird.json and of the rules, the go-earlybird configuration directory by default
+	ConfigDir string
+	// Modules are the rule modules enabled, e.g. password-secret, every module of the configuration directory by default
+	Modules []string
+	// DisabledModules are the rule modules skipped, e.g. entropy, they take precedence over Modules.  At least one module
+	// must be left enabled.
+	DisabledModules []string
+	// IncludeCategories are the categories of the rules to run, e.g. pii, and ExcludeCategories the categories of the rules
+	// to skip, the rules of every category run by default
+	IncludeCategories, ExcludeCategories []string
+	// DisplaySeverity and DisplayConfidence are the lowest levels of the findings reported, e.g. high, they default to
+	// the display thresholds of earlybird.json
+	DisplaySeverity, DisplayConfidence string
+	// FailSeverity and FailConfidence are the lowest levels of the findings failing the scan, FailSeverity defaults to
+	// the fail severity of earlybird.json and FailConfidence to the lowest level
+	FailSeverity, FailConfidence string
+	// Hits receives the findings while they're found when it's set, so the findings of a large scan are processed as they
+	// arrive rather than being held in memory until it's over: they aren't sorted, deduplicated nor collected in
+	// Result.Hits.  Scan closes the channel once it returns.
+	Hits chan<- scan.Hit
+	// Config is used as is instead of the configuration of the options above, e.g. by the CLI which builds it from its
+	// flags -- the rules of the configuration must already be loaded with scan.LoadRules or scan.Init
+	Config *cfgreader.EarlybirdConfig
+}
+
+// Result of a scan
+type Result struct {
+	// Hits are the findings in the order of the reports, the findings suppressed with an inline comment are only included
+	// in verbose mode and flagged as Suppressed.  They're left empty when the findings are streamed to Options.Hits.
+	Hits []scan.Hit
+	// Files are the files scanned and skipped, along with the ignore patterns
+	Files file.Context
+	// Summary counts the files and the findings reported
+	Summary scan.Summary
+	// Failed is set when a finding is at or above the fail severity and confidence, or in strict mode when a file couldn't
+	// be scanned, see Summary.FilesIncomplete
+	Failed bool
+}
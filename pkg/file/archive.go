@@ -0,0 +1,472 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+type archiveKind int
+
+const (
+	notArchive archiveKind = iota
+	zipArchive
+	tarArchive
+	gzipArchive
+)
+
+var (
+	// zipMagic is the signature at the start of every zip archive, including jar, war and ear files
+	zipMagic = []byte("PK\x03\x04")
+	// gzipMagic is the signature at the start of a gzip stream, e.g. a .tar.gz or a single .gz file
+	gzipMagic = []byte{0x1f, 0x8b}
+	// tarMagic is found at tarMagicOffset of the first header of a tar archive
+	tarMagic = []byte("ustar")
+
+	// ErrArchiveTooLarge is returned when an archive decompresses to more than the configured maximum archive size
+	ErrArchiveTooLarge = errors.New("archive exceeds the maximum decompressed size")
+
+	// archiveIdleTimeout is how long an archive stays open once none of its entries is read, so the next entry
+	// reuses it instead of reading the archive all over again
+	archiveIdleTimeout = time.Second
+)
+
+const tarMagicOffset = 257
+
+// GetArchiveFiles lists the files contained within the archives without extracting them to disk.
+// Each entry is named after its archive, e.g. bundle.zip!/config/app.properties, and streamed from the archive when scanned.
+// The entries which are too large or ignored are skipped.
+func GetArchiveFiles(files []scan.File, cfg *cfgreader.EarlybirdConfig, fileContext *Context) (newfiles []scan.File) {
+	for _, file := range files {
+		var (
+			entries, skippedEntries []scan.File
+			err                     error
+		)
+		switch detectArchive(file.Path) {
+		case tarArchive, gzipArchive:
+			entries, skippedEntries, err = GetTarEntries(file.Path, cfg.MaxFileSize, cfg.MaxArchiveSize)
+		default:
+			// Files with an archive extension are read as zip archives, like jar, war and ear files
+			entries, skippedEntries, err = GetZipEntries(file.Path, cfg.MaxFileSize)
+		}
+		if err != nil {
+			// We log the error and move on with the entries read so far
+			log.Println("Error reading compressed file", file.Path, err)
+		}
+		for _, entry := range skippedEntries {
+			fileContext.skip(entry.Path, skipTooLarge)
+			if cfg.VerboseEnabled {
+				utils.InfoLog.Println("Ignoring", entry.Path, ". Filesize is too large.")
+			}
+		}
+		for _, entry := range entries {
+			if isIgnoredFile(entry.Path, cfg.SearchDir) {
+				fileContext.skip(entry.Path, skipIgnored)
+				continue
+			}
+			if cfg.VerboseEnabled {
+				utils.InfoLog.Println("Reading file ", entry.Path)
+			}
+			newfiles = append(newfiles, entry)
+		}
+	}
+	return newfiles
+}
+
+// GetZipEntries lists the regular files inside of the zip archive, skipping the entries larger than maxFileSize.
+// The entries read at the same time share a single reader of the archive.
+func GetZipEntries(archivePath string, maxFileSize int64) (entries, skipped []scan.File, err error) {
+	_, entries, skipped, err = listZipEntries(archivePath, maxFileSize)
+	return entries, skipped, err
+}
+
+// listZipEntries lists the entries of the zip archive along with the stream they share
+func listZipEntries(archivePath string, maxFileSize int64) (stream *zipStream, entries, skipped []scan.File, err error) {
+	r, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer r.Close()
+
+	stream = &zipStream{path: archivePath}
+	for i, entry := range r.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+		// Empty entries have nothing to scan
+		if entry.UncompressedSize64 == 0 {
+			continue
+		}
+		file := archiveEntry(archivePath, entry.Name)
+		if entry.UncompressedSize64 > uint64(maxFileSize) {
+			skipped = append(skipped, file)
+			continue
+		}
+		file.Open = stream.entryOpener(i, entry.Name, maxFileSize)
+		entries = append(entries, file)
+	}
+	return stream, entries, skipped, nil
+}
+
+// zipStream shares a single reader of a zip archive between its entries, so its central directory isn't read again
+// for each of them.  The archive is opened by the first entry read and closed once no entry has been read from it
+// for archiveIdleTimeout, the entries which are never read don't keep it open.
+type zipStream struct {
+	mu     sync.Mutex
+	path   string
+	reader *zip.ReadCloser
+	// open is the number of entries being read from the reader
+	open int
+	idle *time.Timer
+}
+
+// entryOpener opens the entry from the shared reader, the entries can be read concurrently
+func (s *zipStream) entryOpener(index int, name string, maxFileSize int64) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		file, err := s.acquire(index, name)
+		if err != nil {
+			return nil, err
+		}
+		rc, err := file.Open()
+		if err != nil {
+			s.release()
+			return nil, err
+		}
+		// The header size can't be trusted, so cap what is read from the entry as well
+		return entryReader{Reader: io.LimitReader(rc, maxFileSize), closers: []io.Closer{rc, &zipEntryCloser{stream: s}}}, nil
+	}
+}
+
+// acquire opens the archive unless it's open already and counts the entry as being read
+func (s *zipStream) acquire(index int, name string) (*zip.File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	stopIdle(&s.idle)
+	if s.reader == nil {
+		r, err := zip.OpenReader(s.path)
+		if err != nil {
+			return nil, err
+		}
+		s.reader = r
+	}
+	if index >= len(s.reader.File) || s.reader.File[index].Name != name {
+		s.closeUnused()
+		return nil, fmt.Errorf("%s: entry %s changed since the archive was listed", s.path, name)
+	}
+	s.open++
+	return s.reader.File[index], nil
+}
+
+// release counts the entry as read, closing the archive when no other entry is read from it in the meantime
+func (s *zipStream) release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.open--; s.open == 0 {
+		s.idle = time.AfterFunc(archiveIdleTimeout, s.closeIdle)
+	}
+}
+
+func (s *zipStream) closeIdle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closeUnused()
+}
+
+// closeUnused closes the archive unless an entry is being read from it, the caller holds the lock
+func (s *zipStream) closeUnused() {
+	if s.open == 0 && s.reader != nil {
+		s.reader.Close()
+		s.reader = nil
+	}
+}
+
+// zipEntryCloser releases the shared reader once the entry is read
+type zipEntryCloser struct {
+	once   sync.Once
+	stream *zipStream
+}
+
+func (c *zipEntryCloser) Close() error {
+	c.once.Do(c.stream.release)
+	return nil
+}
+
+// stopIdle cancels the pending close of an archive which is read again
+func stopIdle(idle **time.Timer) {
+	if *idle != nil {
+		(*idle).Stop()
+		*idle = nil
+	}
+}
+
+// GetTarEntries lists the regular files inside of the tar archive, which may be gzip compressed.  A gzip file which
+// doesn't contain a tar archive is listed as a single entry.  Reading stops with ErrArchiveTooLarge once more than
+// maxArchiveSize bytes were decompressed, to guard against decompression bombs, unless maxArchiveSize is 0.
+func GetTarEntries(archivePath string, maxFileSize, maxArchiveSize int64) (entries, skipped []scan.File, err error) {
+	_, entries, skipped, err = listTarEntries(archivePath, maxFileSize, maxArchiveSize)
+	return entries, skipped, err
+}
+
+// listTarEntries lists the entries of the tar archive along with the stream they share
+func listTarEntries(archivePath string, maxFileSize, maxArchiveSize int64) (stream *tarStream, entries, skipped []scan.File, err error) {
+	stream = &tarStream{path: archivePath, maxArchiveSize: maxArchiveSize}
+	r, isTar, err := stream.openReader()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	defer stream.close()
+
+	if !isTar {
+		// A single compressed file, e.g. app.log.gz, is read through once to get its size
+		size, err := io.Copy(io.Discard, r)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		file := archiveEntry(archivePath, strings.TrimSuffix(filepath.Base(archivePath), filepath.Ext(archivePath)))
+		if size == 0 {
+			return stream, nil, nil, nil
+		}
+		if size > maxFileSize {
+			return stream, nil, []scan.File{file}, nil
+		}
+		file.Open = gzipEntryOpener(stream, maxFileSize)
+		return stream, []scan.File{file}, nil, nil
+	}
+
+	tr := tar.NewReader(r)
+	var total int64
+	for index := 0; ; index++ {
+		header, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return stream, entries, skipped, err
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+			continue
+		}
+		// Stop before an entry which would take the archive over the limit
+		if total += header.Size; maxArchiveSize > 0 && total > maxArchiveSize {
+			return stream, entries, skipped, ErrArchiveTooLarge
+		}
+		// Empty entries have nothing to scan
+		if header.Size == 0 {
+			continue
+		}
+		file := archiveEntry(archivePath, header.Name)
+		if header.Size > maxFileSize {
+			skipped = append(skipped, file)
+			continue
+		}
+		file.Open = stream.entryOpener(index, maxFileSize)
+		entries = append(entries, file)
+	}
+	return stream, entries, skipped, nil
+}
+
+// archiveEntry names the entry after its archive, e.g. bundle.zip!/config/app.properties
+func archiveEntry(archivePath, name string) scan.File {
+	entryPath := archivePath + archiveSeparator + strings.TrimPrefix(name, "/")
+	return scan.File{Name: entryPath, Path: entryPath}
+}
+
+// tarStream is a forward only reader over the entries of a tar archive.  As the files are scanned in order, the entries
+// share a single decompressed stream, which is only reopened when an earlier entry is requested.  The archive is
+// closed once no entry has been read from it for archiveIdleTimeout, the entries which are never read don't keep it open.
+type tarStream struct {
+	mu             sync.Mutex
+	path           string
+	maxArchiveSize int64
+	file           *os.File
+	reader         *tar.Reader
+	// next is the index of the entry returned by the next call to reader.Next
+	next int
+	idle *time.Timer
+}
+
+// openReader opens the archive and returns its decompressed content, reporting if it's a tar archive
+func (s *tarStream) openReader() (r io.Reader, isTar bool, err error) {
+	if s.file, err = os.Open(s.path); err != nil {
+		return nil, false, err
+	}
+	r = bufio.NewReader(s.file)
+	if magic, _ := r.(*bufio.Reader).Peek(len(gzipMagic)); bytes.Equal(magic, gzipMagic) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			s.close()
+			return nil, false, err
+		}
+		r = bufio.NewReader(gz)
+	}
+	header, _ := r.(*bufio.Reader).Peek(tarMagicOffset + len(tarMagic))
+	isTar = len(header) == tarMagicOffset+len(tarMagic) && bytes.Equal(header[tarMagicOffset:], tarMagic)
+	if s.maxArchiveSize > 0 {
+		r = &cappedReader{r: r, remaining: s.maxArchiveSize}
+	}
+	return r, isTar, nil
+}
+
+func (s *tarStream) close() {
+	if s.file != nil {
+		s.file.Close()
+		s.file, s.reader = nil, nil
+	}
+}
+
+// entryOpener positions the shared stream at the entry.  The stream is locked until the entry is closed.
+func (s *tarStream) entryOpener(index int, maxFileSize int64) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		s.mu.Lock()
+		stopIdle(&s.idle)
+		if s.reader == nil || index < s.next {
+			s.close()
+			r, _, err := s.openReader()
+			if err != nil {
+				s.mu.Unlock()
+				return nil, err
+			}
+			s.reader, s.next = tar.NewReader(r), 0
+		}
+		for s.next <= index {
+			if _, err := s.reader.Next(); err != nil {
+				s.close()
+				s.mu.Unlock()
+				if err == io.EOF {
+					err = fmt.Errorf("%s: entry %d changed since the archive was listed", s.path, index)
+				}
+				return nil, err
+			}
+			s.next++
+		}
+		return entryReader{Reader: io.LimitReader(s.reader, maxFileSize), closers: []io.Closer{&tarEntryCloser{stream: s, reuse: true}}}, nil
+	}
+}
+
+func (s *tarStream) closeIdle() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.close()
+}
+
+// gzipEntryOpener streams the content of a gzip file which doesn't contain a tar archive
+func gzipEntryOpener(stream *tarStream, maxFileSize int64) func() (io.ReadCloser, error) {
+	return func() (io.ReadCloser, error) {
+		stream.mu.Lock()
+		r, _, err := stream.openReader()
+		if err != nil {
+			stream.mu.Unlock()
+			return nil, err
+		}
+		return entryReader{Reader: io.LimitReader(r, maxFileSize), closers: []io.Closer{&tarEntryCloser{stream: stream}}}, nil
+	}
+}
+
+// tarEntryCloser releases the shared stream once the entry is read
+type tarEntryCloser struct {
+	once   sync.Once
+	stream *tarStream
+	// reuse keeps the stream open for the next entry until it's idle, otherwise it's closed right away
+	reuse bool
+}
+
+func (c *tarEntryCloser) Close() error {
+	c.once.Do(func() {
+		if c.reuse {
+			c.stream.idle = time.AfterFunc(archiveIdleTimeout, c.stream.closeIdle)
+		} else {
+			c.stream.close()
+		}
+		c.stream.mu.Unlock()
+	})
+	return nil
+}
+
+// entryReader reads an archive entry, closing the entry and its archive once done
+type entryReader struct {
+	io.Reader
+	closers []io.Closer
+}
+
+func (e entryReader) Close() (err error) {
+	for _, c := range e.closers {
+		if closeErr := c.Close(); err == nil {
+			err = closeErr
+		}
+	}
+	return err
+}
+
+// cappedReader fails with ErrArchiveTooLarge instead of reading more than the remaining bytes
+type cappedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.remaining <= 0 {
+		// Only fail if there is more to read than the cap
+		var probe [1]byte
+		if n, err := c.r.Read(probe[:]); n == 0 {
+			return 0, err
+		}
+		return 0, ErrArchiveTooLarge
+	}
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	return n, err
+}
+
+// detectArchive sniffs the magic bytes of the file to find archives regardless of their extension
+func detectArchive(filePath string) archiveKind {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return notArchive
+	}
+	defer f.Close()
+	header := make([]byte, tarMagicOffset+len(tarMagic))
+	n, _ := io.ReadFull(f, header)
+	switch {
+	case bytes.HasPrefix(header[:n], zipMagic):
+		return zipArchive
+	case bytes.HasPrefix(header[:n], gzipMagic):
+		return gzipArchive
+	case n == len(header) && bytes.Equal(header[tarMagicOffset:], tarMagic):
+		return tarArchive
+	}
+	return notArchive
+}
@@ -19,4 +19,28 @@ package file
 const (
 	notTrackedDir string = "This does not seem to be a git tracked directory. Exiting"
 	gitErr        string = "Failed to find any git files. Exiting"
+	//negationPrefix marks an ignore pattern which re-includes matching files
+	negationPrefix string = "!"
+	//dirSuffix marks an ignore pattern which only matches directories
+	dirSuffix string = "/"
+	//gitDirPattern ignores the git metadata of the repositories, it's applied unless the git directories are scanned
+	gitDirPattern string = "**/*.git/**"
+	//gitDir is the directory of the git metadata of a repository, skipped by the walk unless the git directories are scanned
+	gitDir string = ".git"
+	//builtinIgnoreSource is the source of the ignore patterns applied without an ignore file
+	builtinIgnoreSource string = "built-in"
+	//forceIncludeSource is the source of the force-include patterns of the configuration
+	forceIncludeSource string = "--force-include"
+	//gitignoreFile is the ignore file discovered in every directory of the scan, scoped to that directory
+	gitignoreFile string = ".gitignore"
+	//archiveSeparator separates the path of an archive from the path of an entry inside of it, e.g. bundle.zip!/config/app.properties
+	archiveSeparator string = "!/"
+	//The reasons the files are skipped, counted in the summary of the report
+	skipIgnored    string = "ignored"
+	skipTooLarge   string = "too_large"
+	skipExtension  string = "extension"
+	skipUnmodified string = "unmodified"
+	skipMissing    string = "missing"
+	//stdinList reads the list of files to scan from the standard input
+	stdinList string = "-"
 )
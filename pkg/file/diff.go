@@ -0,0 +1,70 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"io"
+	"os"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/git"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// GetDiffFiles builds the list of the lines added by the unified diff of the git range of the search directory, or by
+// the diff read from the standard input without a git range, e.g. the diff of a pull request.  Only the added lines are
+// scanned, with their line numbers in the new version of their file, so the findings already there before the diff aren't
+// reported again.
+func GetDiffFiles(cfg *cfgreader.EarlybirdConfig) (fileContext Context, err error) {
+	var diff io.Reader = os.Stdin
+	if cfg.GitRange != "" {
+		// No context lines, only the added lines are scanned
+		output, err := gitOutput(cfg.SearchDir, "diff", "--relative", "--no-color", "--no-ext-diff", "--find-renames", "--unified=0", cfg.GitRange, "--")
+		if err != nil {
+			return fileContext, err
+		}
+		diff = bytes.NewReader(output)
+	}
+	return diffFiles(cfg, diff)
+}
+
+// diffFiles lists the files of the diff with the lines it adds, the paths of the diff are relative to the search directory
+func diffFiles(cfg *cfgreader.EarlybirdConfig, diff io.Reader) (fileContext Context, err error) {
+	setScanPatterns(cfg, cfg.SearchDir)
+
+	files, err := git.ParseAddedLines(diff, cfg.SearchDir)
+	if err != nil {
+		return fileContext, err
+	}
+	for _, f := range files {
+		if isIgnoredFile(f.Path, cfg.SearchDir) {
+			fileContext.skip(f.Path, skipIgnored)
+			if cfg.VerboseEnabled {
+				utils.InfoLog.Println("Ignoring", f.Path, ". File blacklisted.")
+			}
+			continue
+		}
+		if skipFilteredExtension(cfg, f.Path) {
+			fileContext.skip(f.Path, skipExtension)
+			continue
+		}
+		fileContext.Files = append(fileContext.Files, f)
+	}
+	fileContext.IgnorePatterns = ignorePatterns
+	return fileContext, nil
+}
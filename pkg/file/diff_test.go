@@ -0,0 +1,128 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// addedLines returns the numbered lines of the files of the context, by path
+func addedLines(t *testing.T, fileContext Context) map[string][]string {
+	t.Helper()
+	got := make(map[string][]string)
+	for _, f := range fileContext.Files {
+		for _, line := range f.Lines {
+			if line.FilePath != f.Path {
+				t.Errorf("line %d of %s has the path %s", line.LineNum, f.Path, line.FilePath)
+			}
+			got[f.Path] = append(got[f.Path], fmt.Sprintf("%d: %s", line.LineNum, line.LineValue))
+		}
+	}
+	return got
+}
+
+func TestDiffFiles(t *testing.T) {
+	diff := `diff --git a/settings.py b/settings.py
+--- a/settings.py
++++ b/settings.py
+@@ -1,2 +1,3 @@
+ api_key = 'unchanged_secret'
++token = 'added_secret'
+ debug = True
+diff --git a/sub/app.py b/sub/app.py
+--- a/sub/app.py
++++ b/sub/app.py
+@@ -4,0 +5 @@
++password = 'added_password'
+diff --git a/logo.png b/logo.png
+--- a/logo.png
++++ b/logo.png
+@@ -1 +1 @@
+-old
++new
+`
+	cfg := cfgreader.EarlybirdConfig{
+		SearchDir:         "/repo",
+		IgnoreFile:        path.Join(projectRoot, ".ge_ignore"),
+		ExcludeExtensions: []string{".png"},
+	}
+	fileContext, err := diffFiles(&cfg, strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("diffFiles() err = %v", err)
+	}
+	want := map[string][]string{
+		"/repo/settings.py": {"2: token = 'added_secret'"},
+		"/repo/sub/app.py":  {"5: password = 'added_password'"},
+	}
+	if got := addedLines(t, fileContext); !reflect.DeepEqual(got, want) {
+		t.Errorf("diffFiles() = %v, want %v", got, want)
+	}
+	if skipped := []string{"/repo/logo.png"}; !reflect.DeepEqual(fileContext.SkippedFiles, skipped) {
+		t.Errorf("diffFiles() skipped %v, want %v", fileContext.SkippedFiles, skipped)
+	}
+}
+
+func TestGetDiffFilesRange(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	writeFile := func(name, content string) {
+		if err := os.WriteFile(path.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitCommand(t, repo, "init", "-q")
+	writeFile("settings.py", "api_key = 'unchanged_secret'\ndebug = True\n")
+	writeFile("removed.py", "password = 'removed_secret'\n")
+	gitCommand(t, repo, "add", "-A")
+	gitCommand(t, repo, "commit", "-q", "-m", "Initial commit")
+	writeFile("settings.py", "api_key = 'unchanged_secret'\ndebug = False\ntoken = 'added_secret'\n")
+	gitCommand(t, repo, "rm", "-q", "removed.py")
+	gitCommand(t, repo, "add", "-A")
+	gitCommand(t, repo, "commit", "-q", "-m", "Add a token")
+
+	cfg := cfgreader.EarlybirdConfig{
+		SearchDir:  repo,
+		IgnoreFile: path.Join(projectRoot, ".ge_ignore"),
+		GitRange:   "HEAD~1..HEAD",
+		DiffOnly:   true,
+	}
+	fileContext, err := GetDiffFiles(&cfg)
+	if err != nil {
+		t.Fatalf("GetDiffFiles() err = %v", err)
+	}
+	want := map[string][]string{
+		path.Join(repo, "settings.py"): {"2: debug = False", "3: token = 'added_secret'"},
+	}
+	if got := addedLines(t, fileContext); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetDiffFiles() = %v, want %v", got, want)
+	}
+
+	cfg.GitRange = "unknown..HEAD"
+	if _, err := GetDiffFiles(&cfg); err == nil {
+		t.Error("GetDiffFiles() of an unknown revision err = nil, want an error")
+	}
+}
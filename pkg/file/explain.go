@@ -0,0 +1,123 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// IgnoreMatch explains whether a file is left out of the scan by the ignore patterns
+type IgnoreMatch struct {
+	// Path is the file explained
+	Path string
+	// Ignored is set when the file is left out of the scan
+	Ignored bool
+	// Pattern is the ignore pattern deciding, as written in its source: the pattern ignoring the file or one of its
+	// parent directories, or the negation or force-include pattern re-including the file.  It's empty when no pattern
+	// matches the file.
+	Pattern string
+	// Source is the ignore file of the pattern, built-in for the patterns applied to every scan, or --force-include for
+	// the force-include patterns re-including an ignored file
+	Source string
+	// Match is the path matched by the pattern, relative to the directory scanned: the file or one of its parent directories
+	Match string
+}
+
+// ExplainIgnore finds the ignore pattern, and its ignore file, deciding whether the file is left out of the scan of the
+// configuration.  The patterns are evaluated like in the walk of the scan: the built-in patterns, the .gitignore and the
+// .ge_ignore of the directory scanned, the ignore file of the configuration, then the .gitignore files of the
+// subdirectories down to the file.  A relative path is relative to the directory scanned.  Only the ignore patterns are
+// explained, the extension, modification and size filters aren't.
+func ExplainIgnore(cfg *cfgreader.EarlybirdConfig, filePath string) (IgnoreMatch, error) {
+	root := cfg.SearchDir
+	if !filepath.IsAbs(filePath) {
+		filePath = filepath.Join(root, filePath)
+	}
+	// With several directories scanned, each one has its own ignore files
+	for _, dir := range cfg.SearchDirs {
+		if rel, err := filepath.Rel(dir, filePath); err == nil && !strings.HasPrefix(rel, "..") {
+			root = dir
+		}
+	}
+	explained := IgnoreMatch{Path: filePath}
+
+	rootRules := sourceRules(builtinIgnoreSource, builtinIgnorePatterns(cfg), cfg.IgnoreCaseSensitive)
+	sources := make([]string, 0, len(ignoreFiles)+1)
+	for _, ignoreFile := range ignoreFiles {
+		sources = append(sources, path.Join(root, ignoreFile))
+	}
+	if cfg.IgnoreFile != "" {
+		sources = append(sources, cfg.IgnoreFile)
+	}
+	for _, source := range sources {
+		if !Exists(source) {
+			continue
+		}
+		patterns, err := readIgnoreFile(source)
+		if err != nil {
+			return explained, err
+		}
+		rootRules = append(rootRules, sourceRules(source, patterns, cfg.IgnoreCaseSensitive)...)
+	}
+	scopes := []ignoreScope{{rules: rootRules}}
+
+	// The .gitignore files of the subdirectories, from the shallowest to the deepest
+	rel, err := filepath.Rel(root, filepath.Dir(filePath))
+	if err == nil && rel != "." && !strings.HasPrefix(rel, "..") {
+		dir := root
+		for _, name := range strings.Split(rel, string(filepath.Separator)) {
+			dir = filepath.Join(dir, name)
+			source := filepath.Join(dir, gitignoreFile)
+			if !Exists(source) {
+				continue
+			}
+			patterns, err := readIgnoreFile(source)
+			if err != nil {
+				return explained, err
+			}
+			trimmedDir := filepath.ToSlash(strings.Replace(dir, root, "", 1))
+			scopes = append(scopes, ignoreScope{dir: trimmedDir, rules: sourceRules(source, patterns, cfg.IgnoreCaseSensitive)})
+		}
+	}
+
+	rule, matched := decidingRule(filePath, root, scopes)
+	if rule != nil && !rule.negated {
+		// The force-include patterns take precedence over the ignore patterns
+		if forced, forcedMatch := forceIncludingRule(filePath, root, sourceRules(forceIncludeSource, cfg.ForceInclude, cfg.IgnoreCaseSensitive)); forced != nil {
+			rule, matched = forced, forcedMatch
+		}
+	}
+	if rule != nil {
+		explained.Ignored = !rule.negated && rule.source != forceIncludeSource
+		explained.Pattern, explained.Source = rule.pattern, rule.source
+		explained.Match = strings.TrimPrefix(matched, "/")
+	}
+	return explained, nil
+}
+
+// sourceRules compiles the patterns of the ignore file, keeping it as the source of the rules
+func sourceRules(source string, patterns []string, caseSensitive bool) []ignoreRule {
+	rules := compileIgnorePatterns(patterns, caseSensitive)
+	for i := range rules {
+		rules[i].source = source
+	}
+	return rules
+}
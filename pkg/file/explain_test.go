@@ -0,0 +1,136 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+func TestExplainIgnore(t *testing.T) {
+	searchDir := t.TempDir()
+	ignoreFile := filepath.Join(t.TempDir(), "ignore")
+	for name, content := range map[string]string{
+		".gitignore":          "*.log\nbuild/\n!keep.log\n",
+		".ge_ignore":          "# environment files\n*.env\n",
+		"sub/.gitignore":      "local.yaml\n",
+		"debug.log":           "",
+		"keep.log":            "",
+		"prod.env":            "",
+		"main.go":             "",
+		"build/app.js":        "",
+		"secrets/token.txt":   "",
+		"sub/local.yaml":      "",
+		"sub/settings.yaml":   "",
+		"build/sub/keep.log":  "",
+		"build/vendored/a.js": "",
+		"other/sub/local.yml": "",
+	} {
+		filePath := filepath.Join(searchDir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(ignoreFile, []byte("**/secrets/**\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	gitignore, geIgnore := filepath.Join(searchDir, ".gitignore"), filepath.Join(searchDir, ".ge_ignore")
+
+	tests := []struct {
+		name     string
+		filePath string
+		want     IgnoreMatch
+	}{
+		{
+			name:     "Pattern of the .gitignore",
+			filePath: "debug.log",
+			want:     IgnoreMatch{Ignored: true, Pattern: "*.log", Source: gitignore, Match: "debug.log"},
+		},
+		{
+			name:     "Negation re-including the file",
+			filePath: "keep.log",
+			want:     IgnoreMatch{Pattern: "!keep.log", Source: gitignore, Match: "keep.log"},
+		},
+		{
+			name:     "Pattern of the .ge_ignore",
+			filePath: "prod.env",
+			want:     IgnoreMatch{Ignored: true, Pattern: "*.env", Source: geIgnore, Match: "prod.env"},
+		},
+		{
+			name:     "Pattern of the ignore file of the configuration",
+			filePath: "secrets/token.txt",
+			want:     IgnoreMatch{Ignored: true, Pattern: "**/secrets/**", Source: ignoreFile, Match: "secrets/token.txt"},
+		},
+		{
+			name:     "Pattern of the parent directory",
+			filePath: "build/app.js",
+			want:     IgnoreMatch{Ignored: true, Pattern: "build/", Source: gitignore, Match: "build"},
+		},
+		{
+			name:     "Negation can't re-include a file of an ignored directory",
+			filePath: "build/sub/keep.log",
+			want:     IgnoreMatch{Ignored: true, Pattern: "build/", Source: gitignore, Match: "build"},
+		},
+		{
+			name:     "Force-include pattern re-including a file of an ignored directory",
+			filePath: "build/vendored/a.js",
+			want:     IgnoreMatch{Pattern: "build/vendored/**", Source: forceIncludeSource, Match: "build/vendored/a.js"},
+		},
+		{
+			name:     "Pattern of a nested .gitignore",
+			filePath: "sub/local.yaml",
+			want:     IgnoreMatch{Ignored: true, Pattern: "local.yaml", Source: filepath.Join(searchDir, "sub", ".gitignore"), Match: "sub/local.yaml"},
+		},
+		{
+			name:     "Nested .gitignore only applies to its directory",
+			filePath: "other/sub/local.yml",
+			want:     IgnoreMatch{},
+		},
+		{
+			name:     "Built-in pattern",
+			filePath: ".git/config",
+			want:     IgnoreMatch{Ignored: true, Pattern: gitDirPattern, Source: builtinIgnoreSource, Match: ".git/config"},
+		},
+		{
+			name:     "No pattern matches",
+			filePath: filepath.Join(searchDir, "main.go"),
+			want:     IgnoreMatch{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExplainIgnore(&cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: ignoreFile,
+				ForceInclude: []string{"build/vendored/**"}}, tt.filePath)
+			if err != nil {
+				t.Fatalf("ExplainIgnore() err = %v", err)
+			}
+			tt.want.Path = tt.filePath
+			if !filepath.IsAbs(tt.filePath) {
+				tt.want.Path = filepath.Join(searchDir, tt.filePath)
+			}
+			if got != tt.want {
+				t.Errorf("ExplainIgnore() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
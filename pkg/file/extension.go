@@ -0,0 +1,69 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+
+package file
+
+import (
+	"strings"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// isFilteredExtension reports whether the file is left out by the extension filters: its extension is excluded, or
+// extensions are included and its extension isn't one of them.  The exclusion wins when an extension is in both lists.
+func isFilteredExtension(name string, include, exclude []string) bool {
+	name = strings.ToLower(name)
+	for _, ext := range exclude {
+		if strings.HasSuffix(name, ext) {
+			return true
+		}
+	}
+	if len(include) == 0 {
+		return false
+	}
+	for _, ext := range include {
+		if strings.HasSuffix(name, ext) {
+			return false
+		}
+	}
+	return true
+}
+
+// skipFilteredExtension reports whether the file is left out by the extension filters of the config, logging it
+func skipFilteredExtension(cfg *cfgreader.EarlybirdConfig, path string) bool {
+	if !isFilteredExtension(path, cfg.IncludeExtensions, cfg.ExcludeExtensions) {
+		return false
+	}
+	if cfg.VerboseEnabled {
+		utils.InfoLog.Println("Ignoring", path, ". File extension filtered.")
+	}
+	return true
+}
+
+// filterExtensions returns the files kept by the extension filters, the files left out are skipped
+func filterExtensions(cfg *cfgreader.EarlybirdConfig, files []scan.File, fileContext *Context) (kept []scan.File) {
+	for _, f := range files {
+		if skipFilteredExtension(cfg, f.Path) {
+			fileContext.skip(f.Path, skipExtension)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
@@ -21,6 +21,7 @@ import (
 	"bufio"
 	"bytes"
 	"code.sajari.com/docconv"
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io"
@@ -39,21 +40,28 @@ import (
 )
 
 var (
-	ignoreFiles    = [...]string{".ge_ignore"}
+	// ignoreFiles are read from the root of the scan from the lowest to the highest priority
+	ignoreFiles    = [...]string{gitignoreFile, ".ge_ignore"}
 	ignorePatterns []string
+	// ignoreCaseSensitive stops folding the case of the ignore patterns and the file paths when matching
+	ignoreCaseSensitive bool
+	// ignoreRules are the ignorePatterns compiled once for the whole scan
+	ignoreRules []ignoreRule
+	// forceIncludeRules are the patterns of the paths scanned even when they're ignored
+	forceIncludeRules []ignoreRule
 )
 
 // MultipartToScanFiles converts the multipart file upload into Earlybird files
 func MultipartToScanFiles(files []*multipart.FileHeader, cfg cfgreader.EarlybirdConfig) (fileList []scan.File, err error) {
-	ignorePatterns = getIgnorePatterns(cfg.SearchDir, cfg.IgnoreFile, cfg.VerboseEnabled)
+	setScanPatterns(&cfg, cfg.SearchDir)
 
-    var buffer bytes.Buffer
+	var buffer bytes.Buffer
 	for _, fheader := range files {
 		myfile, err := fheader.Open()
 		if err != nil {
 			return fileList, err
 		}
-        defer myfile.Close()
+		defer myfile.Close()
 		// Per the HTTP spec, The filename directive of multipart form data will have it's path information stripped https://developer.mozilla.org/en-US/docs/Web/HTTP/Headers/Content-Disposition.
 		// .ge_ignore file only works on absolute paths, not the basename of a file
 		// client will send filepath as base64 encoded and earlybird will decode to get the full path
@@ -137,42 +145,63 @@ func MultipartToScanFiles(files []*multipart.FileHeader, cfg cfgreader.Earlybird
 
 // GetGitFiles Builds the list of staged or tracked files
 func GetGitFiles(fileType string, cfg *cfgreader.EarlybirdConfig) (fileContext Context, err error) {
-	ignorePatterns = getIgnorePatterns(cfg.SearchDir, cfg.IgnoreFile, cfg.VerboseEnabled)
+	setScanPatterns(cfg, cfg.SearchDir)
 
 	var (
-		output       []byte
-		compressList []scan.File
-		convertList  []scan.File
-		fileList     []scan.File
-		skipList     []string
+		output   []byte
+		fileList []scan.File
 	)
 
 	if fileType == utils.Tracked {
 		output, err = exec.Command("git", "ls-tree", "--full-tree", "-r", "--name-only", "HEAD").Output()
 	} else if fileType == utils.Staged {
 		output, err = exec.Command("git", "--no-pager", "diff", "--name-only", "--staged").Output()
+	} else if fileType == utils.Range {
+		output, err = gitRangeFiles(cfg.SearchDir, cfg.GitRange)
 	}
 
 	if err != nil {
-		log.Println(notTrackedDir)
+		if fileType != utils.Range {
+			log.Println(notTrackedDir)
+		}
 		return fileContext, err
 	}
 
-	fileList, skipList = parseGitFiles(output, cfg.VerboseEnabled, cfg.MaxFileSize, cfg.SearchDir)
+	fileList = parseGitFiles(output, cfg.VerboseEnabled, cfg.MaxFileSize, cfg.SearchDir, &fileContext)
+	collectFiles(cfg, fileList, &fileContext)
+	return fileContext, nil
+}
+
+// collectFiles filters the files listed into the context, adding the files within the archives and the converted files
+func collectFiles(cfg *cfgreader.EarlybirdConfig, fileList []scan.File, fileContext *Context) {
+	var compressList, convertList []scan.File
+	fileList = filterExtensions(cfg, fileList, fileContext)
+	fileList = filterModified(cfg, fileList, fileContext)
 	compressList, fileList = separateCompressedAndUncompressed(fileList)
-	compressList, fileContext.CompressPaths, err = GetCompressedFiles(compressList, cfg.SearchDir) //Get the files within our compressed list
-	if err != nil {
-		return fileContext, err
-	}
+	compressList = GetArchiveFiles(compressList, cfg, fileContext) //Get the files within our compressed list
 	fileContext.Files = append(fileList, compressList...)
 	convertList, fileContext.ConvertPaths = GetConvertedFiles(fileContext.Files) //Get the files that need to be converted and convert them to plaintext
 	fileContext.Files = append(fileContext.Files, convertList...)
 	fileContext.IgnorePatterns = ignorePatterns
-	fileContext.SkippedFiles = skipList
-	return fileContext, nil
 }
 
-func parseGitFiles(out []byte, verbose bool, maxFileSize int64, searchDir string) (fileList []scan.File, skipList []string) {
+// gitRangeFiles lists the files of the search directory added, modified or renamed in the git revision range, as absolute paths.
+// Deleted files are left out and renamed files are listed at their new path.
+func gitRangeFiles(searchDir, revisionRange string) ([]byte, error) {
+	output, err := gitOutput(searchDir, "diff", "--name-only", "--relative", "--diff-filter=d", "--find-renames", revisionRange, "--")
+	if err != nil {
+		return nil, err
+	}
+
+	var files bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		files.WriteString(filepath.Join(searchDir, scanner.Text()) + "\n")
+	}
+	return files.Bytes(), nil
+}
+
+func parseGitFiles(out []byte, verbose bool, maxFileSize int64, searchDir string, fileContext *Context) (fileList []scan.File) {
 	var curFile scan.File
 	// Convert byteArray to string
 	gitFiles := string(out)
@@ -201,57 +230,77 @@ func parseGitFiles(out []byte, verbose bool, maxFileSize int64, searchDir string
 
 					if !pathIsDirectory && getFileSizeOK(curFile.Path, maxFileSize) {
 						if verbose {
-							log.Println("Reading file ", curFile.Path)
+							utils.InfoLog.Println("Reading file ", curFile.Path)
 						}
 						fileList = append(fileList, curFile)
+					} else if size, err := GetFileSize(curFile.Path); !pathIsDirectory && err == nil && tooLarge(curFile.Path, size, maxFileSize) {
+						fileContext.skip(curFile.Path, skipTooLarge)
+						logTooLarge(curFile.Path, size, maxFileSize)
 					}
 				}
 			} else {
-				skipList = append(skipList, curFile.Path)
+				fileContext.skip(curFile.Path, skipIgnored)
 				if verbose {
-					log.Println("Ignoring", curFile.Path, ". File blacklisted.")
+					utils.InfoLog.Println("Ignoring", curFile.Path, ". File blacklisted.")
 				}
 			}
 		}
 	}
 
-	return fileList, skipList
+	return fileList
 }
 
 // GetFiles Build the list of files
-func GetFiles(searchDir, ignoreFile string, verbose bool, maxFileSize int64) (fileContext Context, err error) {
-	ignorePatterns = getIgnorePatterns(searchDir, ignoreFile, verbose)
+func GetFiles(cfg *cfgreader.EarlybirdConfig) (fileContext Context, err error) {
+	return GetFilesContext(context.Background(), cfg)
+}
+
+// GetFilesContext is GetFiles stopping the walk with the error of the context once it's cancelled
+func GetFilesContext(ctx context.Context, cfg *cfgreader.EarlybirdConfig) (fileContext Context, err error) {
+	searchDir, verbose, maxFileSize := cfg.SearchDir, cfg.VerboseEnabled, cfg.MaxFileSize
+	if info, statErr := os.Stat(searchDir); statErr == nil && info.Mode().IsRegular() {
+		return getTargetFile(cfg, info), nil
+	}
+	setScanPatterns(cfg, searchDir)
 	fileList := make([]scan.File, 0)
 	var curFile scan.File
-	err = filepath.Walk(searchDir, func(path string, f os.FileInfo, err error) error {
+	// The walk is depth first, so the .gitignore files of the current directory and its parents form a stack
+	scopes := []ignoreScope{{rules: ignoreRules}}
+	err = walkFiles(ctx, searchDir, cfg, func(path string, f os.FileInfo, err error) error {
 		if err != nil {
 			log.Println("Error reading directory: ", err)
 		}
-		if !isIgnoredFile(path, searchDir) {
+		if !isIgnoredInScopes(path, searchDir, scopes) {
 			// Ignore the path if it's a directory
 			pathIsDirectory, isDirErr := isDirectory(path)
-			if !pathIsDirectory {
+			if pathIsDirectory {
+				scopes = pushIgnoreScope(scopes, path, searchDir, verbose)
+			} else {
 				if isDirErr != nil && verbose {
 					log.Println("Error checking if path is directory")
 				}
-				if getFileSizeOK(path, maxFileSize) {
+				if skipFilteredExtension(cfg, path) {
+					fileContext.skip(path, skipExtension)
+				} else if skipUnmodifiedFile(cfg, path, f) {
+					fileContext.skip(path, skipUnmodified)
+				} else if getFileSizeOK(path, maxFileSize) {
 					curFile.Name = f.Name()
 					curFile.Path = path
 					fileList = append(fileList, curFile)
 					if verbose {
-						log.Println("Reading file ", curFile.Path)
+						utils.InfoLog.Println("Reading file ", curFile.Path)
 					}
 				} else {
-					fileContext.SkippedFiles = append(fileContext.SkippedFiles, path)
-					if verbose {
-						log.Println("Ignoring", path, ". Filesize is too large.")
+					fileContext.skip(path, skipTooLarge)
+					if size, err := GetFileSize(path); err == nil && tooLarge(path, size, maxFileSize) {
+						logTooLarge(path, size, maxFileSize)
 					}
 				}
 			}
 		} else {
-			fileContext.SkippedFiles = append(fileContext.SkippedFiles, path)
+			fileContext.skip(path, skipIgnored)
 			if verbose {
-				log.Println("Ignoring", path, ". File blacklisted.")
+				utils.InfoLog.Println("Ignoring", path, ". File blacklisted.")
 			}
 		}
 		return err
@@ -259,25 +308,51 @@ func GetFiles(searchDir, ignoreFile string, verbose bool, maxFileSize int64) (fi
 	if err != nil {
 		return fileContext, err
 	}
+	addFiles(cfg, fileList, &fileContext)
+	return fileContext, nil
+}
 
+// getTargetFile builds the context of a scan targeting a single file.  The file is scanned even when the ignore patterns
+// of its directory exclude it, as it's named explicitly, but the extension, modification and size filters still apply.
+func getTargetFile(cfg *cfgreader.EarlybirdConfig, info os.FileInfo) (fileContext Context) {
+	filePath := cfg.SearchDir
+	// The patterns still apply to the entries of an archive
+	setScanPatterns(cfg, filepath.Dir(filePath))
+	var fileList []scan.File
+	if skipFilteredExtension(cfg, filePath) {
+		fileContext.skip(filePath, skipExtension)
+	} else if skipUnmodifiedFile(cfg, filePath, info) {
+		fileContext.skip(filePath, skipUnmodified)
+	} else if tooLarge(filePath, info.Size(), cfg.MaxFileSize) {
+		fileContext.skip(filePath, skipTooLarge)
+		logTooLarge(filePath, info.Size(), cfg.MaxFileSize)
+	} else {
+		fileList = append(fileList, scan.File{Name: info.Name(), Path: filePath})
+	}
+	addFiles(cfg, fileList, &fileContext)
+	return fileContext
+}
+
+// addFiles adds the files listed to the context, along with the entries of the archives and the converted documents
+func addFiles(cfg *cfgreader.EarlybirdConfig, fileList []scan.File, fileContext *Context) {
 	var compressList, convertList []scan.File
 	compressList, fileList = separateCompressedAndUncompressed(fileList)
-	compressList, fileContext.CompressPaths, err = GetCompressedFiles(compressList, searchDir) //Get the files within our compressed list
-	if err != nil {
-		return fileContext, err
-	}
+	compressList = GetArchiveFiles(compressList, cfg, fileContext) //Get the files within our compressed list
 	fileContext.Files = append(fileList, compressList...)
 	convertList, fileContext.ConvertPaths = GetConvertedFiles(fileContext.Files) //Get the files that need to be converted and convert them to plaintext
 	fileContext.Files = append(fileContext.Files, convertList...)
 	fileContext.IgnorePatterns = ignorePatterns
-	return fileContext, nil
 }
 
 // GetFileFromStream Builds a file as a collection of lines from the input stream.
 // This will be fed to the scan modules.
-func GetFileFromStream(cfg *cfgreader.EarlybirdConfig) []scan.File {
-	// Read Stdin
-	scanner := bufio.NewScanner(os.Stdin)
+func GetFileFromStream(cfg *cfgreader.EarlybirdConfig) ([]scan.File, error) {
+	return readStreamFile(cfg, os.Stdin)
+}
+
+// readStreamFile builds the file of the lines read from r, returning the error of the stream when it can't be read
+func readStreamFile(cfg *cfgreader.EarlybirdConfig, r io.Reader) ([]scan.File, error) {
+	scanner := bufio.NewScanner(r)
 	// The scan modules will expect a list of Files, so create that list with just one
 	fileList := make([]scan.File, 0)
 	curFile := scan.File{
@@ -314,10 +389,10 @@ func GetFileFromStream(cfg *cfgreader.EarlybirdConfig) []scan.File {
 		}
 	}
 	if err := scanner.Err(); err != nil {
-		log.Fatal("Reading standard input:", err)
+		return nil, fmt.Errorf("reading standard input: %w", err)
 	}
 	fileList = append(fileList, curFile)
-	return fileList
+	return fileList, nil
 }
 
 // GetFileSize returns the file size of target file
@@ -329,86 +404,257 @@ func GetFileSize(path string) (size int64, err error) {
 	return stat.Size(), nil
 }
 
-// Make sure the filesize is lower than the MAX_FILE_SIZE threshold so bufio doesn't fail
+// Make sure the filesize is within the MAX_FILE_SIZE threshold so bufio doesn't fail, the size is checked before reading the file
 func getFileSizeOK(path string, maxFileSize int64) bool {
 	size, err := GetFileSize(path)
-	return err == nil && size != 0 && (size < maxFileSize || hasCompressionExtension(path))
+	return err == nil && size != 0 && !tooLarge(path, size, maxFileSize)
+}
+
+// tooLarge determines if the file exceeds the maximum file size
+func tooLarge(path string, size, maxFileSize int64) bool {
+	return size > maxFileSize && !hasCompressionExtension(path)
 }
 
+// logTooLarge notices a file skipped for exceeding the maximum file size, so the gaps in the scan coverage are known
+func logTooLarge(path string, size, maxFileSize int64) {
+	utils.InfoLog.Printf("Skipping %s, its size of %d bytes exceeds the maximum file size of %d bytes\n", path, size, maxFileSize)
+}
+
+// Archives are exempt from the size limit, which applies to each of their entries instead
 func hasCompressionExtension(path string) bool {
 	switch filepath.Ext(path) {
-	case "war", "jar", "zip", "ear":
+	case ".war", ".jar", ".zip", ".ear", ".tar", ".gz", ".tgz":
 		return true
 	default:
 		return false
 	}
 }
 
+// builtinIgnorePatterns returns the ignore patterns applied without an ignore file
+func builtinIgnorePatterns(cfg *cfgreader.EarlybirdConfig) []string {
+	if cfg.ScanGitDir {
+		return nil
+	}
+	return []string{gitDirPattern}
+}
+
 // Read in .ge_ignore file and ignore files matching the patterns
 func getIgnorePatterns(filePath, ignoreFile string, verbose bool) (ignorePatterns []string) {
-	ignorePatterns = append(ignorePatterns, "*.git/*")
-
-	// Loop through the files defined to contain ignore patterns (.ge_ignore, .gitignore, etc.)
+	// Loop through the files defined to contain ignore patterns (.gitignore, .ge_ignore, etc.)
+	var sources []string
 	for _, ignoreFile := range ignoreFiles {
-		actualFilePath := path.Join(filePath, ignoreFile)
-		if Exists(actualFilePath) {
-			file, err := os.Open(actualFilePath)
-			if err != nil {
-				log.Fatal(err)
-			}
-			defer file.Close()
-			scanner := bufio.NewScanner(file)
-			var line, firstChar string
-			for scanner.Scan() {
-				line = scanner.Text()
-
-				// Ignore comment lines (starting with #)
-				runes := []rune(line)
-				firstChar = string(runes[0:1])
-				if !(firstChar == "#") && strings.Trim(line, " ") != "" {
-					ignorePatterns = append(ignorePatterns, line)
-				}
-			}
-		}
+		sources = append(sources, path.Join(filePath, ignoreFile))
 	}
+	ignorePatterns = append(ignorePatterns, mergeIgnoreSources(sources)...)
 
 	if ignoreFile != "" {
-		file, err := os.Open(ignoreFile)
+		patterns, err := readIgnoreFile(ignoreFile)
 		if err != nil {
 			log.Println("Failed to open ignore file", err)
 		} else {
-			defer file.Close()
-			scanner := bufio.NewScanner(file)
-			var line, firstChar string
-			for scanner.Scan() {
-				line = scanner.Text()
-
-				// Ignore comment lines (starting with #)
-				runes := []rune(line)
-				firstChar = string(runes[0:1])
-				if !(firstChar == "#") && strings.Trim(line, " ") != "" {
-					ignorePatterns = append(ignorePatterns, line)
-				}
-			}
+			ignorePatterns = append(ignorePatterns, patterns...)
 		}
 	}
 
 	if verbose {
-		log.Println("Ignore pattern: ", strings.Join(ignorePatterns, ", "))
+		utils.InfoLog.Println("Ignore pattern: ", strings.Join(ignorePatterns, ", "))
 	}
 	return ignorePatterns
 }
 
-// If the file matches a pattern in one of the ignore files, return true
+// mergeIgnoreSources reads the ignore files from the lowest to the highest priority into a single ordered list of patterns.
+// As the last matching pattern wins, the patterns of a later file override the earlier ones, e.g. a .ge_ignore negation
+// re-includes a file excluded by the .gitignore.  Missing files are skipped.
+func mergeIgnoreSources(sources []string) (patterns []string) {
+	for _, source := range sources {
+		if !Exists(source) {
+			continue
+		}
+		sourcePatterns, err := readIgnoreFile(source)
+		if err != nil {
+			log.Println("Failed to open ignore file", err)
+			continue
+		}
+		patterns = append(patterns, sourcePatterns...)
+	}
+	return patterns
+}
+
+// loadIgnorePatterns reads the .gitignore file in dir, its patterns only apply to the paths under dir
+func loadIgnorePatterns(dir string, verbose bool) (patterns []string) {
+	gitignorePath := filepath.Join(dir, gitignoreFile)
+	if !Exists(gitignorePath) {
+		return nil
+	}
+	patterns, err := readIgnoreFile(gitignorePath)
+	if err != nil {
+		log.Println("Failed to open ignore file", err)
+		return nil
+	}
+	if verbose && len(patterns) > 0 {
+		utils.InfoLog.Println("Ignore pattern from", gitignorePath, ": ", strings.Join(patterns, ", "))
+	}
+	return patterns
+}
+
+// readIgnoreFile returns the patterns in the ignore file, skipping blank and comment lines (starting with #)
+func readIgnoreFile(filePath string) (patterns []string, err error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "#") && strings.Trim(line, " ") != "" {
+			patterns = append(patterns, line)
+		}
+	}
+	return patterns, scanner.Err()
+}
+
+// If the file, or one of its parent directories, matches a pattern in one of the ignore files, return true
 func isIgnoredFile(fileName string, fileRoot string) bool {
+	return isIgnoredInScopes(fileName, fileRoot, []ignoreScope{{rules: ignoreRules}})
+}
+
+// isIgnoredInScopes is isIgnoredFile for a walk with nested ignore files.  Each scope only applies to the paths
+// under its directory and the scopes are evaluated from the shallowest to the deepest, so deeper files override shallower ones.
+func isIgnoredInScopes(fileName string, fileRoot string, scopes []ignoreScope) bool {
+	rule, _ := decidingRule(fileName, fileRoot, scopes)
+	if rule == nil || rule.negated {
+		return false
+	}
+	// The force-include patterns are applied after the ignore patterns and take precedence over them
+	forced, _ := forceIncludingRule(fileName, fileRoot, forceIncludeRules)
+	return forced == nil
+}
+
+// forceIncludingRule returns the force-include rule matching the file or one of its parent directories and the root
+// relative path it matched, or nil when the file isn't force-included.  Like for the ignore patterns, the last rule
+// matching the deepest path decides, so a negated force-include pattern leaves the files it matches ignored.
+func forceIncludingRule(fileName string, fileRoot string, rules []ignoreRule) (forced *ignoreRule, matchedPath string) {
+	if len(rules) == 0 {
+		return nil, ""
+	}
+	trimmedName := filepath.ToSlash(strings.Replace(fileName, fileRoot, "", 1))
+	isDir := false
+	for p := trimmedName; p != "" && p != "/" && p != "."; p = path.Dir(p) {
+		if rule := lastMatchingRule(p, isDir, rules); rule != nil {
+			if rule.negated {
+				return nil, ""
+			}
+			return rule, p
+		}
+		isDir = true
+	}
+	return nil, ""
+}
+
+// decidingRule returns the rule deciding if the file is ignored and the root relative path it matched: the rule ignoring
+// the file or one of its parent directories, else the last rule matching the file without ignoring it, e.g. a negation
+// re-including it, or nil when no rule matches
+func decidingRule(fileName string, fileRoot string, scopes []ignoreScope) (decider *ignoreRule, matchedPath string) {
 	// ignore root directory when checking ignore matching
-	trimmedName := strings.Replace(fileName, fileRoot, "", 1)
-	for _, pattern := range ignorePatterns {
-		if wildcard.PatternMatch(trimmedName, pattern) {
-			return true
+	trimmedName := filepath.ToSlash(strings.Replace(fileName, fileRoot, "", 1))
+	// Like git, everything inside an ignored directory is ignored as well and can't be re-included
+	isDir := false
+	for p := trimmedName; p != "" && p != "/" && p != "."; p = path.Dir(p) {
+		var last *ignoreRule
+		for _, scope := range scopes {
+			if !scope.contains(p) {
+				continue
+			}
+			if rule := lastMatchingRule(strings.TrimPrefix(p, scope.dir), isDir, scope.rules); rule != nil {
+				last = rule
+			}
+		}
+		if last != nil && !last.negated {
+			return last, p
+		}
+		if last != nil && decider == nil {
+			decider, matchedPath = last, p
+		}
+		// Every parent of the file is a directory
+		isDir = true
+	}
+	return decider, matchedPath
+}
+
+// contains reports if the root relative path is inside the directory of the scope
+func (scope ignoreScope) contains(name string) bool {
+	return scope.dir == "" || strings.HasPrefix(name, scope.dir+"/")
+}
+
+// pushIgnoreScope adds the scope of the .gitignore file in dir, after dropping the scopes the walk has left
+func pushIgnoreScope(scopes []ignoreScope, dir, fileRoot string, verbose bool) []ignoreScope {
+	trimmedDir := filepath.ToSlash(strings.Replace(dir, fileRoot, "", 1))
+	// The .gitignore of the root is already merged into the root patterns by getIgnorePatterns
+	if trimmedDir == "" || trimmedDir == "/" {
+		return scopes
+	}
+	// The first scope holds the root patterns and always applies
+	for len(scopes) > 1 && !scopes[len(scopes)-1].contains(trimmedDir) {
+		scopes = scopes[:len(scopes)-1]
+	}
+	if patterns := loadIgnorePatterns(dir, verbose); len(patterns) > 0 {
+		scopes = append(scopes, ignoreScope{dir: strings.TrimSuffix(trimmedDir, "/"), rules: compileIgnorePatterns(patterns, ignoreCaseSensitive)})
+	}
+	return scopes
+}
+
+// lastMatchingRule returns the last of the rules matching the name, which decides if it's ignored, or nil when none does
+func lastMatchingRule(name string, isDir bool, rules []ignoreRule) (last *ignoreRule) {
+	for i, rule := range rules {
+		if rule.dirOnly && !isDir {
+			continue
+		}
+		if rule.matcher.Match(name) {
+			last = &rules[i]
 		}
 	}
-	return false
+	return last
+}
+
+// setScanPatterns sets the ignore patterns of the directory scanned along with the force-include patterns of the configuration
+func setScanPatterns(cfg *cfgreader.EarlybirdConfig, searchDir string) {
+	patterns := append(builtinIgnorePatterns(cfg), getIgnorePatterns(searchDir, cfg.IgnoreFile, cfg.VerboseEnabled)...)
+	setIgnorePatterns(patterns, cfg.IgnoreCaseSensitive)
+	forceIncludeRules = sourceRules(forceIncludeSource, cfg.ForceInclude, cfg.IgnoreCaseSensitive)
+}
+
+// setIgnorePatterns replaces the ignore patterns of the scan and compiles them once, before any file is matched
+func setIgnorePatterns(patterns []string, caseSensitive bool) {
+	ignorePatterns = patterns
+	ignoreCaseSensitive = caseSensitive
+	ignoreRules = compileIgnorePatterns(patterns, caseSensitive)
+}
+
+// compileIgnorePatterns parses the ignore patterns into rules, skipping the patterns which aren't valid.
+// The patterns are matched case-insensitively unless caseSensitive is set.
+func compileIgnorePatterns(patterns []string, caseSensitive bool) (rules []ignoreRule) {
+	compile := wildcard.Compile
+	if caseSensitive {
+		compile = wildcard.CompileCase
+	}
+	for _, pattern := range patterns {
+		rule := ignoreRule{pattern: pattern}
+		rule.negated = strings.HasPrefix(pattern, negationPrefix)
+		pattern = strings.TrimPrefix(pattern, negationPrefix)
+		if strings.HasSuffix(pattern, dirSuffix) {
+			rule.dirOnly = true
+			pattern = strings.TrimSuffix(pattern, dirSuffix)
+		}
+		matcher, err := compile(pattern)
+		if err != nil {
+			log.Println("Invalid ignore pattern", pattern, err)
+			continue
+		}
+		rule.matcher = matcher
+		rules = append(rules, rule)
+	}
+	return rules
 }
 
 // Check a path to see if it's a directory
@@ -422,11 +668,7 @@ func isDirectory(path string) (bool, error) {
 
 // GetWD Gets the current working directory
 func GetWD() (string, error) {
-	dir, err := os.Getwd()
-	if err != nil {
-		log.Fatal(err)
-	}
-	return dir, err
+	return os.Getwd()
 }
 
 // IsEmpty Check to see if a directory is empty
@@ -447,9 +689,11 @@ func Exists(path string) bool {
 	return !os.IsNotExist(err)
 }
 
+// separateCompressedAndUncompressed finds the zip, tar and gzip archives by extension or magic bytes.  Documents which are zip based
+// (docx, odt) are converted to plaintext instead.
 func separateCompressedAndUncompressed(files []scan.File) (compressed, uncompressed []scan.File) {
 	for _, f := range files {
-		if scan.CompressPattern.MatchString(f.Path) {
+		if scan.CompressPattern.MatchString(f.Path) || !scan.ConvertPattern.MatchString(f.Path) && detectArchive(f.Path) != notArchive {
 			compressed = append(compressed, f)
 		} else {
 			uncompressed = append(uncompressed, f)
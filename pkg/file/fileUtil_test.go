@@ -17,14 +17,24 @@
 package file
 
 import (
+	"bytes"
+	"sort"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
 	"os/exec"
 	"reflect"
 
+	"errors"
+	"io"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
+	"time"
 )
 
 var projectRoot string
@@ -38,16 +48,17 @@ func init() {
 
 	workDir = workingDir
 	projectRoot = path.Join(workingDir, "../../")
-	ignorePatterns = getIgnorePatterns(projectRoot, path.Join(projectRoot, ".ge_ignore"), false)
+	setIgnorePatterns(getIgnorePatterns(projectRoot, path.Join(projectRoot, ".ge_ignore"), false), false)
 }
 
 func TestGetFiles(t *testing.T) {
-	searchDir := "test_data"
-	ignoreFile := path.Join(projectRoot, ".ge_ignore")
-	verbose := false
-	maxFileSize := int64(1000000)
+	cfg := cfgreader.EarlybirdConfig{
+		SearchDir:   "test_data",
+		IgnoreFile:  path.Join(projectRoot, ".ge_ignore"),
+		MaxFileSize: int64(1000000),
+	}
 
-	fileContext, err := GetFiles(searchDir, ignoreFile, verbose, maxFileSize)
+	fileContext, err := GetFiles(&cfg)
 	if err != nil {
 		t.Errorf("GetFiles() err = %v", err)
 	}
@@ -128,6 +139,289 @@ func Test_getFileSizeOK(t *testing.T) {
 	}
 }
 
+func TestGetFilesMaxFileSize(t *testing.T) {
+	searchDir := t.TempDir()
+	maxFileSize := int64(1000)
+	sizes := map[string]int64{"under.txt": maxFileSize - 1, "limit.txt": maxFileSize, "over.txt": maxFileSize + 1}
+	for name, size := range sizes {
+		if err := os.WriteFile(path.Join(searchDir, name), []byte(strings.Repeat("a", int(size))), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	var notices bytes.Buffer
+	utils.InfoLog.SetOutput(&notices)
+	defer utils.SetQuiet(false)
+
+	fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: path.Join(projectRoot, ".ge_ignore"), MaxFileSize: maxFileSize})
+	if err != nil {
+		t.Fatalf("GetFiles() err = %v", err)
+	}
+	var got []string
+	for _, file := range fileContext.Files {
+		got = append(got, file.Name)
+	}
+	sort.Strings(got)
+	if want := []string{"limit.txt", "under.txt"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFiles() = %v, want %v", got, want)
+	}
+	if want := []string{path.Join(searchDir, "over.txt")}; !reflect.DeepEqual(fileContext.SkippedFiles, want) {
+		t.Errorf("GetFiles() skipped %v, want %v", fileContext.SkippedFiles, want)
+	}
+	if want := map[string]string{path.Join(searchDir, "over.txt"): skipTooLarge}; !reflect.DeepEqual(fileContext.SkipReasons, want) {
+		t.Errorf("GetFiles() skip reasons %v, want %v", fileContext.SkipReasons, want)
+	}
+	if want := "over.txt, its size of 1001 bytes exceeds the maximum file size of 1000 bytes"; !strings.Contains(notices.String(), want) {
+		t.Errorf("GetFiles() notices = %q, want %q", notices.String(), want)
+	}
+}
+
+func TestGetFilesExtensionFilters(t *testing.T) {
+	searchDir := t.TempDir()
+	for _, name := range []string{"logo.PNG", "app.properties", "main.go"} {
+		if err := os.WriteFile(path.Join(searchDir, name), []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name        string
+		include     []string
+		exclude     []string
+		wantFiles   []string
+		wantSkipped []string
+	}{
+		{
+			name:        "Excluded .png is skipped",
+			exclude:     []string{".png"},
+			wantFiles:   []string{"app.properties", "main.go"},
+			wantSkipped: []string{"logo.PNG"},
+		},
+		{
+			name:        "Included .properties is retained",
+			include:     []string{".properties"},
+			wantFiles:   []string{"app.properties"},
+			wantSkipped: []string{"logo.PNG", "main.go"},
+		},
+		{
+			name:        "Exclusion wins over inclusion",
+			include:     []string{".properties", ".go"},
+			exclude:     []string{".go"},
+			wantFiles:   []string{"app.properties"},
+			wantSkipped: []string{"logo.PNG", "main.go"},
+		},
+		{
+			name:      "No filters",
+			wantFiles: []string{"app.properties", "logo.PNG", "main.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Case-sensitive so the *.png of the project's .ge_ignore leaves logo.PNG to the extension filters
+			fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: path.Join(projectRoot, ".ge_ignore"),
+				IgnoreCaseSensitive: true, MaxFileSize: 1000, IncludeExtensions: tt.include, ExcludeExtensions: tt.exclude})
+			if err != nil {
+				t.Fatalf("GetFiles() err = %v", err)
+			}
+			var gotFiles, gotSkipped []string
+			for _, file := range fileContext.Files {
+				gotFiles = append(gotFiles, file.Name)
+			}
+			for _, skipped := range fileContext.SkippedFiles {
+				gotSkipped = append(gotSkipped, path.Base(skipped))
+			}
+			sort.Strings(gotFiles)
+			sort.Strings(gotSkipped)
+			if !reflect.DeepEqual(gotFiles, tt.wantFiles) {
+				t.Errorf("GetFiles() = %v, want %v", gotFiles, tt.wantFiles)
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("GetFiles() skipped %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestGetFilesForceInclude(t *testing.T) {
+	searchDir := t.TempDir()
+	for _, name := range []string{"main.go", "vendor/acme/lib.go", "vendor/acme/testdata/fixture.go", "vendor/other/lib.go", "debug.log"} {
+		if err := os.MkdirAll(path.Dir(path.Join(searchDir, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path.Join(searchDir, name), []byte("content"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignoreFile := path.Join(t.TempDir(), ".ge_ignore")
+	if err := os.WriteFile(ignoreFile, []byte("vendor/\n*.log\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		forceInclude []string
+		wantFiles    []string
+	}{
+		{
+			name:      "Ignored without force-include",
+			wantFiles: []string{"main.go"},
+		},
+		{
+			name:         "Force-include beats the ignored directory",
+			forceInclude: []string{"vendor/acme/**"},
+			wantFiles:    []string{"main.go", "vendor/acme/lib.go", "vendor/acme/testdata/fixture.go"},
+		},
+		{
+			name:         "Force-include of a directory",
+			forceInclude: []string{"vendor/acme/"},
+			wantFiles:    []string{"main.go", "vendor/acme/lib.go", "vendor/acme/testdata/fixture.go"},
+		},
+		{
+			name:         "Force-include beats the ignored file pattern",
+			forceInclude: []string{"debug.log"},
+			wantFiles:    []string{"debug.log", "main.go"},
+		},
+		{
+			name:         "Negated force-include leaves the files ignored",
+			forceInclude: []string{"vendor/acme/**", "!vendor/acme/testdata/**"},
+			wantFiles:    []string{"main.go", "vendor/acme/lib.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: ignoreFile, MaxFileSize: 1000,
+				ForceInclude: tt.forceInclude})
+			if err != nil {
+				t.Fatalf("GetFiles() err = %v", err)
+			}
+			var gotFiles []string
+			for _, file := range fileContext.Files {
+				relPath, _ := filepath.Rel(searchDir, file.Path)
+				gotFiles = append(gotFiles, filepath.ToSlash(relPath))
+			}
+			sort.Strings(gotFiles)
+			if !reflect.DeepEqual(gotFiles, tt.wantFiles) {
+				t.Errorf("GetFiles() = %v, want %v", gotFiles, tt.wantFiles)
+			}
+		})
+	}
+}
+
+func TestGetFilesTarget(t *testing.T) {
+	searchDir := t.TempDir()
+	for name, content := range map[string]string{
+		"settings.js": `password = "SecretValue1673"`,
+		"main.go":     "package main",
+		".ge_ignore":  "settings.js\n",
+	} {
+		if err := os.WriteFile(path.Join(searchDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tests := []struct {
+		name        string
+		target      string
+		wantFiles   []string
+		wantSkipped []string
+	}{
+		{
+			name:        "Directory target is walked with its ignore patterns",
+			target:      searchDir,
+			wantFiles:   []string{".ge_ignore", "main.go"},
+			wantSkipped: []string{"settings.js"},
+		},
+		{
+			name:      "File target is scanned even when it's ignored",
+			target:    path.Join(searchDir, "settings.js"),
+			wantFiles: []string{"settings.js"},
+		},
+		{
+			name:      "File target is scanned alone",
+			target:    path.Join(searchDir, "main.go"),
+			wantFiles: []string{"main.go"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: tt.target, MaxFileSize: 1000})
+			if err != nil {
+				t.Fatalf("GetFiles() err = %v", err)
+			}
+			var gotFiles, gotSkipped []string
+			for _, file := range fileContext.Files {
+				gotFiles = append(gotFiles, file.Name)
+			}
+			for _, skipped := range fileContext.SkippedFiles {
+				gotSkipped = append(gotSkipped, path.Base(skipped))
+			}
+			sort.Strings(gotFiles)
+			if !reflect.DeepEqual(gotFiles, tt.wantFiles) {
+				t.Errorf("GetFiles() = %v, want %v", gotFiles, tt.wantFiles)
+			}
+			if !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("GetFiles() skipped %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestGetFilesModifiedSince(t *testing.T) {
+	searchDir := t.TempDir()
+	since := time.Now().Add(-time.Hour)
+	for name, modTime := range map[string]time.Time{
+		".gitignore":        since.Add(-time.Hour),
+		"old.go":            since.Add(-time.Hour),
+		"sub/old.py":        since.Add(-time.Hour),
+		"touched.go":        since.Add(time.Minute),
+		"sub/touched.py":    since.Add(time.Minute),
+		"touched.log":       since.Add(time.Minute),
+		"touched.md":        since.Add(time.Minute),
+		"exactly-since.txt": since,
+	} {
+		filePath := path.Join(searchDir, name)
+		if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		content := "content"
+		if name == ".gitignore" {
+			content = "*.log\n"
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filePath, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: path.Join(projectRoot, ".ge_ignore"),
+		MaxFileSize: 1000, ModifiedSince: since, ExcludeExtensions: []string{".md"}})
+	if err != nil {
+		t.Fatalf("GetFiles() err = %v", err)
+	}
+	var gotFiles []string
+	for _, file := range fileContext.Files {
+		gotFiles = append(gotFiles, strings.TrimPrefix(file.Path, searchDir+"/"))
+	}
+	sort.Strings(gotFiles)
+	if want := []string{"sub/touched.py", "touched.go"}; !reflect.DeepEqual(gotFiles, want) {
+		t.Errorf("GetFiles() = %v, want %v", gotFiles, want)
+	}
+	// The ignore and extension filters apply first, so the touched files they filter keep their reason
+	wantReasons := map[string]string{
+		".gitignore":        skipUnmodified,
+		"old.go":            skipUnmodified,
+		"sub/old.py":        skipUnmodified,
+		"exactly-since.txt": skipUnmodified,
+		"touched.log":       skipIgnored,
+		"touched.md":        skipExtension,
+	}
+	gotReasons := make(map[string]string)
+	for filePath, reason := range fileContext.SkipReasons {
+		gotReasons[strings.TrimPrefix(filePath, searchDir+"/")] = reason
+	}
+	if !reflect.DeepEqual(gotReasons, wantReasons) {
+		t.Errorf("GetFiles() skipped %v, want %v", gotReasons, wantReasons)
+	}
+}
+
 func Test_getIgnorePatterns(t *testing.T) {
 	if gotIgnorePatterns := getIgnorePatterns(projectRoot, ".ge_ignore", false); len(ignorePatterns) == 0 {
 		t.Errorf("getIgnorePatterns() = %v, want multiple patterns", gotIgnorePatterns)
@@ -172,6 +466,281 @@ func Test_isIgnoredFile(t *testing.T) {
 	}
 }
 
+func Test_isIgnoredFileParentDirectory(t *testing.T) {
+	defer setIgnorePatterns(ignorePatterns, ignoreCaseSensitive)
+	setIgnorePatterns([]string{"**/*.git/**", "**/node_modules/*", "**/*.pyc"}, false)
+
+	tests := []struct {
+		name     string
+		fileName string
+		want     bool
+	}{
+		{
+			name:     "File nested deep inside an ignored directory",
+			fileName: "/web/node_modules/express/lib/router/index.js",
+			want:     true,
+		},
+		{
+			name:     "Git internals",
+			fileName: "/.git/objects/ab/cdef0123",
+			want:     true,
+		},
+		{
+			name:     "Floating pattern at depth",
+			fileName: "/app/models/user.pyc",
+			want:     true,
+		},
+		{
+			name:     "Sibling of an ignored directory",
+			fileName: "/web/src/index.js",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIgnoredFile(tt.fileName, ""); got != tt.want {
+				t.Errorf("isIgnoredFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lastMatchingRule(t *testing.T) {
+	rules := compileIgnorePatterns([]string{"*.log", "!important.log", "debug/*.log", `\!keep`, `foo\*bar.txt`}, false)
+	tests := []struct {
+		name     string
+		fileName string
+		want     bool
+	}{
+		{
+			name:     "Ignored by the first pattern",
+			fileName: "/app.log",
+			want:     true,
+		},
+		{
+			name:     "Re-included by a later negation",
+			fileName: "/logs/important.log",
+			want:     false,
+		},
+		{
+			name:     "Ignored again by a pattern after the negation",
+			fileName: "/debug/important.log",
+			want:     true,
+		},
+		{
+			name:     "Not matched by any pattern",
+			fileName: "/main.go",
+			want:     false,
+		},
+		{
+			name:     "Escaped exclamation mark is not a negation",
+			fileName: "/!keep",
+			want:     true,
+		},
+		{
+			name:     "Escaped star only matches the literal filename",
+			fileName: "/foo*bar.txt",
+			want:     true,
+		},
+		{
+			name:     "Escaped star is not a wildcard",
+			fileName: "/foobazbar.txt",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := lastMatchingRule(tt.fileName, false, rules)
+			if got := rule != nil && !rule.negated; got != tt.want {
+				t.Errorf("lastMatchingRule() ignores = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_lastMatchingRuleCase(t *testing.T) {
+	patterns := []string{"Thumbs.db", "*.JPG", "!keep.jpg"}
+	tests := []struct {
+		name          string
+		fileName      string
+		caseSensitive bool
+		want          bool
+	}{
+		{
+			name:          "Exact case matches when case-sensitive",
+			fileName:      "/images/Thumbs.db",
+			caseSensitive: true,
+			want:          true,
+		},
+		{
+			name:          "Different case is not matched when case-sensitive",
+			fileName:      "/images/thumbs.db",
+			caseSensitive: true,
+			want:          false,
+		},
+		{
+			name:     "Different case is matched by default",
+			fileName: "/images/thumbs.db",
+			want:     true,
+		},
+		{
+			name:     "Pattern is folded as well as the path",
+			fileName: "/images/photo.jpg",
+			want:     true,
+		},
+		{
+			name:     "Negation is folded by default",
+			fileName: "/images/KEEP.JPG",
+			want:     false,
+		},
+		{
+			name:          "Negation does not apply to a different case when case-sensitive",
+			fileName:      "/images/KEEP.JPG",
+			caseSensitive: true,
+			want:          true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := lastMatchingRule(tt.fileName, false, compileIgnorePatterns(patterns, tt.caseSensitive))
+			if got := rule != nil && !rule.negated; got != tt.want {
+				t.Errorf("lastMatchingRule() ignores = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isIgnoredFileDirectoryOnly(t *testing.T) {
+	defer setIgnorePatterns(ignorePatterns, ignoreCaseSensitive)
+	setIgnorePatterns([]string{"dist/", "coverage/", "build/"}, false)
+
+	tests := []struct {
+		name     string
+		fileName string
+		want     bool
+	}{
+		{
+			name:     "File inside a dist directory",
+			fileName: "/dist/bundle.js",
+			want:     true,
+		},
+		{
+			name:     "File inside a nested coverage directory",
+			fileName: "/packages/web/coverage/lcov-report/index.js",
+			want:     true,
+		},
+		{
+			name:     "File inside a build directory",
+			fileName: "/build/main.test",
+			want:     true,
+		},
+		{
+			name:     "File named like an ignored directory",
+			fileName: "/scripts/build",
+			want:     false,
+		},
+		{
+			name:     "Directory prefix only matches whole names",
+			fileName: "/distribution/app.js",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIgnoredFile(tt.fileName, ""); got != tt.want {
+				t.Errorf("isIgnoredFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isIgnoredFileAnchored(t *testing.T) {
+	defer setIgnorePatterns(ignorePatterns, ignoreCaseSensitive)
+
+	tests := []struct {
+		name     string
+		patterns []string
+		fileName string
+		rootPath string
+		want     bool
+	}{
+		{
+			name:     "Anchored pattern matches the root file",
+			patterns: []string{"/secret.txt"},
+			fileName: "/secret.txt",
+			want:     true,
+		},
+		{
+			name:     "Anchored pattern does not match a nested file",
+			patterns: []string{"/secret.txt"},
+			fileName: "/sub/secret.txt",
+			want:     false,
+		},
+		{
+			name:     "Floating pattern matches a nested file",
+			patterns: []string{"secret.txt"},
+			fileName: "/sub/secret.txt",
+			want:     true,
+		},
+		{
+			name:     "Anchored pattern is relative to the scan root",
+			patterns: []string{"/config.yml"},
+			fileName: "/home/jdoe/project/config.yml",
+			rootPath: "/home/jdoe/project",
+			want:     true,
+		},
+		{
+			name:     "Anchored pattern does not match below the scan root",
+			patterns: []string{"/config.yml"},
+			fileName: "/home/jdoe/project/sub/config.yml",
+			rootPath: "/home/jdoe/project",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setIgnorePatterns(tt.patterns, false)
+			if got := isIgnoredFile(tt.fileName, tt.rootPath); got != tt.want {
+				t.Errorf("isIgnoredFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isIgnoredFileNegation(t *testing.T) {
+	defer setIgnorePatterns(ignorePatterns, ignoreCaseSensitive)
+	setIgnorePatterns([]string{".env*", "!.env.example", "build", "!build/keep.txt"}, false)
+
+	tests := []struct {
+		name     string
+		fileName string
+		want     bool
+	}{
+		{
+			name:     "Ignored environment file",
+			fileName: "/config/.env",
+			want:     true,
+		},
+		{
+			name:     "Re-included example file",
+			fileName: "/config/.env.example",
+			want:     false,
+		},
+		{
+			name:     "A file can't be re-included when its directory is ignored",
+			fileName: "/build/keep.txt",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isIgnoredFile(tt.fileName, ""); got != tt.want {
+				t.Errorf("isIgnoredFile() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func Test_isDirectory(t *testing.T) {
 	type args struct {
 		path string
@@ -217,6 +786,44 @@ func TestGetWD(t *testing.T) {
 
 }
 
+func Test_readStreamFile(t *testing.T) {
+	tests := []struct {
+		name      string
+		r         io.Reader
+		wantLines []string
+		wantErr   bool
+	}{
+		{
+			name:      "Lines of the stream",
+			r:         strings.NewReader("first\nsecond\n"),
+			wantLines: []string{"first", "second"},
+		},
+		{
+			name:    "Error reading the stream is returned",
+			r:       iotest.ErrReader(errors.New("broken pipe")),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := readStreamFile(&cfgreader.EarlybirdConfig{}, tt.r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readStreamFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			var gotLines []string
+			for _, line := range files[0].Lines {
+				gotLines = append(gotLines, line.LineValue)
+			}
+			if len(files) != 1 || !reflect.DeepEqual(gotLines, tt.wantLines) {
+				t.Errorf("readStreamFile() = %v, want the lines %v", files, tt.wantLines)
+			}
+		})
+	}
+}
+
 func TestIsEmpty(t *testing.T) {
 	type args struct {
 		path string
@@ -414,9 +1021,231 @@ func Test_parseGitFiles(t *testing.T) {
 	if len(output) == 0 {
 		t.Errorf("parseGitFiles() output = %v, want multiple files", output)
 	}
-	_, skipFiles := parseGitFiles(output, true, int64(1000000), projectRoot)
+	var fileContext Context
+	parseGitFiles(output, true, int64(1000000), projectRoot, &fileContext)
 
-	if len(skipFiles) == 0 {
+	if skipFiles := fileContext.SkippedFiles; len(skipFiles) == 0 {
 		t.Errorf("parseGitFiles() skipFiles = %v, want multiple files", skipFiles)
 	}
 }
+
+// gitCommand runs the git command in the repository with a test identity
+func gitCommand(t *testing.T, repo string, args ...string) {
+	t.Helper()
+	cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=Earlybird", "-c", "user.email=earlybird@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git %v: %v\n%s", args, err, output)
+	}
+}
+
+func TestGetGitFilesRange(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	writeFiles := func(files map[string]string) {
+		for name, content := range files {
+			if err := os.MkdirAll(path.Dir(path.Join(repo, name)), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path.Join(repo, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	gitCommand(t, repo, "init", "-q")
+	writeFiles(map[string]string{
+		"modified.py":    "password = 'unchanged'\n",
+		"deleted.py":     "password = 'deleted'\n",
+		"old/renamed.py": "def handler():\n    return 'the content of a renamed file stays the same'\n",
+		"untouched.py":   "print('untouched')\n",
+	})
+	gitCommand(t, repo, "add", "-A")
+	gitCommand(t, repo, "commit", "-q", "-m", "Initial commit")
+
+	writeFiles(map[string]string{
+		"modified.py":  "password = 'changed'\n",
+		"sub/added.py": "password = 'added'\n",
+	})
+	if err := os.Remove(path.Join(repo, "deleted.py")); err != nil {
+		t.Fatal(err)
+	}
+	gitCommand(t, repo, "mv", "old/renamed.py", "renamed.py")
+	gitCommand(t, repo, "add", "-A")
+	gitCommand(t, repo, "commit", "-q", "-m", "Change files")
+
+	cfg := cfgreader.EarlybirdConfig{
+		SearchDir:   repo,
+		GitRange:    "HEAD~1..HEAD",
+		IgnoreFile:  path.Join(projectRoot, ".ge_ignore"),
+		MaxFileSize: int64(1000000),
+	}
+	fileContext, err := GetGitFiles(utils.Range, &cfg)
+	if err != nil {
+		t.Fatalf("GetGitFiles() err = %v", err)
+	}
+	var got []string
+	for _, f := range fileContext.Files {
+		got = append(got, strings.TrimPrefix(f.Path, repo+"/"))
+	}
+	want := []string{"modified.py", "renamed.py", "sub/added.py"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetGitFiles() = %v, want %v", got, want)
+	}
+
+	cfg.GitRange = "unknown-branch...HEAD"
+	if _, err := GetGitFiles(utils.Range, &cfg); err == nil || !strings.Contains(err.Error(), "unknown-branch") {
+		t.Errorf("GetGitFiles() err = %v, want the invalid range reported", err)
+	}
+}
+
+func TestGetFilesNestedGitignore(t *testing.T) {
+	searchDir := t.TempDir()
+	files := map[string]string{
+		".gitignore":           "*.txt\nbuild/\n",
+		"main.go":              "package main",
+		"notes.txt":            "ignored by the root .gitignore",
+		"build/output.go":      "ignored directory",
+		"testdata/.gitignore":  "!fixture.txt\n*.json\n",
+		"testdata/fixture.txt": "re-included by the nested .gitignore",
+		"testdata/other.txt":   "still ignored by the root .gitignore",
+		"testdata/data.json":   "ignored by the nested .gitignore",
+		"config/data.json":     "the nested .gitignore doesn't apply outside of testdata",
+	}
+	for name, content := range files {
+		filePath := path.Join(searchDir, name)
+		if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, MaxFileSize: int64(1000000)})
+	if err != nil {
+		t.Fatalf("GetFiles() err = %v", err)
+	}
+	var got []string
+	for _, file := range fileContext.Files {
+		if file.Name != ".gitignore" {
+			got = append(got, strings.TrimPrefix(file.Path, searchDir+"/"))
+		}
+	}
+	want := []string{"config/data.json", "main.go", "testdata/fixture.txt"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFiles() = %v, want %v", got, want)
+	}
+}
+
+func TestGetFilesRootGitignore(t *testing.T) {
+	searchDir := t.TempDir()
+	files := map[string]string{
+		".gitignore": "*.env\n",
+		"local.env":  "re-included by the ignore file",
+		"prod.env":   "ignored by the root .gitignore",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(path.Join(searchDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ignoreFile := path.Join(t.TempDir(), "ignore")
+	if err := os.WriteFile(ignoreFile, []byte("!local.env\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The root .gitignore is part of the root patterns, the ignore file negation comes after it
+	fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: ignoreFile, MaxFileSize: int64(1000000)})
+	if err != nil {
+		t.Fatalf("GetFiles() err = %v", err)
+	}
+	var got []string
+	for _, file := range fileContext.Files {
+		if strings.HasSuffix(file.Name, ".env") {
+			got = append(got, file.Name)
+		}
+	}
+	if want := []string{"local.env"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFiles() = %v, want %v", got, want)
+	}
+}
+
+func Test_mergeIgnoreSources(t *testing.T) {
+	dir := t.TempDir()
+	gitignore := path.Join(dir, ".gitignore")
+	geIgnore := path.Join(dir, ".ge_ignore")
+	if err := os.WriteFile(gitignore, []byte("# project ignores\n*.env\n!sample.json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(geIgnore, []byte("!local.env\n*.json\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	patterns := mergeIgnoreSources([]string{gitignore, geIgnore, path.Join(dir, "missing")})
+	want := []string{"*.env", "!sample.json", "!local.env", "*.json"}
+	if !reflect.DeepEqual(patterns, want) {
+		t.Fatalf("mergeIgnoreSources() = %v, want %v", patterns, want)
+	}
+	rules := compileIgnorePatterns(patterns, false)
+
+	tests := []struct {
+		name     string
+		fileName string
+		want     bool
+	}{
+		{
+			name:     ".ge_ignore negation overrides a .gitignore exclusion",
+			fileName: "/local.env",
+			want:     false,
+		},
+		{
+			name:     ".gitignore exclusion still applies to other files",
+			fileName: "/prod.env",
+			want:     true,
+		},
+		{
+			name:     ".ge_ignore exclusion overrides a .gitignore negation",
+			fileName: "/sample.json",
+			want:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := lastMatchingRule(tt.fileName, false, rules)
+			if got := rule != nil && !rule.negated; got != tt.want {
+				t.Errorf("lastMatchingRule() ignores = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFilesMergedIgnoreSources(t *testing.T) {
+	searchDir := t.TempDir()
+	files := map[string]string{
+		".gitignore": "*.env\n",
+		".ge_ignore": "!local.env\n",
+		"local.env":  "re-included by .ge_ignore",
+		"prod.env":   "ignored by .gitignore",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(path.Join(searchDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, MaxFileSize: int64(1000000)})
+	if err != nil {
+		t.Fatalf("GetFiles() err = %v", err)
+	}
+	var got []string
+	for _, file := range fileContext.Files {
+		if strings.HasSuffix(file.Name, ".env") {
+			got = append(got, file.Name)
+		}
+	}
+	if want := []string{"local.env"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("GetFiles() = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,161 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// historyBlob is a file content introduced by a commit
+type historyBlob struct {
+	name, hash string
+	commit     *scan.Commit
+}
+
+// GetHistoryFiles builds the list of the file contents introduced by each commit of the git history of the search
+// directory, from the oldest commit.  The history is limited to the git range and the last commits when they are set.
+// Each content is listed once, for the first commit which introduced it.
+func GetHistoryFiles(cfg *cfgreader.EarlybirdConfig) (fileContext Context, err error) {
+	setScanPatterns(cfg, cfg.SearchDir)
+
+	blobs, err := historyBlobs(cfg.SearchDir, cfg.GitRange, cfg.GitHistoryDepth)
+	if err != nil {
+		return fileContext, err
+	}
+	var hashes []string
+	for _, blob := range blobs {
+		hashes = append(hashes, blob.hash)
+	}
+	sizes, err := blobSizes(cfg.SearchDir, hashes)
+	if err != nil {
+		return fileContext, err
+	}
+	for _, blob := range blobs {
+		filePath := filepath.Join(cfg.SearchDir, blob.name)
+		if isIgnoredFile(filePath, cfg.SearchDir) {
+			fileContext.skip(filePath, skipIgnored)
+			if cfg.VerboseEnabled {
+				utils.InfoLog.Println("Ignoring", filePath, ". File blacklisted.")
+			}
+			continue
+		}
+		if skipFilteredExtension(cfg, filePath) {
+			fileContext.skip(filePath, skipExtension)
+			continue
+		}
+		if sizes[blob.hash] > cfg.MaxFileSize {
+			fileContext.skip(filePath, skipTooLarge)
+			logTooLarge(filePath+" of commit "+blob.commit.Hash, sizes[blob.hash], cfg.MaxFileSize)
+			continue
+		}
+		hash := blob.hash
+		fileContext.Files = append(fileContext.Files, scan.File{
+			Name: filepath.Base(blob.name),
+			Path: filePath,
+			Open: func() (io.ReadCloser, error) {
+				content, err := gitOutput(cfg.SearchDir, "cat-file", "blob", hash)
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(bytes.NewReader(content)), nil
+			},
+			Commit: blob.commit,
+		})
+	}
+	fileContext.IgnorePatterns = ignorePatterns
+	return fileContext, nil
+}
+
+// historyBlobs lists the blobs added or modified by each commit, from the oldest commit
+func historyBlobs(searchDir, revisionRange string, depth int) (blobs []historyBlob, err error) {
+	// Merge commits are skipped by --raw, their content comes from the commits they merge
+	args := []string{"log", "--reverse", "--raw", "--no-abbrev", "--no-renames", "--relative", "--diff-filter=AM",
+		"--format=%x00%H%x00%an%x00%aI"}
+	if depth > 0 {
+		args = append(args, "--max-count="+strconv.Itoa(depth))
+	}
+	if revisionRange != "" {
+		args = append(args, revisionRange)
+	}
+	output, err := gitOutput(searchDir, append(args, "--")...)
+	if err != nil {
+		return nil, err
+	}
+
+	var commit *scan.Commit
+	seen := make(map[string]bool)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\x00") {
+			fields := strings.Split(line, "\x00")
+			if len(fields) != 4 {
+				return nil, fmt.Errorf("unexpected git log commit line %q", line)
+			}
+			commit = &scan.Commit{Hash: fields[1], Author: fields[2], Date: fields[3]}
+			continue
+		}
+		// :<old mode> <new mode> <old blob> <new blob> <status>\t<path>
+		meta, name, found := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 5 || commit == nil {
+			continue
+		}
+		if unquoted, err := strconv.Unquote(name); err == nil {
+			name = unquoted
+		}
+		// Submodules and symlinks have no content to scan
+		if fields[1] == "160000" || fields[1] == "120000" || seen[fields[3]] {
+			continue
+		}
+		seen[fields[3]] = true
+		blobs = append(blobs, historyBlob{name: name, hash: fields[3], commit: commit})
+	}
+	return blobs, scanner.Err()
+}
+
+// blobSizes returns the size of the blobs by hash
+func blobSizes(searchDir string, hashes []string) (sizes map[string]int64, err error) {
+	sizes = make(map[string]int64, len(hashes))
+	if len(hashes) == 0 {
+		return sizes, nil
+	}
+	cmd := exec.Command("git", "-C", searchDir, "cat-file", "--batch-check=%(objectname) %(objectsize)")
+	cmd.Stdin = strings.NewReader(strings.Join(hashes, "\n") + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git cat-file: %w", err)
+	}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, size, _ := strings.Cut(line, " ")
+		if sizes[hash], err = strconv.ParseInt(size, 10, 64); err != nil {
+			return nil, fmt.Errorf("unexpected git cat-file line %q", line)
+		}
+	}
+	return sizes, nil
+}
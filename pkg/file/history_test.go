@@ -0,0 +1,148 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+func TestGetHistoryFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	gitCommand(t, repo, "init", "-q")
+	commit := func(message string, files map[string]string) string {
+		for name, content := range files {
+			if err := os.MkdirAll(path.Dir(path.Join(repo, name)), 0755); err != nil {
+				t.Fatal(err)
+			}
+			if err := os.WriteFile(path.Join(repo, name), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		gitCommand(t, repo, "add", "-A")
+		gitCommand(t, repo, "commit", "-q", "-m", message)
+		output, err := exec.Command("git", "-C", repo, "rev-parse", "HEAD").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	first := commit("Add the settings", map[string]string{
+		"settings.py": "password = 'HistorySecret1673'\n",
+		"README.md":   "# Settings\n",
+	})
+	second := commit("Enable debug", map[string]string{
+		"settings.py": "debug = True\npassword = 'HistorySecret1673'\n",
+	})
+	third := commit("Remove the password", map[string]string{
+		"settings.py":  "debug = True\n",
+		"sub/other.py": "print('other')\n",
+		// The same content as an earlier commit is only scanned once
+		"sub/copy.md": "# Settings\n",
+	})
+
+	type historyFile struct {
+		Path, Commit, Content string
+	}
+	tests := []struct {
+		name     string
+		gitRange string
+		depth    int
+		dir      string
+		want     []historyFile
+	}{
+		{
+			name: "Every commit of the history",
+			want: []historyFile{
+				{"README.md", first, "# Settings\n"},
+				{"settings.py", first, "password = 'HistorySecret1673'\n"},
+				{"settings.py", second, "debug = True\npassword = 'HistorySecret1673'\n"},
+				{"settings.py", third, "debug = True\n"},
+				{"sub/other.py", third, "print('other')\n"},
+			},
+		},
+		{
+			name:  "Last commits of the history",
+			depth: 2,
+			want: []historyFile{
+				{"settings.py", second, "debug = True\npassword = 'HistorySecret1673'\n"},
+				{"settings.py", third, "debug = True\n"},
+				{"sub/copy.md", third, "# Settings\n"},
+				{"sub/other.py", third, "print('other')\n"},
+			},
+		},
+		{
+			name:     "Commits of a range",
+			gitRange: first + ".." + second,
+			want: []historyFile{
+				{"settings.py", second, "debug = True\npassword = 'HistorySecret1673'\n"},
+			},
+		},
+		{
+			name: "History of a sub directory",
+			dir:  "sub",
+			want: []historyFile{
+				{"sub/copy.md", third, "# Settings\n"},
+				{"sub/other.py", third, "print('other')\n"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := cfgreader.EarlybirdConfig{
+				SearchDir:       path.Join(repo, tt.dir),
+				GitRange:        tt.gitRange,
+				GitHistoryDepth: tt.depth,
+				IgnoreFile:      path.Join(projectRoot, ".ge_ignore"),
+				MaxFileSize:     int64(1000000),
+			}
+			fileContext, err := GetHistoryFiles(&cfg)
+			if err != nil {
+				t.Fatalf("GetHistoryFiles() err = %v", err)
+			}
+			var got []historyFile
+			for _, f := range fileContext.Files {
+				reader, err := f.Open()
+				if err != nil {
+					t.Fatal(err)
+				}
+				content, err := io.ReadAll(reader)
+				reader.Close()
+				if err != nil {
+					t.Fatal(err)
+				}
+				if f.Commit.Author != "Earlybird" || f.Commit.Date == "" {
+					t.Errorf("GetHistoryFiles() commit = %+v, want the author and date", f.Commit)
+				}
+				got = append(got, historyFile{strings.TrimPrefix(f.Path, repo+"/"), f.Commit.Hash, string(content)})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetHistoryFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// GetListedFiles builds the list of files from the paths of cfg.FilesFrom, one per line, or of the standard input when
+// it's "-", e.g. the files changed computed by the CI.  Only the listed files are scanned, the directories aren't walked,
+// but the ignore patterns and the filters still apply.  The listed paths which don't exist are skipped with a warning.
+func GetListedFiles(cfg *cfgreader.EarlybirdConfig) (fileContext Context, err error) {
+	var list io.Reader = os.Stdin
+	if cfg.FilesFrom != stdinList {
+		listFile, err := os.Open(cfg.FilesFrom)
+		if err != nil {
+			return fileContext, fmt.Errorf("failed to open the file list: %w", err)
+		}
+		defer listFile.Close()
+		list = listFile
+	}
+	return listedFiles(cfg, list)
+}
+
+// listedFiles builds the list of files from the paths read from list
+func listedFiles(cfg *cfgreader.EarlybirdConfig, list io.Reader) (fileContext Context, err error) {
+	setScanPatterns(cfg, cfg.SearchDir)
+
+	var paths bytes.Buffer
+	scanner := bufio.NewScanner(list)
+	for scanner.Scan() {
+		listedPath := strings.TrimSpace(scanner.Text())
+		if listedPath == "" {
+			continue
+		}
+		if !Exists(listedPath) {
+			log.Println("Warning: skipping", listedPath, "of the file list, it doesn't exist")
+			fileContext.skip(listedPath, skipMissing)
+			continue
+		}
+		paths.WriteString(listedPath + "\n")
+	}
+	if err = scanner.Err(); err != nil {
+		return fileContext, fmt.Errorf("failed to read the file list: %w", err)
+	}
+
+	var fileList []scan.File
+	if paths.Len() > 0 {
+		fileList = parseGitFiles(paths.Bytes(), cfg.VerboseEnabled, cfg.MaxFileSize, cfg.SearchDir, &fileContext)
+	}
+	collectFiles(cfg, fileList, &fileContext)
+	return fileContext, nil
+}
@@ -0,0 +1,137 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// writeTree writes the files of the map, by path relative to the directory
+func writeTree(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		filePath := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func Test_listedFiles(t *testing.T) {
+	searchDir := t.TempDir()
+	writeTree(t, searchDir, map[string]string{
+		"main.go":             "package main",
+		"app.properties":      "password=secret",
+		"logo.png":            "content",
+		"debug.log":           "content",
+		"config/settings.yml": "password: secret",
+	})
+	ignoreFile := filepath.Join(t.TempDir(), ".ge_ignore")
+	writeTree(t, filepath.Dir(ignoreFile), map[string]string{".ge_ignore": "*.log\n"})
+
+	tests := []struct {
+		name        string
+		list        []string
+		wantFiles   []string
+		wantSkipped map[string]string
+	}{
+		{
+			name:      "Only the listed files are scanned",
+			list:      []string{"main.go", "config/settings.yml"},
+			wantFiles: []string{"main.go", "settings.yml"},
+		},
+		{
+			name:      "Blank lines and surrounding spaces are ignored",
+			list:      []string{"", "  main.go  ", "", "app.properties\r"},
+			wantFiles: []string{"app.properties", "main.go"},
+		},
+		{
+			name:        "Missing files are skipped",
+			list:        []string{"main.go", "deleted.go"},
+			wantFiles:   []string{"main.go"},
+			wantSkipped: map[string]string{"deleted.go": skipMissing},
+		},
+		{
+			name:        "Filters and ignore patterns still apply",
+			list:        []string{"main.go", "logo.png", "debug.log"},
+			wantFiles:   []string{"main.go"},
+			wantSkipped: map[string]string{"logo.png": skipExtension, "debug.log": skipIgnored},
+		},
+		{
+			name: "Empty list",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var list strings.Builder
+			for _, listed := range tt.list {
+				if strings.TrimSpace(listed) != "" {
+					listed = strings.Replace(listed, strings.TrimSpace(listed), filepath.Join(searchDir, strings.TrimSpace(listed)), 1)
+				}
+				list.WriteString(listed + "\n")
+			}
+			fileContext, err := listedFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: ignoreFile, MaxFileSize: 1000,
+				ExcludeExtensions: []string{".png"}}, strings.NewReader(list.String()))
+			if err != nil {
+				t.Fatalf("listedFiles() err = %v", err)
+			}
+			var gotFiles []string
+			for _, file := range fileContext.Files {
+				gotFiles = append(gotFiles, file.Name)
+			}
+			sort.Strings(gotFiles)
+			if !reflect.DeepEqual(gotFiles, tt.wantFiles) {
+				t.Errorf("listedFiles() = %v, want %v", gotFiles, tt.wantFiles)
+			}
+			gotSkipped := make(map[string]string)
+			for skipped, reason := range fileContext.SkipReasons {
+				gotSkipped[filepath.Base(skipped)] = reason
+			}
+			if len(gotSkipped) != len(tt.wantSkipped) || len(tt.wantSkipped) > 0 && !reflect.DeepEqual(gotSkipped, tt.wantSkipped) {
+				t.Errorf("listedFiles() skipped %v, want %v", gotSkipped, tt.wantSkipped)
+			}
+		})
+	}
+}
+
+func TestGetListedFiles(t *testing.T) {
+	searchDir := t.TempDir()
+	writeTree(t, searchDir, map[string]string{"main.go": "package main", "other.go": "package main"})
+	listFile := filepath.Join(t.TempDir(), "changed-files.txt")
+	writeTree(t, filepath.Dir(listFile), map[string]string{"changed-files.txt": filepath.Join(searchDir, "main.go") + "\n"})
+
+	fileContext, err := GetListedFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, MaxFileSize: 1000, FilesFrom: listFile})
+	if err != nil {
+		t.Fatalf("GetListedFiles() err = %v", err)
+	}
+	if len(fileContext.Files) != 1 || fileContext.Files[0].Name != "main.go" {
+		t.Errorf("GetListedFiles() = %v, want main.go", fileContext.Files)
+	}
+	if _, err := GetListedFiles(&cfgreader.EarlybirdConfig{SearchDir: searchDir, FilesFrom: filepath.Join(searchDir, "missing.txt")}); err == nil {
+		t.Error("GetListedFiles() of a missing file list err = nil, want an error")
+	}
+}
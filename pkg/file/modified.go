@@ -0,0 +1,53 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"os"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// skipUnmodifiedFile reports whether the file wasn't modified after the ModifiedSince time of the config, logging it.  The
+// files are all scanned without the time, and so are the files whose modification time can't be read.
+func skipUnmodifiedFile(cfg *cfgreader.EarlybirdConfig, path string, info os.FileInfo) bool {
+	if cfg.ModifiedSince.IsZero() || info == nil || info.ModTime().After(cfg.ModifiedSince) {
+		return false
+	}
+	if cfg.VerboseEnabled {
+		utils.InfoLog.Println("Ignoring", path, ". File not modified since", cfg.ModifiedSince.Format("2006-01-02 15:04:05"))
+	}
+	return true
+}
+
+// filterModified returns the files modified after the ModifiedSince time of the config, the files left out are skipped
+func filterModified(cfg *cfgreader.EarlybirdConfig, files []scan.File, fileContext *Context) (kept []scan.File) {
+	if cfg.ModifiedSince.IsZero() {
+		return files
+	}
+	for _, f := range files {
+		info, err := os.Stat(f.Path)
+		if err == nil && skipUnmodifiedFile(cfg, f.Path, info) {
+			fileContext.skip(f.Path, skipUnmodified)
+			continue
+		}
+		kept = append(kept, f)
+	}
+	return kept
+}
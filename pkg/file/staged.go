@@ -0,0 +1,130 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// GetStagedFiles builds the list of the files staged in the git repository of the search directory.  Their content is read
+// from the git index rather than the working tree, so the findings are the ones of the content about to be committed.
+func GetStagedFiles(cfg *cfgreader.EarlybirdConfig) (fileContext Context, err error) {
+	setScanPatterns(cfg, cfg.SearchDir)
+
+	// Deleted files have nothing left to commit
+	output, err := gitOutput(cfg.SearchDir, "diff", "--cached", "--name-only", "--relative", "--diff-filter=d", "-z")
+	if err != nil {
+		return fileContext, err
+	}
+	// The size of the staged files is checked before reading them
+	blobs, err := indexBlobs(cfg.SearchDir)
+	if err != nil {
+		return fileContext, err
+	}
+	var hashes []string
+	for _, hash := range blobs {
+		hashes = append(hashes, hash)
+	}
+	sizes, err := blobSizes(cfg.SearchDir, hashes)
+	if err != nil {
+		return fileContext, err
+	}
+	for _, name := range strings.Split(string(output), "\x00") {
+		if name == "" {
+			continue
+		}
+		filePath := filepath.Join(cfg.SearchDir, name)
+		if isIgnoredFile(filePath, cfg.SearchDir) {
+			fileContext.skip(filePath, skipIgnored)
+			if cfg.VerboseEnabled {
+				utils.InfoLog.Println("Ignoring", filePath, ". File blacklisted.")
+			}
+			continue
+		}
+		if skipFilteredExtension(cfg, filePath) {
+			fileContext.skip(filePath, skipExtension)
+			continue
+		}
+
+		hash, ok := blobs[name]
+		if !ok {
+			// Submodules and symlinks have no content to scan
+			continue
+		}
+		if sizes[hash] > cfg.MaxFileSize {
+			fileContext.skip(filePath, skipTooLarge)
+			logTooLarge(filePath, sizes[hash], cfg.MaxFileSize)
+			continue
+		}
+		if cfg.VerboseEnabled {
+			utils.InfoLog.Println("Reading staged file ", filePath)
+		}
+		fileContext.Files = append(fileContext.Files, scan.File{
+			Name: filepath.Base(name),
+			Path: filePath,
+			Open: func() (io.ReadCloser, error) {
+				content, err := gitOutput(cfg.SearchDir, "cat-file", "blob", hash)
+				if err != nil {
+					return nil, err
+				}
+				return io.NopCloser(bytes.NewReader(content)), nil
+			},
+		})
+	}
+	fileContext.IgnorePatterns = ignorePatterns
+	return fileContext, nil
+}
+
+// indexBlobs returns the blob hashes of the regular files in the git index, by path relative to the search directory
+func indexBlobs(searchDir string) (blobs map[string]string, err error) {
+	output, err := gitOutput(searchDir, "ls-files", "--stage", "-z")
+	if err != nil {
+		return nil, err
+	}
+	blobs = make(map[string]string)
+	for _, entry := range strings.Split(string(output), "\x00") {
+		// <mode> <blob> <stage>\t<path>
+		meta, name, found := strings.Cut(entry, "\t")
+		fields := strings.Fields(meta)
+		if !found || len(fields) != 3 || fields[0] == "160000" || fields[0] == "120000" {
+			continue
+		}
+		blobs[name] = fields[1]
+	}
+	return blobs, nil
+}
+
+// gitOutput runs the git command in the directory, the error includes what git reported
+func gitOutput(dir string, args ...string) ([]byte, error) {
+	output, err := exec.Command("git", append([]string{"-C", dir, "-c", "core.quotePath=false", "--no-pager"}, args...)...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return nil, fmt.Errorf("git %s: %s", strings.Join(args, " "), strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return output, nil
+}
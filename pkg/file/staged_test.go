@@ -0,0 +1,116 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+func TestGetStagedFiles(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	writeFile := func(name, content string) {
+		if err := os.MkdirAll(path.Dir(path.Join(repo, name)), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path.Join(repo, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	gitCommand(t, repo, "init", "-q")
+	writeFile("settings.py", "debug = True\n")
+	writeFile("deleted.py", "print('deleted')\n")
+	writeFile("untouched.py", "print('untouched')\n")
+	gitCommand(t, repo, "add", "-A")
+	gitCommand(t, repo, "commit", "-q", "-m", "Initial commit")
+
+	// The secret is staged, then removed from the working tree only
+	writeFile("settings.py", "debug = True\npassword = 'StagedSecret1673'\n")
+	writeFile("sub/added.py", "print('added')\n")
+	writeFile("large.csv", strings.Repeat("a", 1000001))
+	gitCommand(t, repo, "add", "settings.py", "sub/added.py", "large.csv")
+	gitCommand(t, repo, "rm", "-q", "deleted.py")
+	writeFile("settings.py", "debug = True\n")
+	writeFile("unstaged.py", "password = 'not staged'\n")
+
+	cfg := cfgreader.EarlybirdConfig{
+		SearchDir:   repo,
+		IgnoreFile:  path.Join(projectRoot, ".ge_ignore"),
+		MaxFileSize: int64(1000000),
+	}
+	fileContext, err := GetStagedFiles(&cfg)
+	if err != nil {
+		t.Fatalf("GetStagedFiles() err = %v", err)
+	}
+	got := make(map[string]string)
+	for _, f := range fileContext.Files {
+		if f.Open == nil {
+			t.Fatalf("GetStagedFiles() file %s can't be opened", f.Path)
+		}
+		reader, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(reader)
+		reader.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got[strings.TrimPrefix(f.Path, repo+"/")] = string(content)
+	}
+	want := map[string]string{
+		"settings.py":  "debug = True\npassword = 'StagedSecret1673'\n",
+		"sub/added.py": "print('added')\n",
+	}
+	if len(got) != len(want) {
+		t.Errorf("GetStagedFiles() = %v, want %v", got, want)
+	}
+	for name, content := range want {
+		if got[name] != content {
+			t.Errorf("GetStagedFiles() content of %s = %q, want %q", name, got[name], content)
+		}
+	}
+	// The staged files larger than the maximum file size are skipped
+	if skipped := []string{path.Join(repo, "large.csv")}; !reflect.DeepEqual(fileContext.SkippedFiles, skipped) {
+		t.Errorf("GetStagedFiles() skipped %v, want %v", fileContext.SkippedFiles, skipped)
+	}
+
+	// The staged content is read relative to the search directory
+	cfg.SearchDir = path.Join(repo, "sub")
+	fileContext, err = GetStagedFiles(&cfg)
+	if err != nil {
+		t.Fatalf("GetStagedFiles() err = %v", err)
+	}
+	if len(fileContext.Files) != 1 || fileContext.Files[0].Path != path.Join(repo, "sub", "added.py") {
+		t.Errorf("GetStagedFiles() = %v, want only sub/added.py", fileContext.Files)
+	}
+
+	cfg.SearchDir = t.TempDir()
+	if _, err := GetStagedFiles(&cfg); err == nil {
+		t.Error("GetStagedFiles() outside of a git repository err = nil, want an error")
+	}
+}
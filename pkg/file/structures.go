@@ -16,10 +16,81 @@
 
 package file
 
-import "github.com/americanexpress/earlybird/v4/pkg/scan"
+import (
+	"time"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+	"github.com/americanexpress/earlybird/v4/pkg/wildcard"
+)
 
 //Context is the file system context used for the scan process
 type Context struct {
 	Files                                                     []scan.File
 	CompressPaths, ConvertPaths, IgnorePatterns, SkippedFiles []string
+	//SkipReasons is the reason each of the skipped files was skipped, by path
+	SkipReasons map[string]string
+	//Start is the time the scan started, for the duration in the summary
+	Start time.Time
+	//Truncated is set when the scan stopped at the maximum number of findings
+	Truncated bool
+}
+
+//skip adds the file to the skipped files for the reason
+func (fileContext *Context) skip(path, reason string) {
+	fileContext.SkippedFiles = append(fileContext.SkippedFiles, path)
+	if fileContext.SkipReasons == nil {
+		fileContext.SkipReasons = make(map[string]string)
+	}
+	fileContext.SkipReasons[path] = reason
+}
+
+//Summary aggregates the counts of the scan of the files and the hits reported
+func (fileContext Context) Summary(hits []scan.Hit) scan.Summary {
+	var duration time.Duration
+	if !fileContext.Start.IsZero() {
+		duration = time.Since(fileContext.Start)
+	}
+	summary := scan.Summarize(hits, fileContext.Files, fileContext.SkipReasons, duration)
+	summary.Truncated = fileContext.Truncated
+	return summary
+}
+
+//Merge adds the files of another directory of the scan to the context, the files already in the context are kept once
+func (fileContext *Context) Merge(other Context) {
+	seen := make(map[string]bool)
+	for _, f := range fileContext.Files {
+		seen[f.Path] = true
+	}
+	for _, f := range other.Files {
+		if !seen[f.Path] {
+			seen[f.Path] = true
+			fileContext.Files = append(fileContext.Files, f)
+		}
+	}
+	for _, path := range other.SkippedFiles {
+		fileContext.skip(path, other.SkipReasons[path])
+	}
+	fileContext.CompressPaths = append(fileContext.CompressPaths, other.CompressPaths...)
+	fileContext.ConvertPaths = append(fileContext.ConvertPaths, other.ConvertPaths...)
+	for _, pattern := range other.IgnorePatterns {
+		if !utils.Contains(fileContext.IgnorePatterns, pattern) {
+			fileContext.IgnorePatterns = append(fileContext.IgnorePatterns, pattern)
+		}
+	}
+}
+
+//ignoreScope is a set of ignore rules which only applies to the paths under dir (relative to the scan root), e.g. from a nested .gitignore
+type ignoreScope struct {
+	dir   string
+	rules []ignoreRule
+}
+
+//ignoreRule is a compiled ignore pattern
+type ignoreRule struct {
+	matcher wildcard.Matcher
+	negated bool
+	dirOnly bool
+	//pattern is the pattern as written in its ignore file, and source the ignore file, when it's explained
+	pattern, source string
 }
@@ -0,0 +1,158 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// walker walks the file tree in lexical order like filepath.Walk, but resolves symbolic links.  Symlinked regular files
+// are walked once, and symlinked directories are only followed when followSymlinks is set.  The walk stops with the
+// error of the context once it's cancelled.
+type walker struct {
+	ctx            context.Context
+	followSymlinks bool
+	verbose        bool
+	// skipHidden skips the files and directories below the root with a name starting with a dot
+	skipHidden bool
+	// skipGitDir skips the .git directories below the root, before the ignore patterns are evaluated
+	skipGitDir bool
+	// maxDepth limits how many levels below the root are walked, 0 for no limit
+	maxDepth int
+	// visited holds the real path of every file and directory walked so far, so a symlink cycle is never walked twice
+	visited map[string]bool
+	walkFn  filepath.WalkFunc
+}
+
+// walkFiles calls walkFn for every file and directory under root, see walker
+func walkFiles(ctx context.Context, root string, cfg *cfgreader.EarlybirdConfig, walkFn filepath.WalkFunc) error {
+	info, err := os.Stat(root)
+	if err != nil {
+		return walkFn(root, nil, err)
+	}
+	realRoot, err := filepath.EvalSymlinks(root)
+	if err != nil {
+		return walkFn(root, nil, err)
+	}
+	w := &walker{
+		ctx:            ctx,
+		followSymlinks: cfg.FollowSymlinks,
+		verbose:        cfg.VerboseEnabled,
+		skipHidden:     cfg.SkipHidden,
+		skipGitDir:     !cfg.ScanGitDir,
+		maxDepth:       cfg.MaxDepth,
+		visited:        make(map[string]bool),
+		walkFn:         walkFn,
+	}
+	err = w.walk(root, realRoot, info, 0)
+	if err == filepath.SkipDir || err == filepath.SkipAll {
+		return nil
+	}
+	return err
+}
+
+// walk walks path, which resolves to realPath and is depth levels below the root
+func (w *walker) walk(path, realPath string, info fs.FileInfo, depth int) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+	if w.visited[realPath] {
+		if w.verbose {
+			utils.InfoLog.Println("Skipping", path, ". Already scanned as", realPath)
+		}
+		return nil
+	}
+	w.visited[realPath] = true
+	if !info.IsDir() {
+		return w.walkFn(path, info, nil)
+	}
+
+	if err := w.walkFn(path, info, nil); err != nil {
+		return err
+	}
+	if w.maxDepth > 0 && depth >= w.maxDepth {
+		// The content of the directory is too deep
+		return nil
+	}
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return w.walkFn(path, info, err)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	for _, entry := range entries {
+		if w.skipHidden && strings.HasPrefix(entry.Name(), ".") {
+			if w.verbose {
+				utils.InfoLog.Println("Skipping", filepath.Join(path, entry.Name()), ". Hidden files aren't scanned.")
+			}
+			continue
+		}
+		if w.skipGitDir && entry.IsDir() && entry.Name() == gitDir {
+			if w.verbose {
+				utils.InfoLog.Println("Skipping", filepath.Join(path, entry.Name()), ". Git directories aren't scanned.")
+			}
+			continue
+		}
+		entryPath, entryRealPath := filepath.Join(path, entry.Name()), filepath.Join(realPath, entry.Name())
+		entryInfo, err := entry.Info()
+		if err == nil && entryInfo.Mode()&fs.ModeSymlink != 0 {
+			entryInfo, entryRealPath, err = w.resolveSymlink(entryPath)
+			if entryInfo == nil && err == nil {
+				continue
+			}
+		}
+		if err != nil {
+			if err := w.walkFn(entryPath, entryInfo, err); err != nil && err != filepath.SkipDir {
+				return err
+			}
+			continue
+		}
+		if err := w.walk(entryPath, entryRealPath, entryInfo, depth+1); err != nil {
+			if err != filepath.SkipDir || !entryInfo.IsDir() {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// resolveSymlink returns the target of the symlink, or a nil FileInfo if the symlink should be skipped
+func (w *walker) resolveSymlink(path string) (fs.FileInfo, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		// A dangling symlink has nothing to scan
+		if w.verbose {
+			utils.InfoLog.Println("Skipping", path, ". Broken symlink.")
+		}
+		return nil, "", nil
+	}
+	if info.IsDir() && !w.followSymlinks {
+		if w.verbose {
+			utils.InfoLog.Println("Skipping", path, ". Symlinked directories aren't followed.")
+		}
+		return nil, "", nil
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	return info, realPath, err
+}
@@ -0,0 +1,297 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package file
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+func TestGetFilesSymlinks(t *testing.T) {
+	base := t.TempDir()
+	searchDir, outsideDir := path.Join(base, "repo"), path.Join(base, "outside")
+	for _, dir := range []string{path.Join(searchDir, "a"), outsideDir} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, file := range []string{path.Join(searchDir, "a", "secret.py"), path.Join(searchDir, "notes.py"), path.Join(outsideDir, "config.py"), path.Join(outsideDir, "target.py")} {
+		if err := os.WriteFile(file, []byte(`password = "SecretValue1673"`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for link, target := range map[string]string{
+		"a/loop":   "../",                               // A cycle back to the root of the scan
+		"b":        "a",                                 // A directory which is already scanned
+		"alias.py": "a/secret.py",                       // A file which is already scanned
+		"ext":      outsideDir,                          // A directory outside of the scan
+		"link.py":  path.Join(outsideDir, "target.py"),  // A file outside of the scan
+		"dangling": path.Join(outsideDir, "missing.py"), // A broken symlink
+	} {
+		if err := os.Symlink(target, path.Join(searchDir, link)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name           string
+		followSymlinks bool
+		want           []string
+	}{
+		{
+			name: "Skip symlinked directories",
+			want: []string{"/a/secret.py", "/link.py", "/notes.py"},
+		},
+		{
+			name:           "Follow symlinked directories once",
+			followSymlinks: true,
+			want:           []string{"/a/secret.py", "/ext/config.py", "/ext/target.py", "/notes.py"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done := make(chan Context)
+			go func() {
+				fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{
+					SearchDir:      searchDir,
+					IgnoreFile:     path.Join(projectRoot, ".ge_ignore"),
+					MaxFileSize:    int64(1000000),
+					FollowSymlinks: tt.followSymlinks,
+				})
+				if err != nil {
+					t.Errorf("GetFiles() err = %v", err)
+				}
+				done <- fileContext
+			}()
+
+			select {
+			case fileContext := <-done:
+				var got []string
+				for _, file := range fileContext.Files {
+					got = append(got, strings.TrimPrefix(file.Path, searchDir))
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("GetFiles() = %v, want %v", got, tt.want)
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatal("GetFiles() didn't terminate on a symlink cycle")
+			}
+		})
+	}
+}
+
+func TestGetFilesMaxDepth(t *testing.T) {
+	searchDir := t.TempDir()
+	for _, file := range []string{"root.py", "src/app.py", "src/lib/util.py", "src/lib/deps/dep.py"} {
+		filePath := path.Join(searchDir, file)
+		if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(`password = "SecretValue1673"`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		maxDepth int
+		want     []string
+	}{
+		{
+			name:     "Only the files in the root",
+			maxDepth: 1,
+			want:     []string{"/root.py"},
+		},
+		{
+			name:     "One level of subdirectories",
+			maxDepth: 2,
+			want:     []string{"/root.py", "/src/app.py"},
+		},
+		{
+			name: "Unlimited",
+			want: []string{"/root.py", "/src/app.py", "/src/lib/deps/dep.py", "/src/lib/util.py"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{
+				SearchDir:   searchDir,
+				IgnoreFile:  path.Join(projectRoot, ".ge_ignore"),
+				MaxFileSize: int64(1000000),
+				MaxDepth:    tt.maxDepth,
+			})
+			if err != nil {
+				t.Fatalf("GetFiles() err = %v", err)
+			}
+			var got []string
+			for _, file := range fileContext.Files {
+				got = append(got, strings.TrimPrefix(file.Path, searchDir))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFiles() = %v, want %v", got, tt.want)
+			}
+			if len(fileContext.SkippedFiles) != 0 {
+				t.Errorf("GetFiles() skipped %v, want the deeper files to be silently skipped", fileContext.SkippedFiles)
+			}
+		})
+	}
+}
+
+func TestGetFilesHidden(t *testing.T) {
+	searchDir := t.TempDir()
+	for _, file := range []string{".env", ".aws/credentials", ".git/config", "app.py"} {
+		filePath := path.Join(searchDir, file)
+		if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(`password = "SecretValue1673"`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		skipHidden bool
+		want       []string
+	}{
+		{
+			name: "Scan the hidden files but the git metadata",
+			want: []string{"/.aws/credentials", "/.env", "/app.py"},
+		},
+		{
+			name:       "Skip the hidden files",
+			skipHidden: true,
+			want:       []string{"/app.py"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{
+				SearchDir:   searchDir,
+				IgnoreFile:  path.Join(projectRoot, ".ge_ignore"),
+				MaxFileSize: int64(1000000),
+				SkipHidden:  tt.skipHidden,
+			})
+			if err != nil {
+				t.Fatalf("GetFiles() err = %v", err)
+			}
+			var got []string
+			for _, file := range fileContext.Files {
+				got = append(got, strings.TrimPrefix(file.Path, searchDir))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFilesGitDir(t *testing.T) {
+	searchDir := t.TempDir()
+	for _, file := range []string{".git/config", ".git/objects/pack/pack-1.pack", "lib/.git/HEAD", "app.py"} {
+		filePath := path.Join(searchDir, file)
+		if err := os.MkdirAll(path.Dir(filePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filePath, []byte(`password = "SecretValue1673"`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// A negated ignore pattern doesn't re-include the git metadata, the walk skips it before the patterns are evaluated
+	ignoreFile := path.Join(t.TempDir(), ".ge_ignore")
+	if err := os.WriteFile(ignoreFile, []byte("!**/.git/config\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		scanGitDir bool
+		want       []string
+	}{
+		{
+			name: "Skip the git directories",
+			want: []string{"/app.py"},
+		},
+		{
+			name:       "Scan the git directories",
+			scanGitDir: true,
+			want:       []string{"/.git/config", "/.git/objects/pack/pack-1.pack", "/app.py", "/lib/.git/HEAD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fileContext, err := GetFiles(&cfgreader.EarlybirdConfig{
+				SearchDir:   searchDir,
+				IgnoreFile:  ignoreFile,
+				MaxFileSize: int64(1000000),
+				ScanGitDir:  tt.scanGitDir,
+			})
+			if err != nil {
+				t.Fatalf("GetFiles() err = %v", err)
+			}
+			var got []string
+			for _, file := range fileContext.Files {
+				got = append(got, strings.TrimPrefix(file.Path, searchDir))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFiles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetFilesContextCanceled(t *testing.T) {
+	searchDir := t.TempDir()
+	for _, file := range []string{path.Join(searchDir, "a", "secret.py"), path.Join(searchDir, "b", "notes.py")} {
+		if err := os.MkdirAll(path.Dir(file), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(`password = "SecretValue1673"`), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cfg := &cfgreader.EarlybirdConfig{SearchDir: searchDir, IgnoreFile: path.Join(searchDir, ".ge_ignore")}
+	// Cancel the walk once it reaches the first directory
+	calls := 0
+	err := walkFiles(ctx, searchDir, cfg, func(filePath string, info os.FileInfo, err error) error {
+		calls++
+		if path.Base(filePath) == "a" {
+			cancel()
+		}
+		return err
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("walkFiles() error = %v, want %v", err, context.Canceled)
+	}
+	if calls != 2 {
+		t.Errorf("walkFiles() walked %d paths, want the root and the first directory only", calls)
+	}
+
+	if _, err := GetFilesContext(ctx, cfg); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetFilesContext() error = %v, want %v", err, context.Canceled)
+	}
+}
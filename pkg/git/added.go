@@ -0,0 +1,144 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+const (
+	newFilePrefix = "+++ "
+	hunkPrefix    = "@@ -"
+	devNull       = "/dev/null"
+)
+
+// ParseAddedLines parses a unified diff, e.g. the output of git diff, into the lines added to each file, numbered as in
+// the new version of the file.  The context and the removed lines are left out, as are the deleted files and the files
+// without added lines.  The paths of the files are joined to dir.
+func ParseAddedLines(r io.Reader, dir string) (fileList []scan.File, err error) {
+	// increase buffer size
+	scanner := bufio.NewScanner(r)
+	buf := make([]byte, 0, 64*1024)
+	scanner.Buffer(buf, 1024*1024*100)
+
+	var (
+		curFile          *scan.File
+		lineNum          int
+		oldLeft, newLeft int
+	)
+	flush := func() {
+		if curFile != nil && len(curFile.Lines) > 0 {
+			fileList = append(fileList, *curFile)
+		}
+		curFile = nil
+	}
+	for scanner.Scan() {
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		// The lines of a hunk are counted, so the removed and added lines looking like headers stay in the hunk
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				if curFile != nil {
+					curFile.Lines = append(curFile.Lines, scan.Line{
+						LineNum:   lineNum,
+						LineValue: text[1:],
+						FilePath:  curFile.Path,
+						FileName:  filepath.Base(curFile.Path),
+					})
+				}
+				lineNum++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				oldLeft--
+			case strings.HasPrefix(text, `\`):
+				// "\ No newline at end of file" isn't a line of either file
+			default:
+				// Context line, some tools strip the space of the empty ones
+				lineNum++
+				oldLeft--
+				newLeft--
+			}
+			continue
+		}
+		switch {
+		case strings.HasPrefix(text, diffSep):
+			flush()
+		case strings.HasPrefix(text, newFilePrefix):
+			flush()
+			// The deleted files have no new version
+			if filePath := diffFilePath(text[len(newFilePrefix):]); filePath != "" {
+				curFile = &scan.File{Name: "buffer", Path: filepath.Join(dir, filePath)}
+			}
+		case strings.HasPrefix(text, hunkPrefix):
+			var ok bool
+			if lineNum, oldLeft, newLeft, ok = parseHunkHeader(text); !ok {
+				return nil, fmt.Errorf("Not valid hunk header: %s", text)
+			}
+		}
+	}
+	flush()
+	return fileList, scanner.Err()
+}
+
+// diffFilePath returns the path of the +++ header of a file, without its b/ prefix, or "" for /dev/null
+func diffFilePath(header string) string {
+	// diff -u follows the path with the modification time
+	filePath, _, _ := strings.Cut(header, "\t")
+	// git quotes the paths with special characters
+	if unquoted, err := strconv.Unquote(filePath); err == nil {
+		filePath = unquoted
+	}
+	if filePath == devNull {
+		return ""
+	}
+	return strings.TrimPrefix(filePath, "b/")
+}
+
+// parseHunkHeader returns the first line of the new file and the number of lines of the old and new files of a hunk
+// header, e.g. @@ -12,3 +12,4 @@ func main() {
+func parseHunkHeader(header string) (newStart, oldCount, newCount int, ok bool) {
+	fields := strings.Fields(header)
+	if len(fields) < 3 || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, false
+	}
+	_, oldCount, oldOK := parseHunkRange(fields[1][1:])
+	newStart, newCount, newOK := parseHunkRange(fields[2][1:])
+	return newStart, oldCount, newCount, oldOK && newOK
+}
+
+// parseHunkRange parses the start,count range of a hunk header, the count is 1 when it's left out
+func parseHunkRange(hunkRange string) (start, count int, ok bool) {
+	startText, countText, found := strings.Cut(hunkRange, ",")
+	start, err := strconv.Atoi(startText)
+	if err != nil {
+		return 0, 0, false
+	}
+	if !found {
+		return start, 1, true
+	}
+	if count, err = strconv.Atoi(countText); err != nil {
+		return 0, 0, false
+	}
+	return start, count, true
+}
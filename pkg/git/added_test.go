@@ -0,0 +1,109 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package git
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+func TestParseAddedLines(t *testing.T) {
+	diff := `diff --git a/config/settings.py b/config/settings.py
+index 3c3108d..f53837c 100644
+--- a/config/settings.py
++++ b/config/settings.py
+@@ -1,4 +1,5 @@
+ import os
+-password = 'removed_secret'
++password = os.environ['PASSWORD']
+ api_key = 'unchanged_secret'
++token = 'added_secret'
+ debug = True
+@@ -10,2 +11,2 @@ def main():
+ 
+--- not a header
++++ not a header either
+diff --git a/deleted.py b/deleted.py
+deleted file mode 100644
+index 3c3108d..0000000
+--- a/deleted.py
++++ /dev/null
+@@ -1 +0,0 @@
+-secret = 'deleted_secret'
+diff --git a/context.py b/context.py
+index 3c3108d..f53837c 100644
+--- a/context.py
++++ b/context.py
+@@ -3,2 +3 @@
+ print('context')
+-print('removed')
+diff --git a/new.txt b/new.txt
+new file mode 100644
+index 0000000..f53837c
+--- /dev/null
++++ b/new.txt
+@@ -0,0 +1 @@
++added_secret
+\ No newline at end of file
+`
+	got, err := ParseAddedLines(strings.NewReader(diff), "/repo")
+	if err != nil {
+		t.Fatalf("ParseAddedLines() error = %v", err)
+	}
+	added := func(filePath string, lineNum int, value string) scan.Line {
+		return scan.Line{LineNum: lineNum, LineValue: value, FilePath: filePath, FileName: filePath[strings.LastIndex(filePath, "/")+1:]}
+	}
+	want := []scan.File{
+		{
+			Name: "buffer",
+			Path: "/repo/config/settings.py",
+			Lines: []scan.Line{
+				added("/repo/config/settings.py", 2, "password = os.environ['PASSWORD']"),
+				added("/repo/config/settings.py", 4, "token = 'added_secret'"),
+				added("/repo/config/settings.py", 12, "++ not a header either"),
+			},
+		},
+		{
+			Name:  "buffer",
+			Path:  "/repo/new.txt",
+			Lines: []scan.Line{added("/repo/new.txt", 1, "added_secret")},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAddedLines() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseAddedLinesErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		diff string
+	}{
+		{name: "Hunk header without new range", diff: "+++ b/app.py\n@@ -1,2 @@\n"},
+		{name: "Hunk header with a bad count", diff: "+++ b/app.py\n@@ -1,2 +1,x @@\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseAddedLines(strings.NewReader(tt.diff), ""); err == nil {
+				t.Error("ParseAddedLines() error = nil, want an error")
+			}
+		})
+	}
+}
@@ -43,8 +43,7 @@ func ReposPerProject(projectURL, username, password string) (scanRepos []string)
 		opt := &github.RepositoryListByOrgOptions{Type: "public"}
 		repos, _, err := client.Repositories.ListByOrg(context.Background(), utils.GetGitProject(projectURL), opt)
 		if err != nil {
-			log.Println("Failed To Get Project Repositories:", err)
-			os.Exit(1)
+			utils.Fatal("Failed To Get Project Repositories: ", err)
 		}
 		for _, repo := range repos {
 			scanRepos = append(scanRepos, *repo.HTMLURL)
@@ -60,8 +59,7 @@ func ReposPerProject(projectURL, username, password string) (scanRepos []string)
 
 		reposResponse, err := client.getRepositories(project, requestParams)
 		if err != nil {
-			log.Println("Failed To Get Project Repositories:", err)
-			os.Exit(1)
+			utils.Fatal("Failed To Get Project Repositories: ", err)
 		}
 
 		for _, repo := range reposResponse.Values {
@@ -76,8 +74,12 @@ func ReposPerProject(projectURL, username, password string) (scanRepos []string)
 	return scanRepos
 }
 
-//CloneGitRepos Clones a Git repo into a random temporary folder
-func CloneGitRepos(repoURLs []string, username, password string, branch string, json bool) (tmpDir string, err error) {
+//DefaultCloneDepth clones the last commit of the repositories only, the scan reads the files of the working tree
+const DefaultCloneDepth = 1
+
+//CloneGitRepos Clones a Git repo into a random temporary folder, with the last depth commits of its history or all of
+//them when depth is 0.  The folder is removed when a repository fails to clone.
+func CloneGitRepos(repoURLs []string, username, password string, branch string, depth int, json bool) (tmpDir string, err error) {
 	tmpDir, err = os.MkdirTemp("", "ebgit")
 	if err != nil {
 		return "", err
@@ -90,7 +92,7 @@ func CloneGitRepos(repoURLs []string, username, password string, branch string,
 	for _, repo := range repoURLs {
 		options := git.CloneOptions{
 			URL:   repo,
-			Depth: 1,
+			Depth: depth,
 		}
 
 		if username != "" {
@@ -115,10 +117,13 @@ func CloneGitRepos(repoURLs []string, username, password string, branch string,
 		}
 
 		//Clone repo into random temporary path
-		log.Println("Cloned into:", scanDir)
+		utils.InfoLog.Println("Cloned into:", scanDir)
 		_, err = git.PlainClone(scanDir, false, &options)
 		if err != nil {
-			return tmpDir, err
+			if removeErr := os.RemoveAll(tmpDir); removeErr != nil {
+				log.Println("Failed to delete the cloned repositories:", removeErr)
+			}
+			return "", err
 		}
 	}
 	return tmpDir, err
@@ -18,7 +18,12 @@ package git
 
 import (
 	"os"
+	"os/exec"
+	"path/filepath"
 	"testing"
+
+	"gopkg.in/src-d/go-git.v4"
+	"gopkg.in/src-d/go-git.v4/plumbing/object"
 )
 
 var FakeRepo = "https://github.com/carnal0wnage/fake_commited_secrets"
@@ -38,7 +43,7 @@ func TestCloneGitRepos(t *testing.T) {
 		t.Skip("If test cases not running locally, skip cloning external repositories for CI/CD purposes.")
 	}
 
-	SearchDir, err := CloneGitRepos([]string{FakeRepo}, "", "", "", true)
+	SearchDir, err := CloneGitRepos([]string{FakeRepo}, "", "", "", DefaultCloneDepth, true)
 	if err != nil {
 		t.Errorf("Failed to clone repository: %s", FakeRepo)
 	}
@@ -49,3 +54,82 @@ func TestCloneGitRepos(t *testing.T) {
 		t.Errorf("Failed to delete git dir: %s", err)
 	}
 }
+
+func TestCloneGitReposLocal(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	// A local bare repository with two commits stands in for the remote
+	work, remote := t.TempDir(), filepath.Join(t.TempDir(), "app.git")
+	runGit := func(args ...string) {
+		t.Helper()
+		args = append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)
+		if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v %s", args, err, output)
+		}
+	}
+	runGit("-C", work, "init", "-q")
+	runGit("-C", work, "commit", "-q", "--allow-empty", "-m", "Initial commit")
+	if err := os.WriteFile(filepath.Join(work, "settings.py"), []byte(`password = "SecretValue1673"`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit("-C", work, "add", "settings.py")
+	runGit("-C", work, "commit", "-q", "-m", "Add the settings")
+	runGit("clone", "-q", "--bare", work, remote)
+
+	tests := []struct {
+		name        string
+		depth       int
+		wantCommits int
+	}{
+		{
+			name:        "Shallow clone",
+			depth:       DefaultCloneDepth,
+			wantCommits: 1,
+		},
+		{
+			name:        "Whole history",
+			wantCommits: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TMPDIR", t.TempDir())
+			tmpDir, err := CloneGitRepos([]string{"file://" + remote}, "", "", "", tt.depth, true)
+			if err != nil {
+				t.Fatalf("CloneGitRepos() err = %v", err)
+			}
+			defer os.RemoveAll(tmpDir)
+			if _, err := os.Stat(filepath.Join(tmpDir, "settings.py")); err != nil {
+				t.Errorf("CloneGitRepos() didn't check out the files: %v", err)
+			}
+			repo, err := git.PlainOpen(tmpDir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			commits, err := repo.Log(&git.LogOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var count int
+			_ = commits.ForEach(func(*object.Commit) error {
+				count++
+				return nil
+			})
+			if count != tt.wantCommits {
+				t.Errorf("CloneGitRepos() cloned %d commits, want %d", count, tt.wantCommits)
+			}
+		})
+	}
+
+	t.Run("Failed clone", func(t *testing.T) {
+		tmp := t.TempDir()
+		t.Setenv("TMPDIR", tmp)
+		if tmpDir, err := CloneGitRepos([]string{"file://" + filepath.Join(tmp, "missing.git")}, "", "", "", DefaultCloneDepth, true); err == nil || tmpDir != "" {
+			t.Errorf("CloneGitRepos() = %q, %v, want an error", tmpDir, err)
+		}
+		if entries, _ := os.ReadDir(tmp); len(entries) != 0 {
+			t.Errorf("CloneGitRepos() left %d entries in the temporary directory, want the clone removed", len(entries))
+		}
+	})
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package git
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+//HookScript is the pre-commit hook installed by InstallHook, it scans the staged content and blocks the commit on findings
+const HookScript = `#!/usr/bin/env bash
+#
+# Earlybird pre-commit hook, installed with 'go-earlybird install-hook'.  Bypass it with 'git commit --no-verify'.
+#
+
+echo "Running EarlyBird pre-commit hook"
+go-earlybird --fail-severity=high --pre-commit --path "$(git rev-parse --show-toplevel)"
+
+# $? stores exit value of the last command
+if [ $? -ne 0 ]; then
+ echo "Secrets detection tests must pass before commit!"
+ exit 1
+fi
+`
+
+//InstallHook writes HookScript to the pre-commit hook of the git repository containing dir, and returns its path.  An
+//existing hook is only overwritten with force.
+func InstallHook(dir string, force bool) (hookPath string, err error) {
+	root, err := revParse(dir, "--show-toplevel")
+	if err != nil {
+		return "", err
+	}
+	// The hooks directory is read from git, as worktrees and core.hooksPath move it out of .git/hooks
+	hooksDir, err := revParse(root, "--git-path", "hooks")
+	if err != nil {
+		return "", err
+	}
+	if !filepath.IsAbs(hooksDir) {
+		hooksDir = filepath.Join(root, hooksDir)
+	}
+	hookPath = filepath.Join(hooksDir, "pre-commit")
+
+	if _, err := os.Lstat(hookPath); err == nil && !force {
+		return "", fmt.Errorf("%s already exists, use --force to overwrite it", hookPath)
+	}
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(hookPath, []byte(HookScript), 0755); err != nil {
+		return "", err
+	}
+	// WriteFile keeps the mode of an overwritten hook
+	return hookPath, os.Chmod(hookPath, 0755)
+}
+
+//revParse runs git rev-parse in dir, the error includes what git reported
+func revParse(dir string, args ...string) (string, error) {
+	output, err := exec.Command("git", append([]string{"-C", dir, "rev-parse"}, args...)...).Output()
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			return "", fmt.Errorf("%s isn't in a git repository: %s", dir, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
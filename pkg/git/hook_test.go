@@ -0,0 +1,97 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package git
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInstallHook(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	if output, err := exec.Command("git", "-C", repo, "init", "-q").CombinedOutput(); err != nil {
+		t.Fatalf("git init: %v %s", err, output)
+	}
+	subDir := filepath.Join(repo, "src", "app")
+	if err := os.MkdirAll(subDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// The hook goes to the repository root from any of its directories
+	hookPath, err := InstallHook(subDir, false)
+	if err != nil {
+		t.Fatalf("InstallHook() err = %v", err)
+	}
+	wantPath, _ := filepath.EvalSymlinks(filepath.Join(repo, ".git", "hooks", "pre-commit"))
+	if gotPath, _ := filepath.EvalSymlinks(hookPath); gotPath != wantPath {
+		t.Errorf("InstallHook() path = %s, want %s", hookPath, wantPath)
+	}
+	assertHook := func() {
+		t.Helper()
+		content, err := os.ReadFile(hookPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(content) != HookScript || !strings.Contains(string(content), "--pre-commit") {
+			t.Errorf("InstallHook() hook = %s, want %s", content, HookScript)
+		}
+		info, err := os.Stat(hookPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0755 {
+			t.Errorf("InstallHook() hook mode = %v, want -rwxr-xr-x", mode)
+		}
+	}
+	assertHook()
+
+	// An existing hook is kept without force
+	custom := "#!/bin/sh\nexit 0\n"
+	if err := os.WriteFile(hookPath, []byte(custom), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InstallHook(repo, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("InstallHook() over an existing hook err = %v, want the --force error", err)
+	}
+	if content, _ := os.ReadFile(hookPath); string(content) != custom {
+		t.Errorf("InstallHook() overwrote the existing hook with %s", content)
+	}
+
+	if _, err := InstallHook(repo, true); err != nil {
+		t.Fatalf("InstallHook() with force err = %v", err)
+	}
+	assertHook()
+}
+
+func TestInstallHookOutsideRepository(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	dir := t.TempDir()
+	if _, err := InstallHook(dir, false); err == nil {
+		t.Error("InstallHook() outside of a git repository err = nil, want an error")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".git")); !os.IsNotExist(err) {
+		t.Errorf("InstallHook() outside of a git repository created %s", filepath.Join(dir, ".git"))
+	}
+}
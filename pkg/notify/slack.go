@@ -0,0 +1,131 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// slackTimeout bounds the webhook request, so an unreachable Slack doesn't hold the scan up
+const slackTimeout = 10 * time.Second
+
+// reportPlaceholder stands in for the link to the report when none is configured
+const reportPlaceholder = "<link to the report>"
+
+// slackMessage is the payload of a Slack incoming webhook, see https://api.slack.com/messaging/webhooks
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Fields   []slackText `json:"fields,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Collect copies the hits to collected as they're read from the returned channel, so they can be counted once the
+// writer is done with them.  The suppressed hits aren't collected.
+func Collect(hits <-chan scan.Hit, collected *[]scan.Hit) chan scan.Hit {
+	out := make(chan scan.Hit)
+	go func() {
+		defer close(out)
+		for hit := range hits {
+			if !hit.Suppressed {
+				*collected = append(*collected, hit)
+			}
+			out <- hit
+		}
+	}()
+	return out
+}
+
+// Slack posts the summary of the scan to the Slack incoming webhook of the config, when the scan has at least
+// SlackThreshold findings
+func Slack(summary scan.Summary, config cfgReader.EarlybirdConfig) error {
+	total := 0
+	for _, count := range summary.Severities {
+		total += count
+	}
+	if total < config.SlackThreshold {
+		return nil
+	}
+
+	body, err := json.Marshal(slackSummary(summary, total, config))
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: slackTimeout}
+	resp, err := client.Post(config.SlackWebhook, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("posting to the Slack webhook: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("received non 200 status code: status=%d, response=%v", resp.StatusCode, string(b))
+	}
+	return nil
+}
+
+// slackSummary formats the finding counts by severity, from the most severe, as Slack message blocks
+func slackSummary(summary scan.Summary, total int, config cfgReader.EarlybirdConfig) slackMessage {
+	target := config.SearchDir
+	levels := make([]string, 0, len(config.LevelMap))
+	for level := range config.LevelMap {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return config.LevelMap[levels[i]] < config.LevelMap[levels[j]] })
+
+	text := fmt.Sprintf("Earlybird found %d findings in %s", total, target)
+	var fields []slackText
+	for _, level := range levels {
+		fields = append(fields, slackText{Type: "mrkdwn", Text: "*" + level + "*\n" + strconv.Itoa(summary.Severities[level])})
+	}
+	link := config.SlackLink
+	if link == "" {
+		link = reportPlaceholder
+	} else {
+		link = "<" + link + "|View the report>"
+	}
+
+	return slackMessage{
+		Text: text,
+		Blocks: []slackBlock{
+			{Type: "section", Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf(":rotating_light: *Earlybird* found *%d* findings in `%s`, %d files scanned", total, target, summary.FilesScanned)}},
+			{Type: "section", Fields: fields},
+			{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: link}}},
+		},
+	}
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package notify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+var levelMap = map[string]int{"critical": 1, "high": 2, "medium": 3, "low": 4, "info": 5}
+
+func TestSlack(t *testing.T) {
+	var got map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("Slack() request = %s %s, want a JSON POST", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &got); err != nil {
+			t.Errorf("Slack() payload %s isn't JSON: %v", body, err)
+		}
+	}))
+	defer server.Close()
+
+	summary := scan.Summary{FilesScanned: 12, Severities: map[string]int{"critical": 1, "high": 2}}
+	config := cfgReader.EarlybirdConfig{SlackWebhook: server.URL, SlackThreshold: 1, SearchDir: "/builds/app", LevelMap: levelMap}
+	if err := Slack(summary, config); err != nil {
+		t.Fatalf("Slack() err = %v", err)
+	}
+
+	field := func(text string) interface{} { return map[string]interface{}{"type": "mrkdwn", "text": text} }
+	want := map[string]interface{}{
+		"text": "Earlybird found 3 findings in /builds/app",
+		"blocks": []interface{}{
+			map[string]interface{}{"type": "section", "text": field(":rotating_light: *Earlybird* found *3* findings in `/builds/app`, 12 files scanned")},
+			map[string]interface{}{"type": "section", "fields": []interface{}{
+				field("*critical*\n1"), field("*high*\n2"), field("*medium*\n0"), field("*low*\n0"), field("*info*\n0"),
+			}},
+			map[string]interface{}{"type": "context", "elements": []interface{}{field("<link to the report>")}},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Slack() payload = %v, want %v", got, want)
+	}
+}
+
+func TestSlackThreshold(t *testing.T) {
+	posted := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		posted = true
+	}))
+	defer server.Close()
+
+	summary := scan.Summary{Severities: map[string]int{"low": 2}}
+	config := cfgReader.EarlybirdConfig{SlackWebhook: server.URL, SlackThreshold: 3, LevelMap: levelMap}
+	if err := Slack(summary, config); err != nil || posted {
+		t.Errorf("Slack() err = %v and posted = %v, want no notification below the threshold", err, posted)
+	}
+}
+
+func TestSlackErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	summary := scan.Summary{Severities: map[string]int{"high": 1}}
+	config := cfgReader.EarlybirdConfig{SlackWebhook: server.URL, LevelMap: levelMap}
+	if err := Slack(summary, config); err == nil {
+		t.Error("Slack() err = nil, want the status code error")
+	}
+
+	// The webhook can't be reached once the server is closed
+	server.Close()
+	if err := Slack(summary, config); err == nil {
+		t.Error("Slack() err = nil, want the network error")
+	}
+}
+
+func TestCollect(t *testing.T) {
+	hits := make(chan scan.Hit)
+	go func() {
+		defer close(hits)
+		hits <- scan.Hit{Code: 1001}
+		hits <- scan.Hit{Code: 3001, Suppressed: true}
+		hits <- scan.Hit{Code: 3002}
+	}()
+
+	var collected []scan.Hit
+	written := 0
+	for range Collect(hits, &collected) {
+		written++
+	}
+	if written != 3 || len(collected) != 2 || collected[0].Code != 1001 || collected[1].Code != 3002 {
+		t.Errorf("Collect() wrote %d hits and collected %v, want the 3 hits written and the 2 unsuppressed collected", written, collected)
+	}
+}
@@ -0,0 +1,48 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package postprocess
+
+import "strings"
+
+// awsKeyPrefixes are the prefixes of the AWS key IDs, by type of resource, e.g. AKIA for the access keys and ASIA for the
+// temporary access keys
+var awsKeyPrefixes = []string{"ABIA", "ACCA", "AGPA", "AIDA", "AIPA", "AKIA", "ANPA", "ANVA", "APKA", "AROA", "ASCA", "ASIA"}
+
+// ValidAWSKeyID checks if the value is a well formed AWS key ID: one of the known prefixes followed by 16 characters of the
+// base32 alphabet, A to Z and 2 to 7.  AWS doesn't publish a checksum of its key IDs, the characters encode the account,
+// so a key ID with one of 0, 1, 8 or 9 can't be a real one.
+func ValidAWSKeyID(keyID string) bool {
+	keyID = strings.Trim(keyID, "\"'\n ")
+	if len(keyID) != 20 || !hasAWSKeyPrefix(keyID) {
+		return false
+	}
+	for _, c := range keyID[4:] {
+		if !(c >= 'A' && c <= 'Z' || c >= '2' && c <= '7') {
+			return false
+		}
+	}
+	return true
+}
+
+func hasAWSKeyPrefix(keyID string) bool {
+	for _, prefix := range awsKeyPrefixes {
+		if strings.HasPrefix(keyID, prefix) {
+			return true
+		}
+	}
+	return false
+}
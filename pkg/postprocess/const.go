@@ -23,4 +23,14 @@ const (
 	pswdRegex       string = "(?:[:=])(.*)"
 	pswdMinLen      int    = 3
 	splitPswdRegex  string = "[:=]"
+
+	panMinLength     int  = 13
+	panMaxLength     int  = 19
+	panVisibleDigits int  = 4
+	panMaskCharacter byte = '*'
+
+	//Base64Charset are the characters of base64 tokens, including the URL safe alphabet
+	Base64Charset string = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/=-_"
+	//HexCharset are the characters of hex tokens
+	HexCharset string = "0123456789abcdefABCDEF"
 )
@@ -18,6 +18,7 @@ package postprocess
 
 import (
 	"math"
+	"strings"
 )
 
 //Shannon is an algorithm used to calculate the complexity of the string
@@ -43,3 +44,19 @@ func Shannon(s string) float64 {
 	}
 	return entropy
 }
+
+//HighEntropyToken returns the token with the highest Shannon entropy among the runs of charset characters of at least minLength in value
+func HighEntropyToken(value, charset string, minLength int) (token string, entropy float64) {
+	tokens := strings.FieldsFunc(value, func(r rune) bool {
+		return !strings.ContainsRune(charset, r)
+	})
+	for _, candidate := range tokens {
+		if len(candidate) < minLength {
+			continue
+		}
+		if candidateEntropy := Shannon(candidate); candidateEntropy > entropy {
+			token, entropy = candidate, candidateEntropy
+		}
+	}
+	return token, entropy
+}
@@ -18,7 +18,10 @@
 // H = - Σ P(x) * log P(x)
 package postprocess
 
-import "testing"
+import (
+	"math"
+	"testing"
+)
 
 func TestShannon(t *testing.T) {
 	type args struct {
@@ -56,3 +59,69 @@ func TestShannon(t *testing.T) {
 		})
 	}
 }
+
+func TestHighEntropyToken(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		charset     string
+		wantToken   string
+		wantEntropy float64
+	}{
+		{
+			name:        "Random base64 key",
+			value:       `aws_secret = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYzq8b3kTz1Q"`,
+			charset:     Base64Charset,
+			wantToken:   "wJalrXUtnFEMI/K7MDENG/bPxRfiCYzq8b3kTz1Q",
+			wantEntropy: 5.07,
+		},
+		{
+			name:        "Encoded URL has a lower entropy than a random key",
+			value:       `url: ZXhhbXBsZS5jb20vcGF0aC90by9yZXNvdXJjZQ==`,
+			charset:     Base64Charset,
+			wantToken:   "ZXhhbXBsZS5jb20vcGF0aC90by9yZXNvdXJjZQ==",
+			wantEntropy: 4.38,
+		},
+		{
+			name:        "Identifier has a low entropy",
+			value:       `call(this_is_a_long_function_name)`,
+			charset:     Base64Charset,
+			wantToken:   "this_is_a_long_function_name",
+			wantEntropy: 3.65,
+		},
+		{
+			name:        "SHA-1 hex digest",
+			value:       `token = '9f86d081884c7d659a2feaa0c55ad015a3bf4f1b'`,
+			charset:     HexCharset,
+			wantToken:   "9f86d081884c7d659a2feaa0c55ad015a3bf4f1b",
+			wantEntropy: 3.83,
+		},
+		{
+			name:        "Repeated hex digits have a low entropy",
+			value:       `id=00000000001111111111deadbeef`,
+			charset:     HexCharset,
+			wantToken:   "00000000001111111111deadbeef",
+			wantEntropy: 2.19,
+		},
+		{
+			name:    "Short tokens are skipped",
+			value:   `key: "K7MDENG/bPxRfiCY"`,
+			charset: Base64Charset,
+		},
+		{
+			name:        "Highest entropy token of the line",
+			value:       `abcabcabcabcabcabcabcabc wJalrXUtnFEMI/K7MDENG/bPxRfiCYzq8b3kTz1Q`,
+			charset:     Base64Charset,
+			wantToken:   "wJalrXUtnFEMI/K7MDENG/bPxRfiCYzq8b3kTz1Q",
+			wantEntropy: 5.07,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotToken, gotEntropy := HighEntropyToken(tt.value, tt.charset, 20)
+			if gotToken != tt.wantToken || math.Abs(gotEntropy-tt.wantEntropy) > 0.01 {
+				t.Errorf("HighEntropyToken() = %q, %.2f, want %q, %.2f", gotToken, gotEntropy, tt.wantToken, tt.wantEntropy)
+			}
+		})
+	}
+}
@@ -50,3 +50,26 @@ func isolateNumber(cc string) string {
 	processedCC := notNum.ReplaceAllString(cc, "")
 	return processedCC
 }
+
+//IsPAN checks a potential card number has 13 to 19 digits and passes a mod10 check
+func IsPAN(cc string) bool {
+	cc = isolateNumber(cc)
+	return len(cc) >= panMinLength && len(cc) <= panMaxLength && IsCard(cc)
+}
+
+//MaskPAN masks all the digits of a card number but the last four, keeping any other character
+func MaskPAN(cc string) string {
+	visible := panVisibleDigits
+	masked := []byte(cc)
+	for i := len(masked) - 1; i > -1; i-- {
+		if masked[i] < '0' || masked[i] > '9' {
+			continue
+		}
+		if visible > 0 {
+			visible--
+			continue
+		}
+		masked[i] = panMaskCharacter
+	}
+	return string(masked)
+}
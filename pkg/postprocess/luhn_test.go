@@ -93,3 +93,81 @@ func Test_isolateNumber(t *testing.T) {
 		})
 	}
 }
+
+func TestIsPAN(t *testing.T) {
+	tests := []struct {
+		name string
+		cc   string
+		want bool
+	}{
+		{
+			name: "Visa card number",
+			cc:   "4539148803436467",
+			want: true,
+		},
+		{
+			name: "Card number separated with spaces",
+			cc:   "6011 1234 5678 9019",
+			want: true,
+		},
+		{
+			name: "Card number separated with dashes",
+			cc:   "5425-2334-3010-9903",
+			want: true,
+		},
+		{
+			name: "Invalid checksum",
+			cc:   "4539148803436468",
+			want: false,
+		},
+		{
+			name: "Too short",
+			cc:   "370000000002",
+			want: false,
+		},
+		{
+			name: "Too long",
+			cc:   "37000000000000200000000000000000000000000011",
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsPAN(tt.cc); got != tt.want {
+				t.Errorf("IsPAN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaskPAN(t *testing.T) {
+	tests := []struct {
+		name string
+		cc   string
+		want string
+	}{
+		{
+			name: "Card number",
+			cc:   "4539148803436467",
+			want: "************6467",
+		},
+		{
+			name: "Separators are kept",
+			cc:   "3795 547382 10094",
+			want: "**** ****** *0094",
+		},
+		{
+			name: "Quotes are kept",
+			cc:   `"5425-2334-3010-9903"`,
+			want: `"****-****-****-9903"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskPAN(tt.cc); got != tt.want {
+				t.Errorf("MaskPAN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strings"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// allowlistHashPrefix marks an entry of the allowlist file which is the SHA-256 of the value instead of the value itself
+const allowlistHashPrefix = "sha256:"
+
+// LoadAllowlist reads the allowlist file of known safe values, e.g. the fake keys of the test fixtures.  Each line is
+// either a value or the SHA-256 of the value prefixed with sha256:, so the file doesn't have to contain the values
+// themselves.  Blank lines and the lines starting with # are skipped.  The entries are stored as the hashes of their
+// normalized value.
+func LoadAllowlist(path string) (map[string]bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	allowlist := make(map[string]bool)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		entry := strings.TrimSpace(scanner.Text())
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+		if strings.HasPrefix(entry, allowlistHashPrefix) {
+			allowlist[strings.ToLower(strings.TrimPrefix(entry, allowlistHashPrefix))] = true
+			continue
+		}
+		allowlist[allowlistHash(entry)] = true
+	}
+	return allowlist, scanner.Err()
+}
+
+// allowlistHash hashes the normalized value, the way the hashes of the allowlist file are written
+func allowlistHash(value string) string {
+	digest := sha256.Sum256([]byte(normalizeAllowlistValue(value)))
+	return hex.EncodeToString(digest[:])
+}
+
+// normalizeAllowlistValue drops the surrounding quotes and all the white space of the value, so a key wrapped on
+// several lines or indented differently still matches its allowlist entry
+func normalizeAllowlistValue(value string) string {
+	return strings.Join(strings.Fields(strings.Trim(strings.TrimSpace(value), "\"'`")), "")
+}
+
+// inAllowlist reports if the secret of the hit is one of the known safe values of the allowlist, whatever the rule
+// which found it.  The rules of assignments match the key with its value, e.g. password = "value", so the value after
+// the assignment is also compared to the allowlist.
+func inAllowlist(cfg *cfgReader.EarlybirdConfig, hit Hit) bool {
+	if len(cfg.Allowlist) == 0 {
+		return false
+	}
+	value := hit.unmaskedValue()
+	if cfg.Allowlist[allowlistHash(value)] {
+		return true
+	}
+	if i := strings.IndexAny(value, ":="); i >= 0 {
+		return cfg.Allowlist[allowlistHash(value[i+1:])]
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestLoadAllowlist(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		values  map[string]bool
+	}{
+		{
+			name:    "Literal values",
+			content: "FakeFixtureKey1234\n",
+			values:  map[string]bool{"FakeFixtureKey1234": true, "RealKey5678": false},
+		},
+		{
+			name:    "Values are normalized",
+			content: "  \"FakeFixture Key1234\"  \n",
+			values:  map[string]bool{"FakeFixtureKey1234": true, "'FakeFixtureKey1234'": true, "FakeFixture\n\tKey1234": true},
+		},
+		{
+			name:    "Hashed values",
+			content: "sha256:" + allowlistHash("FakeFixtureKey1234") + "\n",
+			values:  map[string]bool{"FakeFixtureKey1234": true, " FakeFixtureKey1234\r": true, "sha256:" + allowlistHash("FakeFixtureKey1234"): false},
+		},
+		{
+			name:    "Comments and blank lines are skipped",
+			content: "# fixtures of the payment tests\n\n#FakeFixtureKey1234\n",
+			values:  map[string]bool{"FakeFixtureKey1234": false, "#FakeFixtureKey1234": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			allowlistFile := path.Join(t.TempDir(), "allowlist.txt")
+			if err := os.WriteFile(allowlistFile, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			allowlist, err := LoadAllowlist(allowlistFile)
+			if err != nil {
+				t.Fatalf("LoadAllowlist() err = %v", err)
+			}
+			for value, want := range tt.values {
+				if got := allowlist[allowlistHash(value)]; got != want {
+					t.Errorf("LoadAllowlist() has %q = %v, want %v", value, got, want)
+				}
+			}
+		})
+	}
+
+	if _, err := LoadAllowlist(path.Join(t.TempDir(), "missing.txt")); err == nil {
+		t.Errorf("LoadAllowlist() of a missing file err = nil, want an error")
+	}
+}
+
+func TestSearchFilesAllowlist(t *testing.T) {
+	searchDir := t.TempDir()
+	fixtureFile := path.Join(searchDir, "fixture.py")
+	if err := os.WriteFile(fixtureFile, []byte(`password = "FakeFixtureKey1234"`+"\n"+`password = "RealLookingKey5678"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := []File{{Name: fixtureFile, Path: fixtureFile}}
+	tests := []struct {
+		name      string
+		allowlist string
+		wantLines []int
+	}{
+		{
+			name:      "Without an allowlist",
+			wantLines: []int{1, 2},
+		},
+		{
+			name:      "Listed fake key",
+			allowlist: "FakeFixtureKey1234\n",
+			wantLines: []int{2},
+		},
+		{
+			name:      "Listed match with different white space",
+			allowlist: "password=\"FakeFixtureKey1234\"\n",
+			wantLines: []int{2},
+		},
+		{
+			name:      "Listed hash of the fake key",
+			allowlist: "sha256:" + allowlistHash("FakeFixtureKey1234") + "\n",
+			wantLines: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanCfg := cfg
+			scanCfg.SearchDir, scanCfg.FailScan = searchDir, false
+			if tt.allowlist != "" {
+				allowlistFile := path.Join(t.TempDir(), "allowlist.txt")
+				if err := os.WriteFile(allowlistFile, []byte(tt.allowlist), 0644); err != nil {
+					t.Fatal(err)
+				}
+				var err error
+				if scanCfg.Allowlist, err = LoadAllowlist(allowlistFile); err != nil {
+					t.Fatal(err)
+				}
+			}
+			hits := make(chan Hit)
+			go SearchFiles(&scanCfg, files, nil, nil, hits)
+			var gotLines []int
+			for hit := range hits {
+				if hit.Code == 3001 {
+					gotLines = append(gotLines, hit.Line)
+				}
+			}
+			if !reflect.DeepEqual(gotLines, tt.wantLines) {
+				t.Errorf("SearchFiles() lines = %v, want %v", gotLines, tt.wantLines)
+			}
+			if !scanCfg.FailScan {
+				t.Errorf("SearchFiles() FailScan = false, want the real looking key to fail the scan")
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// Baseline lists the findings accepted when adopting Earlybird, so only new findings are reported and fail the scan
+type Baseline struct {
+	Version  string            `json:"version"`
+	Created  string            `json:"created"`
+	Findings []BaselineFinding `json:"findings"`
+}
+
+// BaselineFinding identifies a finding by its hash, the rest is informational and never contains the secret
+type BaselineFinding struct {
+	Hash     string `json:"hash"`
+	Code     int    `json:"code"`
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
+}
+
+// LoadBaseline reads the hashes of the findings in the baseline file
+func LoadBaseline(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var baseline Baseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, err
+	}
+	hashes := make(map[string]bool, len(baseline.Findings))
+	for _, finding := range baseline.Findings {
+		hashes[finding.Hash] = true
+	}
+	return hashes, nil
+}
+
+// WriteBaseline writes the hits to the baseline file, to be used with LoadBaseline by the following scans
+func WriteBaseline(path string, hits <-chan Hit, cfg *cfgReader.EarlybirdConfig) error {
+	baseline := Baseline{Version: cfg.Version, Created: time.Now().UTC().Format(time.RFC3339), Findings: []BaselineFinding{}}
+	for hit := range hits {
+		if hit.Suppressed {
+			continue
+		}
+		baseline.Findings = append(baseline.Findings, BaselineFinding{
+			Hash:     Fingerprint(hit, cfg.SearchDir),
+			Code:     hit.Code,
+			Filename: RelativeFileName(hit.Filename, cfg.SearchDir),
+			Line:     hit.Line,
+		})
+	}
+	data, err := json.MarshalIndent(baseline, "", "\t")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0666)
+}
+
+// inBaseline reports if the hit was accepted in the baseline
+func inBaseline(cfg *cfgReader.EarlybirdConfig, hit Hit) bool {
+	return len(cfg.Baseline) > 0 && cfg.Baseline[Fingerprint(hit, cfg.SearchDir)]
+}
@@ -0,0 +1,123 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestBaselineRoundTrip(t *testing.T) {
+	searchDir := t.TempDir()
+	legacyFile := path.Join(searchDir, "legacy.py")
+	if err := os.WriteFile(legacyFile, []byte(`password = "SecretValue1673"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := []File{{Name: legacyFile, Path: legacyFile}}
+	baselineCfg := cfg
+	baselineCfg.SearchDir = searchDir
+
+	// Write the baseline of the existing findings
+	hits := make(chan Hit)
+	go SearchFiles(&baselineCfg, files, nil, nil, hits)
+	baselineFile := path.Join(t.TempDir(), "baseline.json")
+	if err := WriteBaseline(baselineFile, hits, &baselineCfg); err != nil {
+		t.Fatalf("WriteBaseline() err = %v", err)
+	}
+	baselineContent, err := os.ReadFile(baselineFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(baselineContent), "SecretValue1673") {
+		t.Errorf("WriteBaseline() = %s, leaked the secret", baselineContent)
+	}
+	baseline, err := LoadBaseline(baselineFile)
+	if err != nil {
+		t.Fatalf("LoadBaseline() err = %v", err)
+	}
+	if len(baseline) == 0 {
+		t.Fatalf("LoadBaseline() is empty, want the existing findings")
+	}
+
+	// A new finding is added to the file
+	if err := os.WriteFile(legacyFile, []byte("# new line shifting the old finding\n"+`password = "SecretValue1673"`+"\n"+`password = "NewSecret2024"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		baseline  map[string]bool
+		wantLines []int
+		wantFail  bool
+	}{
+		{
+			name:      "Without a baseline",
+			wantLines: []int{2, 3},
+			wantFail:  true,
+		},
+		{
+			name:      "Only new findings",
+			baseline:  baseline,
+			wantLines: []int{3},
+			wantFail:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scanCfg := baselineCfg
+			scanCfg.Baseline, scanCfg.FailScan = tt.baseline, false
+			hits := make(chan Hit)
+			go SearchFiles(&scanCfg, files, nil, nil, hits)
+			var gotLines []int
+			for hit := range hits {
+				if hit.Code == 3001 {
+					gotLines = append(gotLines, hit.Line)
+				}
+			}
+			if !reflect.DeepEqual(gotLines, tt.wantLines) {
+				t.Errorf("SearchFiles() lines = %v, want %v", gotLines, tt.wantLines)
+			}
+			if scanCfg.FailScan != tt.wantFail {
+				t.Errorf("SearchFiles() FailScan = %v, want %v", scanCfg.FailScan, tt.wantFail)
+			}
+		})
+	}
+
+	// Once the baseline is written again, every finding is accepted and the scan passes
+	hits = make(chan Hit)
+	go SearchFiles(&baselineCfg, files, nil, nil, hits)
+	if err := WriteBaseline(baselineFile, hits, &baselineCfg); err != nil {
+		t.Fatal(err)
+	}
+	scanCfg := baselineCfg
+	if scanCfg.Baseline, err = LoadBaseline(baselineFile); err != nil {
+		t.Fatal(err)
+	}
+	scanCfg.FailScan = false
+	hits = make(chan Hit)
+	go SearchFiles(&scanCfg, files, nil, nil, hits)
+	for hit := range hits {
+		if hit.Code == 3001 {
+			t.Errorf("SearchFiles() reported %v:%v, which is in the baseline", hit.Filename, hit.Line)
+		}
+	}
+	if scanCfg.FailScan {
+		t.Errorf("SearchFiles() failed the scan with every finding in the baseline")
+	}
+}
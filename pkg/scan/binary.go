@@ -0,0 +1,71 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// isBinary reports whether the sample of a file content looks binary: it contains a null byte, or the ratio of its
+// non-printable characters is above the threshold.  Tabs, line breaks and form feeds are printable, as is any valid
+// UTF-8 character, so text in other scripts isn't mistaken for binary.
+func isBinary(sample []byte, threshold float64) bool {
+	if len(sample) == 0 {
+		return false
+	}
+	nonPrintable, total := 0, 0
+	for len(sample) > 0 {
+		r, size := utf8.DecodeRune(sample)
+		switch {
+		case r == 0:
+			return true
+		case r == utf8.RuneError && size == 1:
+			// A character cut at the end of the sample isn't invalid
+			if !utf8.FullRune(sample) {
+				sample = nil
+				continue
+			}
+			nonPrintable++
+		case r < 0x20 && r != '\t' && r != '\n' && r != '\r' && r != '\f' && r != '\v' && r != 0x1b, r == 0x7f:
+			nonPrintable++
+		}
+		total++
+		sample = sample[size:]
+	}
+	return total > 0 && float64(nonPrintable)/float64(total) > threshold
+}
+
+// sniffThreshold is the ratio of non-printable characters above which a file is binary, defaulting to binaryThreshold
+func sniffThreshold(cfg *cfgReader.EarlybirdConfig) float64 {
+	if cfg.BinaryThreshold <= 0 {
+		return binaryThreshold
+	}
+	return cfg.BinaryThreshold
+}
+
+// isBinaryScanned reports whether the extension of the file is allowlisted to scan its content even when it looks binary
+func isBinaryScanned(cfg *cfgReader.EarlybirdConfig, filename string) bool {
+	for _, ext := range cfg.BinaryScanExtensions {
+		if strings.HasSuffix(strings.ToLower(filename), strings.ToLower(ext)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,106 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+
+package scan
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+var (
+	// pngHeader is the signature and header chunk of a 1x1 PNG image
+	pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x06\x00\x00\x00\x1f\x15\xc4\x89")
+	// elfHeader is the start of the header of a 64-bit ELF executable
+	elfHeader = []byte("\x7fELF\x02\x01\x01\x00\x00\x00\x00\x00\x00\x00\x00\x00\x02\x00\x3e\x00\x01\x00\x00\x00")
+	// utf8Text is a script with non-ASCII characters
+	utf8Text = []byte("#!/bin/sh\n# Café déjà vu, пароль, パスワード\tend\r\nexport PASSWORD=\"SecretValue1673\"\n")
+)
+
+func Test_isBinary(t *testing.T) {
+	tests := []struct {
+		name      string
+		sample    []byte
+		threshold float64
+		want      bool
+	}{
+		{name: "PNG image", sample: pngHeader, threshold: binaryThreshold, want: true},
+		{name: "ELF executable", sample: elfHeader, threshold: binaryThreshold, want: true},
+		{name: "ELF executable with the highest threshold", sample: elfHeader, threshold: 1, want: true},
+		{name: "UTF-8 text", sample: utf8Text, threshold: binaryThreshold, want: false},
+		{name: "UTF-8 character cut at the end of the sample", sample: utf8Text[:16], threshold: 0.01, want: false},
+		{name: "Colored terminal output", sample: []byte("\x1b[31merror\x1b[0m: failed\n"), threshold: binaryThreshold, want: false},
+		{name: "Control characters above the threshold", sample: []byte("ab\x01\x02\x03\x04"), threshold: binaryThreshold, want: true},
+		{name: "Control characters below the threshold", sample: []byte("abcdefghij\x01"), threshold: binaryThreshold, want: false},
+		{name: "Latin-1 text below the threshold", sample: []byte("caf\xe9 cr\xe8me br\xfbl\xe9e\n"), threshold: binaryThreshold, want: false},
+		{name: "Empty file", sample: nil, threshold: binaryThreshold, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isBinary(tt.sample, tt.threshold); got != tt.want {
+				t.Errorf("isBinary() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilesBinary(t *testing.T) {
+	dir := t.TempDir()
+	secret := []byte("\npassword = \"SecretValue1673\"\n")
+	tests := []struct {
+		name       string
+		file       string
+		content    []byte
+		extensions []string
+		want       bool
+	}{
+		{name: "PNG image is skipped", file: "logo.img", content: append(pngHeader, secret...), want: false},
+		{name: "ELF executable is skipped", file: "server", content: append(elfHeader, secret...), want: false},
+		{name: "UTF-8 text is scanned", file: "install.sh", content: utf8Text, want: true},
+		{name: "Allowlisted binary extension is scanned", file: "server.BIN", content: append(elfHeader, secret...), extensions: []string{".bin"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filePath := path.Join(dir, tt.file)
+			if err := os.WriteFile(filePath, tt.content, 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg := cfg
+			cfg.BinaryScanExtensions = tt.extensions
+			hits := make(chan Hit)
+			files := []File{{Name: tt.file, Path: filePath}}
+			go SearchFiles(&cfg, files, nil, nil, hits)
+
+			var found bool
+			for hit := range hits {
+				found = found || hit.Code == 3001 || hit.Code == 3002
+			}
+			if found != tt.want {
+				t.Errorf("SearchFiles() found the password = %v, want %v", found, tt.want)
+			}
+			// The binary files are marked as skipped for the summary
+			wantSkipped := ""
+			if !tt.want {
+				wantSkipped = skipBinary
+			}
+			if files[0].Skipped != wantSkipped {
+				t.Errorf("SearchFiles() skipped %q, want %q", files[0].Skipped, wantSkipped)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uncommittedHash is the commit git blame reports for the lines which aren't committed yet
+const uncommittedHash = "0000000000000000000000000000000000000000"
+
+// blameCache holds the commit of each line of the files blamed, git blame runs once per file
+type blameCache map[string]map[int]*Commit
+
+// commit returns the commit which last changed the line of the file, nil when the file isn't tracked by git
+// or the line isn't committed
+func (cache blameCache) commit(filePath string, line int) *Commit {
+	lines, ok := cache[filePath]
+	if !ok {
+		lines = blameFile(filePath)
+		cache[filePath] = lines
+	}
+	return lines[line]
+}
+
+// blameFile runs git blame on the file, the blame is best effort so failures leave the file without commits
+func blameFile(filePath string) map[int]*Commit {
+	output, err := exec.Command("git", "-C", filepath.Dir(filePath), "blame", "--porcelain", "--", filepath.Base(filePath)).Output()
+	if err != nil {
+		return nil
+	}
+	return parseBlame(output)
+}
+
+// parseBlame reads the commit of each line from the git blame porcelain output, the author of a commit is only
+// listed on its first line
+func parseBlame(output []byte) map[int]*Commit {
+	lines := make(map[int]*Commit)
+	commits := make(map[string]*Commit)
+	var (
+		current    *Commit
+		authorTime int64
+	)
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		key, value, _ := strings.Cut(line, " ")
+		if current == nil && len(key) != len(uncommittedHash) {
+			continue
+		}
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-time":
+			authorTime, _ = strconv.ParseInt(value, 10, 64)
+		case "author-tz":
+			current.Date = blameDate(authorTime, value)
+		default:
+			// <hash> <original line> <final line> [<lines in group>]
+			fields := strings.Fields(line)
+			if len(key) != len(uncommittedHash) || len(fields) < 3 {
+				continue
+			}
+			if current = commits[key]; current == nil {
+				current = &Commit{Hash: key}
+				commits[key] = current
+			}
+			if finalLine, err := strconv.Atoi(fields[2]); err == nil && key != uncommittedHash {
+				lines[finalLine] = current
+			}
+		}
+	}
+	return lines
+}
+
+// blameDate formats the author time of git blame in its time zone, e.g. 1700000000 and +0100
+func blameDate(authorTime int64, zone string) string {
+	date := time.Unix(authorTime, 0).UTC()
+	if offset, err := strconv.Atoi(zone); err == nil && len(zone) == 5 {
+		seconds := (offset/100*60 + offset%100) * 60
+		date = date.In(time.FixedZone(zone, seconds))
+	}
+	return date.Format(time.RFC3339)
+}
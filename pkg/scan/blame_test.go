@@ -0,0 +1,97 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+)
+
+func Test_blameCache(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git isn't installed")
+	}
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=Jane Doe", "-c", "user.email=jane@example.com", "-c", "commit.gpgsign=false"}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE=2024-03-01T10:00:00+01:00")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, output)
+		}
+		return strings.TrimSpace(string(output))
+	}
+	filePath := path.Join(repo, "settings.py")
+	if err := os.WriteFile(filePath, []byte("debug = True\npassword = \"SecretValue1673\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("init", "-q")
+	git("add", "-A")
+	git("commit", "-q", "-m", "Add the settings")
+	hash := git("rev-parse", "HEAD")
+	if err := os.WriteFile(filePath, []byte("debug = True\npassword = \"SecretValue1673\"\ntoken = \"uncommitted\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	untracked := path.Join(t.TempDir(), "untracked.py")
+	if err := os.WriteFile(untracked, []byte("password = \"SecretValue1673\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		filePath string
+		line     int
+		want     *Commit
+	}{
+		{
+			name:     "Committed line",
+			filePath: filePath,
+			line:     2,
+			want:     &Commit{Hash: hash, Author: "Jane Doe", Date: "2024-03-01T10:00:00+01:00"},
+		},
+		{
+			name:     "Uncommitted line",
+			filePath: filePath,
+			line:     3,
+		},
+		{
+			name:     "File outside of a git repository",
+			filePath: untracked,
+			line:     1,
+		},
+	}
+	cache := make(blameCache)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := cache.commit(tt.filePath, tt.line)
+			if (got == nil) != (tt.want == nil) || got != nil && *got != *tt.want {
+				t.Errorf("commit() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+	// The blame of the file is cached
+	if err := os.Remove(filePath); err != nil {
+		t.Fatal(err)
+	}
+	if got := cache.commit(filePath, 1); got == nil || got.Hash != hash {
+		t.Errorf("commit() = %+v, want the cached commit %s", got, hash)
+	}
+}
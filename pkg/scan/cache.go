@@ -0,0 +1,212 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// cacheHeader is the first line of the cache file, the cached findings are only reused by the scans with the same key
+type cacheHeader struct {
+	Key string `json:"key"`
+}
+
+// cacheEntry records the findings of a file, which is scanned again once its size or modification time changes
+type cacheEntry struct {
+	Path    string `json:"path"`
+	ModTime int64  `json:"mod_time"`
+	Size    int64  `json:"size"`
+	Skipped string `json:"skipped,omitempty"`
+	Hits    []Hit  `json:"hits"`
+}
+
+// resultCache reuses the findings of the files unchanged since the previous scans.  Each file scanned is appended to
+// the cache file as soon as it's done, so an interrupted scan resumes where it stopped.  A nil cache caches nothing.
+type resultCache struct {
+	mu      sync.Mutex
+	path    string
+	file    *os.File
+	writer  *bufio.Writer
+	entries map[string]cacheEntry
+}
+
+// openCache loads the cache file of the config, the entries of another rule set or config being discarded like the
+// ones of a refreshed cache, and rewrites it with the valid entries.  It returns nil without a cache file.
+func openCache(cfg *cfgReader.EarlybirdConfig) (*resultCache, error) {
+	if cfg.CacheFile == "" {
+		return nil, nil
+	}
+	key, err := cacheKey(cfg)
+	if err != nil {
+		return nil, err
+	}
+	cache := &resultCache{path: cfg.CacheFile, entries: make(map[string]cacheEntry)}
+	if !cfg.RefreshCache {
+		cache.load(key)
+	}
+	if err := cache.rewrite(key); err != nil {
+		if cache.file != nil {
+			cache.file.Close()
+		}
+		return nil, err
+	}
+	return cache, nil
+}
+
+// cacheKey hashes the version, the rules and the settings which change the findings of a file
+func cacheKey(cfg *cfgReader.EarlybirdConfig) (string, error) {
+	settings, err := json.Marshal([]interface{}{
+		cfg.Version, CombinedRules, FalsePositiveRules, SolutionConfigs, Labels, cfg.LevelMap, cfg.SkipComments,
+		cfg.IgnoreFPRules, cfg.ShowSolutions, cfg.ContextLines, cfg.WorkLength, cfg.VerboseEnabled, cfg.Suppress,
+		cfg.BinaryThreshold, cfg.BinaryScanExtensions, cfg.ExtensionsToSkipScan, cfg.AnnotationsToSkipLine,
+		cfg.EntropyBase64Threshold, cfg.EntropyHexThreshold, cfg.StrictJKS, cfg.FileTimeout, cfg.JoinContinuations,
+		cfg.ConfidenceKeywords, cfg.MaxMatchLength, cfg.DropLongMatches,
+	})
+	if err != nil {
+		return "", err
+	}
+	digest := sha256.Sum256(settings)
+	return hex.EncodeToString(digest[:]), nil
+}
+
+// load reads the entries of the cache file, up to the first line it can't read, e.g. the last line of an interrupted scan
+func (cache *resultCache) load(key string) {
+	f, err := os.Open(cache.path)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+	decoder := json.NewDecoder(bufio.NewReader(f))
+	var header cacheHeader
+	if err := decoder.Decode(&header); err != nil || header.Key != key {
+		return
+	}
+	for {
+		var entry cacheEntry
+		if err := decoder.Decode(&entry); err != nil {
+			return
+		}
+		cache.entries[entry.Path] = entry
+	}
+}
+
+// rewrite writes the header and the entries of the files which still exist to the cache file, and leaves it open to
+// append the files scanned
+func (cache *resultCache) rewrite(key string) (err error) {
+	// The cache holds the unmasked secrets, only the user can read it
+	if cache.file, err = os.OpenFile(cache.path, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0600); err != nil {
+		return err
+	}
+	cache.writer = bufio.NewWriter(cache.file)
+	if err := cache.append(cacheHeader{Key: key}); err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(cache.entries))
+	for path := range cache.entries {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		if _, err := os.Stat(path); err != nil {
+			delete(cache.entries, path)
+			continue
+		}
+		if err := cache.append(cache.entries[path]); err != nil {
+			return err
+		}
+	}
+	return cache.writer.Flush()
+}
+
+// append writes a line of JSON to the cache file
+func (cache *resultCache) append(v interface{}) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = cache.writer.Write(append(line, '\n'))
+	return err
+}
+
+// isCacheFile tells if the file is the cache file itself, which isn't scanned as it contains the secrets
+func (cache *resultCache) isCacheFile(file File) bool {
+	if cache == nil {
+		return false
+	}
+	cachePath, err1 := filepath.Abs(cache.path)
+	filePath, err2 := filepath.Abs(file.Path)
+	return err1 == nil && err2 == nil && cachePath == filePath
+}
+
+// lookup returns the cached result of the file when its size and modification time haven't changed, along with the
+// entry identifying the current version of the file to record its result.  Only the files on disk are cached.
+func (cache *resultCache) lookup(cfg *cfgReader.EarlybirdConfig, file File) (entry cacheEntry, result fileResult, ok bool) {
+	if cache == nil || file.Open != nil || file.Commit != nil || len(file.Lines) > 0 || file.Path == "buffer" || file.Name == "buffer" {
+		return entry, result, false
+	}
+	info, err := os.Stat(file.Path)
+	if err != nil || !info.Mode().IsRegular() {
+		return entry, result, false
+	}
+	entry = cacheEntry{Path: file.Path, ModTime: info.ModTime().UnixNano(), Size: info.Size()}
+
+	cache.mu.Lock()
+	cached, found := cache.entries[file.Path]
+	cache.mu.Unlock()
+	if !found || cached.ModTime != entry.ModTime || cached.Size != entry.Size {
+		return entry, result, false
+	}
+	if cfg.VerboseEnabled {
+		utils.InfoLog.Println("Reusing the cached findings of", file.Path)
+	}
+	return entry, fileResult{hits: cached.Hits, skipped: cached.Skipped}, true
+}
+
+// record appends the result of the file to the cache file, the files which timed out or whose scan was cancelled are left
+// out as their findings are incomplete
+func (cache *resultCache) record(entry cacheEntry, result fileResult) error {
+	if cache == nil || entry.Path == "" || result.warning != nil || result.canceled {
+		return nil
+	}
+	entry.Skipped, entry.Hits = result.skipped, result.hits
+
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	cache.entries[entry.Path] = entry
+	if err := cache.append(entry); err != nil {
+		return err
+	}
+	return cache.writer.Flush()
+}
+
+// close closes the cache file
+func (cache *resultCache) close() error {
+	if cache == nil {
+		return nil
+	}
+	return cache.file.Close()
+}
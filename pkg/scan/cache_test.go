@@ -0,0 +1,113 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// cachedScan scans the files with the cache file and returns the match values found, sorted
+func cachedScan(t *testing.T, cacheFile string, refresh bool, paths ...string) []string {
+	t.Helper()
+	cfg := cfg
+	cfg.CacheFile = cacheFile
+	cfg.RefreshCache = refresh
+	var files []File
+	for _, filePath := range paths {
+		files = append(files, File{Name: path.Base(filePath), Path: filePath})
+	}
+	hits := make(chan Hit)
+	go SearchFiles(&cfg, files, nil, nil, hits)
+	var found []string
+	for hit := range hits {
+		if hit.Code == 3001 || hit.Code == 3002 {
+			found = append(found, hit.unmaskedValue())
+		}
+	}
+	sort.Strings(found)
+	return found
+}
+
+func TestSearchFilesCache(t *testing.T) {
+	dir := t.TempDir()
+	cacheFile := path.Join(dir, ".earlybird-cache")
+	unchanged, modified := path.Join(dir, "unchanged.py"), path.Join(dir, "modified.py")
+	writeFile := func(filePath, secret string, modTime time.Time) {
+		t.Helper()
+		if err := os.WriteFile(filePath, []byte("password = \""+secret+"\"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(filePath, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+	modTime := time.Now().Add(-time.Hour)
+	writeFile(unchanged, "FirstSecret1673", modTime)
+	writeFile(modified, "OtherSecret1673", modTime)
+
+	first := cachedScan(t, cacheFile, false, unchanged, modified, cacheFile)
+	if len(first) != 2 {
+		t.Fatalf("SearchFiles() found %v, want the 2 passwords", first)
+	}
+	if info, err := os.Stat(cacheFile); err != nil || info.Mode().Perm() != 0600 {
+		t.Fatalf("SearchFiles() cache file %v, err = %v, want a file only the user can read", info, err)
+	}
+
+	// The same size and modification time make the file unchanged for the cache, so its cached findings are reused
+	// rather than scanning the new secret, while the modified file is scanned again
+	writeFile(unchanged, "UnseenSecret167", modTime)
+	writeFile(modified, "ModifiedSecret1673", modTime.Add(time.Minute))
+	second := cachedScan(t, cacheFile, false, unchanged, modified, cacheFile)
+	if want := []string{`password = "FirstSecret1673"`, `password = "ModifiedSecret1673"`}; !reflect.DeepEqual(second, want) {
+		t.Errorf("SearchFiles() with the cache found %v, want %v", second, want)
+	}
+
+	// Refreshing the cache scans every file again
+	refreshed := cachedScan(t, cacheFile, true, unchanged, modified)
+	if want := []string{`password = "ModifiedSecret1673"`, `password = "UnseenSecret167"`}; !reflect.DeepEqual(refreshed, want) {
+		t.Errorf("SearchFiles() refreshing the cache found %v, want %v", refreshed, want)
+	}
+}
+
+func TestSearchFilesInterruptedCache(t *testing.T) {
+	dir := t.TempDir()
+	cacheFile := path.Join(dir, "cache.jsonl")
+	filePath := path.Join(dir, "settings.py")
+	if err := os.WriteFile(filePath, []byte("password = \"FirstSecret1673\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cachedScan(t, cacheFile, false, filePath)
+
+	// A scan killed while writing leaves a partial line at the end of the cache file
+	f, err := os.OpenFile(cacheFile, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(`{"path":"` + filePath + `","mod_ti`); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if found := cachedScan(t, cacheFile, false, filePath); !reflect.DeepEqual(found, []string{`password = "FirstSecret1673"`}) {
+		t.Errorf("SearchFiles() with an interrupted cache found %v, want the cached password", found)
+	}
+}
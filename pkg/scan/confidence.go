@@ -0,0 +1,93 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"strings"
+	"unicode"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// adjustConfidence raises the confidence of the hit by one level when its line contains one of the boost keywords
+// around the match, e.g. a high entropy string assigned to a variable named secret, and lowers it by one level when the
+// line contains one of the lower keywords, e.g. example.  The match itself is left out, the rules already weigh it.
+// The confidence stays within the levels of the config, the post-processors set the levels of the default scale, and
+// both adjustments cancel each other out.
+func (hit *Hit) adjustConfidence(cfg *cfgReader.EarlybirdConfig) {
+	confidence := hit.ConfidenceID
+	if keywords := cfg.ConfidenceKeywords; len(keywords.Boost) > 0 || len(keywords.Lower) > 0 {
+		around := strings.Replace(hit.LineValue, hit.MatchValue, " ", 1)
+		if containsKeyword(around, keywords.Boost) {
+			confidence--
+		}
+		if containsKeyword(around, keywords.Lower) {
+			confidence++
+		}
+	}
+	confidence = clampLevel(confidence, cfg.LevelMap)
+	if confidence != hit.ConfidenceID {
+		hit.ConfidenceID = confidence
+		hit.Confidence = getLevelNameFromID(confidence, cfg.LevelMap)
+	}
+}
+
+// containsKeyword reports if the value contains one of the keywords as a word of its own, whatever their case.  The
+// words of an identifier are split at its punctuation, camel case humps and digits, e.g. clientSecret and
+// client_secret contain secret while latest doesn't contain test and tokenizer doesn't contain token.
+func containsKeyword(value string, keywords []string) bool {
+	runes := []rune(value)
+	folded := make([]rune, len(runes))
+	for i, r := range runes {
+		folded[i] = unicode.ToLower(r)
+	}
+	for _, keyword := range keywords {
+		if keyword == "" {
+			continue
+		}
+		word := []rune(strings.ToLower(keyword))
+		for start := 0; start+len(word) <= len(folded); start++ {
+			end := start + len(word)
+			if string(folded[start:end]) == string(word) && wordBoundary(runes, start) && wordBoundary(runes, end) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// wordBoundary reports if a word of an identifier starts or ends at the index of the runes
+func wordBoundary(runes []rune, i int) bool {
+	if i == 0 || i == len(runes) {
+		return true
+	}
+	prev, next := runes[i-1], runes[i]
+	switch {
+	case !isWordRune(prev) || !isWordRune(next):
+		return true
+	case unicode.IsLower(prev) && unicode.IsUpper(next):
+		// A camel case hump, e.g. client|Secret
+		return true
+	default:
+		// Between a letter and a digit, e.g. password|2
+		return unicode.IsDigit(prev) != unicode.IsDigit(next)
+	}
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,191 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"context"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+var testConfidenceKeywords = cfgReader.ConfidenceKeywords{
+	Boost: []string{"password", "secret", "token", "apikey"},
+	Lower: []string{"example", "test", "sample"},
+}
+
+func TestAdjustConfidence(t *testing.T) {
+	tests := []struct {
+		name           string
+		keywords       cfgReader.ConfidenceKeywords
+		lineValue      string
+		matchValue     string
+		confidenceID   int
+		wantConfidence string
+	}{
+		{
+			name:           "Bare match",
+			keywords:       testConfidenceKeywords,
+			lineValue:      "Kx9vLq2Tz7Wm4Rp8",
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "medium",
+		},
+		{
+			name:           "Assigned to a secret",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `client_secret = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "high",
+		},
+		{
+			name:           "Keywords are case insensitive",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `const APIKEY = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "high",
+		},
+		{
+			name:           "Camel case identifier",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `clientSecret: "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "high",
+		},
+		{
+			name:           "Keyword inside of another word",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `latest_tokenizer = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "medium",
+		},
+		{
+			name:           "Keyword followed by a digit",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `contest2 = "Kx9vLq2Tz7Wm4Rp8" # test2`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "low",
+		},
+		{
+			name:           "Sample value",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `sample_value = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "low",
+		},
+		{
+			name:           "Both keywords cancel out",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `test_token = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "medium",
+		},
+		{
+			name:           "The match itself is left out",
+			keywords:       testConfidenceKeywords,
+			lineValue:      "value: SampleToken2024",
+			matchValue:     "SampleToken2024",
+			confidenceID:   3,
+			wantConfidence: "medium",
+		},
+		{
+			name:           "Stays at the highest level",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `secret = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   1,
+			wantConfidence: "critical",
+		},
+		{
+			name:           "Stays at the lowest level",
+			keywords:       testConfidenceKeywords,
+			lineValue:      `example = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   5,
+			wantConfidence: "info",
+		},
+		{
+			name:           "Without keywords",
+			lineValue:      `secret = "Kx9vLq2Tz7Wm4Rp8"`,
+			matchValue:     "Kx9vLq2Tz7Wm4Rp8",
+			confidenceID:   3,
+			wantConfidence: "medium",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keywordCfg := cfg
+			keywordCfg.ConfidenceKeywords = tt.keywords
+			hit := Hit{LineValue: tt.lineValue, MatchValue: tt.matchValue, ConfidenceID: tt.confidenceID, Confidence: getLevelNameFromID(tt.confidenceID, cfg.LevelMap)}
+			hit.adjustConfidence(&keywordCfg)
+			if hit.Confidence != tt.wantConfidence || hit.ConfidenceID != cfg.LevelMap[tt.wantConfidence] {
+				t.Errorf("adjustConfidence() = %v (%v), want %v", hit.Confidence, hit.ConfidenceID, tt.wantConfidence)
+			}
+		})
+	}
+}
+
+func TestScanLineConfidenceKeywords(t *testing.T) {
+	keywordCfg := cfg
+	keywordCfg.ConfidenceKeywords = testConfidenceKeywords
+	tests := []struct {
+		name           string
+		line           string
+		wantConfidence string
+	}{
+		{
+			name:           "Password of the database",
+			line:           `db.password = "Kx9vLq2Tz7"`,
+			wantConfidence: "high",
+		},
+		{
+			name:           "Password of the token store",
+			line:           `db.token.password = "Kx9vLq2Tz7"`,
+			wantConfidence: "critical",
+		},
+		{
+			name:           "Password of the sample",
+			line:           `sample.password = "Kx9vLq2Tz7"`,
+			wantConfidence: "medium",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, hits := scanLine(context.Background(), Line{LineValue: tt.line, LineNum: 1, FilePath: "settings.py", FileName: "settings.py"}, nil, &keywordCfg)
+			var found bool
+			for _, hit := range hits {
+				if hit.Code != 3001 {
+					continue
+				}
+				found = true
+				if hit.Confidence != tt.wantConfidence {
+					t.Errorf("scanLine() confidence = %v, want %v", hit.Confidence, tt.wantConfidence)
+				}
+			}
+			if !found {
+				t.Errorf("scanLine() didn't find the password of %q", tt.line)
+			}
+		})
+	}
+}
@@ -16,13 +16,32 @@
 
 package scan
 
+import "time"
+
 const (
-    ruleSuffix        string  = ".json"
-    entropyThreshold  float64 = 4.7
-    compressRegex     string  = "\\.(war|jar|zip|ear)$"
-    convertRegex      string  = "\\.(docx|odt|pdf|rtf)$"
-    tempRegex         string  = `(?:ebgit|ebzip|ebconv)\d+[/\\](.+$)`
-    maskCharacter     string  = "*"
-    overlapLength     int     = 25
-    infoLevelSeverity string  = "info"
+    ruleSuffix         string  = ".json"
+    entropyThreshold   float64 = 4.7
+    entropyTokenLength int     = 20
+    compressRegex      string  = "\\.(war|jar|zip|ear|tar|gz|tgz)$"
+    convertRegex       string  = "\\.(docx|odt|pdf|rtf)$"
+    tempRegex          string  = `(?:ebgit|ebzip|ebconv)\d+[/\\](.+$)`
+    maskCharacter      string  = "*"
+    maskPrefixLength   int     = 4
+    overlapLength      int     = 25
+    infoLevelSeverity  string  = "info"
+    fileTimeoutCode    int     = 9001
+    suppressToken      string  = "earlybird:disable"
+    ignoreRulesToken   string  = "earlybird:ignore-rules" // Disables the rules of the codes after it in the whole file
+    ignoreRulesLines   int     = 5 // Number of lines at the top of a file searched for the ignoreRulesToken
+    tokenDelimiters    string  = "\"'`=:,;()[]{}<>" // Split the lines into tokens, with the whitespace, for the rules which don't search the entire line
+    streamWindowLines  int     = 100
+    binarySampleLength int     = 8192 // Bytes at the start of a file sniffed to detect binary content
+    binaryThreshold    float64 = 0.3
+    skipBinary         string  = "binary" // Reason the binary files are skipped in the summary
+    skipCache          string  = "cache"  // Reason the cache file is skipped in the summary, it holds the unmasked secrets
+    skipCanceled       string  = "canceled" // Reason the files left when the scan is cancelled are skipped in the summary
+    skipUnreadable     string  = "unreadable" // Reason the files which couldn't be opened or read to the end are skipped in the summary
+    // The progress is reported every progressTerminalInterval on a terminal, every progressLogInterval otherwise
+    progressTerminalInterval time.Duration = time.Second
+    progressLogInterval      time.Duration = 10 * time.Second
 )
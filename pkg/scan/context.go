@@ -0,0 +1,67 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import "strings"
+
+// hitContext returns the lines of the file around the hit.  end is the last line of the hit, after the hit line when the
+// lines continuing it were joined to it.  The lines are unmasked, maskHit masks the secret on them with the hit.
+func hitContext(hit Hit, fileLines []Line, contextLines, end int) (context []ContextLine) {
+	first, last := hit.Line-contextLines, end+contextLines
+	for _, line := range fileLines {
+		if line.LineNum < first || line.LineNum > last {
+			continue
+		}
+		if line.LineNum > hit.Line && line.LineNum <= end {
+			context = append(context, ContextLine{Line: line.LineNum, Value: line.LineValue, Match: true})
+			continue
+		}
+		if line.LineNum != hit.Line {
+			context = append(context, ContextLine{Line: line.LineNum, Value: line.LineValue})
+			continue
+		}
+		value := line.LineValue
+		if hit.MatchValue == "" || !strings.Contains(value, hit.MatchValue) {
+			// The match value was changed by the post processing, e.g. a masked card number
+			value = hit.LineValue
+		}
+		context = append(context, ContextLine{Line: line.LineNum, Value: value, Match: true})
+	}
+	return context
+}
+
+// maskContext masks the secret on the hit line of the context with MaskSecret, the same way as the line of the hit.
+// The lines continuing the hit line are masked like the line of a hit whose secret is unknown.
+func maskContext(hit Hit) []ContextLine {
+	if len(hit.Context) == 0 {
+		return hit.Context
+	}
+	context := make([]ContextLine, 0, len(hit.Context))
+	for _, line := range hit.Context {
+		switch {
+		case line.Line == hit.Line && strings.Contains(line.Value, hit.secret):
+			line.Value = strings.ReplaceAll(line.Value, hit.secret, hit.MatchValue)
+		case line.Line == hit.Line:
+			// The secret on the line is unknown, the line of the hit is already masked
+			line.Value = hit.LineValue
+		case line.Match:
+			line.Value = MaskSecret(line.Value)
+		}
+		context = append(context, line)
+	}
+	return context
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"strings"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+func Test_hitContext(t *testing.T) {
+	var fileLines []Line
+	for i, value := range []string{"one", "two", `password = "SecretValue1673"`, "four", "five", "six"} {
+		fileLines = append(fileLines, Line{LineNum: i + 1, LineValue: value})
+	}
+	// The hit line is masked like the line of the hit
+	masked := MaskSecret(`password = "SecretValue1673"`)
+
+	tests := []struct {
+		name         string
+		hit          Hit
+		contextLines int
+		end          int
+		showSecrets  bool
+		want         []ContextLine
+	}{
+		{
+			name:         "Lines around the hit",
+			hit:          Hit{Line: 3, MatchValue: `password = "SecretValue1673"`},
+			contextLines: 1,
+			want:         []ContextLine{{Line: 2, Value: "two"}, {Line: 3, Value: masked, Match: true}, {Line: 4, Value: "four"}},
+		},
+		{
+			name:         "Lines around the hit with the secrets shown",
+			hit:          Hit{Line: 3, MatchValue: `password = "SecretValue1673"`},
+			contextLines: 1,
+			showSecrets:  true,
+			want:         []ContextLine{{Line: 2, Value: "two"}, {Line: 3, Value: `password = "SecretValue1673"`, Match: true}, {Line: 4, Value: "four"}},
+		},
+		{
+			name:         "Window is clamped at the start of the file",
+			hit:          Hit{Line: 1, MatchValue: "one"},
+			contextLines: 2,
+			want:         []ContextLine{{Line: 1, Value: "***", Match: true}, {Line: 2, Value: "two"}, {Line: 3, Value: `password = "SecretValue1673"`}},
+		},
+		{
+			name:         "Window is clamped at the end of the file",
+			hit:          Hit{Line: 6, MatchValue: "six"},
+			contextLines: 2,
+			want:         []ContextLine{{Line: 4, Value: "four"}, {Line: 5, Value: "five"}, {Line: 6, Value: "***", Match: true}},
+		},
+		{
+			name:         "Match value changed by the post processing",
+			hit:          Hit{Line: 5, MatchValue: "****", LineValue: "****"},
+			contextLines: 0,
+			want:         []ContextLine{{Line: 5, Value: "****", Match: true}},
+		},
+		{
+			name:         "Lines joined to the hit line",
+			hit:          Hit{Line: 4, MatchValue: "fourfive", LineValue: "fourfive"},
+			contextLines: 1,
+			end:          5,
+			want:         []ContextLine{{Line: 3, Value: `password = "SecretValue1673"`}, {Line: 4, Value: "fo******", Match: true}, {Line: 5, Value: "f***", Match: true}, {Line: 6, Value: "six"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			end := tt.end
+			if end == 0 {
+				end = tt.hit.Line
+			}
+			// The context is masked along with the hit
+			hit := tt.hit
+			hit.Context = hitContext(tt.hit, fileLines, tt.contextLines, end)
+			if got := maskHit(&cfgReader.EarlybirdConfig{ShowSecrets: tt.showSecrets}, hit).Context; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hitContext() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilesContextLines(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "settings.js")
+	content := `const config = {
+  password = "SecretValue1673"
+}
+`
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		suppress bool
+		want     []ContextLine
+	}{
+		{
+			name: "Context of the hit",
+			want: []ContextLine{
+				{Line: 1, Value: "const config = {"},
+				{Line: 2, Value: `  ` + MaskSecret(`password = "SecretValue1673"`), Match: true},
+				{Line: 3, Value: "}"},
+			},
+		},
+		{
+			name:     "No context when the secrets are suppressed",
+			suppress: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			contextCfg := cfg
+			contextCfg.ContextLines = 2
+			contextCfg.Suppress = tt.suppress
+			hits := make(chan Hit)
+			go SearchFiles(&contextCfg, []File{{Name: filePath, Path: filePath}}, nil, nil, hits)
+
+			var found bool
+			for hit := range hits {
+				if hit.Code != 3001 {
+					continue
+				}
+				found = true
+				if !reflect.DeepEqual(hit.Context, tt.want) {
+					t.Errorf("SearchFiles() context = %v, want %v", hit.Context, tt.want)
+				}
+				// The line of the hit is trimmed
+				if len(hit.Context) > 1 && strings.TrimSpace(hit.Context[1].Value) != hit.LineValue {
+					t.Errorf("SearchFiles() context line = %q, want the line of the hit %q", hit.Context[1].Value, hit.LineValue)
+				}
+			}
+			if !found {
+				t.Error("SearchFiles() didn't report the password")
+			}
+		})
+	}
+}
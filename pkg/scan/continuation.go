@@ -0,0 +1,88 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// yamlBlockPattern matches a YAML key starting a literal (|) or folded (>) block scalar, with its optional chomping and
+// indentation indicators and comment, e.g. "  password: |-", capturing the indentation and the key
+var yamlBlockPattern = regexp.MustCompile(`^(\s*)(\S.*:)\s*[|>][-+1-9]*\s*(?:#.*)?$`)
+
+// continuationJobs creates the work of the lines of a file once the lines continued on the next lines are joined into
+// logical lines.  Each job keeps the lines of the file up to the last line joined, for the labels and the suppressions.
+func continuationJobs(lines []Line, fileName string, workLength int) (work []WorkJob) {
+	joined, ends := joinContinuations(lines, isYAML(fileName))
+	for i, line := range joined {
+		work = appendJob(work, WorkJob{WorkLine: line, FileLines: lines[:ends[i]+1]}, workLength)
+	}
+	return work
+}
+
+// joinContinuations joins the lines ending with a backslash with the lines they continue on, like a shell does, and in
+// YAML the lines of a block scalar with its key, separated by spaces.  The joined lines keep the number of their first
+// line and ends holds the index of the last line joined into each of them.
+func joinContinuations(lines []Line, yaml bool) (joined []Line, ends []int) {
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if match := yamlBlockPattern.FindStringSubmatch(line.LineValue); yaml && match != nil {
+			// The block is made of the lines indented deeper than its key, and the blank lines between them
+			values := []string{match[1] + match[2]}
+			end := i
+			for j := i + 1; j < len(lines); j++ {
+				value := lines[j].LineValue
+				if strings.TrimSpace(value) == "" {
+					continue
+				}
+				if len(value)-len(strings.TrimLeft(value, " \t")) <= len(match[1]) {
+					break
+				}
+				values = append(values, strings.TrimSpace(value))
+				end = j
+			}
+			line.LineValue = strings.Join(values, " ")
+			joined, ends = append(joined, line), append(ends, end)
+			i = end
+			continue
+		}
+		// The backslash and the line break are removed, the next line is kept as is
+		for continued(line.LineValue) && i+1 < len(lines) {
+			i++
+			line.LineValue = strings.TrimSuffix(line.LineValue, `\`) + lines[i].LineValue
+		}
+		joined, ends = append(joined, line), append(ends, i)
+	}
+	return joined, ends
+}
+
+// continued reports if the line ends with a backslash continuing it on the next line, an escaped backslash doesn't
+func continued(value string) bool {
+	backslashes := len(value) - len(strings.TrimRight(value, `\`))
+	return backslashes%2 == 1
+}
+
+// isYAML reports if the file is a YAML file, its block scalars are joined
+func isYAML(fileName string) bool {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".yml", ".yaml":
+		return true
+	}
+	return false
+}
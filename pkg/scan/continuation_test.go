@@ -0,0 +1,141 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func Test_joinContinuations(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []string
+		yaml     bool
+		want     []Line
+		wantEnds []int
+	}{
+		{
+			name:     "Backslash continued shell export",
+			values:   []string{"#!/bin/sh", `export DB_PASSWORD=\`, `  "SecretValue1673"`, "echo done"},
+			want:     []Line{{LineNum: 1, LineValue: "#!/bin/sh"}, {LineNum: 2, LineValue: `export DB_PASSWORD=  "SecretValue1673"`}, {LineNum: 4, LineValue: "echo done"}},
+			wantEnds: []int{0, 2, 3},
+		},
+		{
+			name:     "Value split over several lines",
+			values:   []string{`token="Secret\`, `Value\`, `1673"`},
+			want:     []Line{{LineNum: 1, LineValue: `token="SecretValue1673"`}},
+			wantEnds: []int{2},
+		},
+		{
+			name:     "Escaped backslash doesn't continue the line",
+			values:   []string{`path=C:\\`, `password="SecretValue1673"`},
+			want:     []Line{{LineNum: 1, LineValue: `path=C:\\`}, {LineNum: 2, LineValue: `password="SecretValue1673"`}},
+			wantEnds: []int{0, 1},
+		},
+		{
+			name:     "Backslash on the last line",
+			values:   []string{`password=\`},
+			want:     []Line{{LineNum: 1, LineValue: `password=\`}},
+			wantEnds: []int{0},
+		},
+		{
+			name:     "YAML block scalars",
+			yaml:     true,
+			values:   []string{"db:", "  password: >- # rotated yearly", "    SecretValue", "", "    1673", "  user: app", "  key: |", "    line"},
+			want:     []Line{{LineNum: 1, LineValue: "db:"}, {LineNum: 2, LineValue: "  password: SecretValue 1673"}, {LineNum: 6, LineValue: "  user: app"}, {LineNum: 7, LineValue: "  key: line"}},
+			wantEnds: []int{0, 4, 5, 7},
+		},
+		{
+			name:     "Empty YAML block",
+			yaml:     true,
+			values:   []string{"password: |", "user: app"},
+			want:     []Line{{LineNum: 1, LineValue: "password:"}, {LineNum: 2, LineValue: "user: app"}},
+			wantEnds: []int{0, 1},
+		},
+		{
+			name:     "Block scalars are only joined in YAML files",
+			values:   []string{"password: |", "  SecretValue1673"},
+			want:     []Line{{LineNum: 1, LineValue: "password: |"}, {LineNum: 2, LineValue: "  SecretValue1673"}},
+			wantEnds: []int{0, 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lines []Line
+			for i, value := range tt.values {
+				lines = append(lines, Line{LineNum: i + 1, LineValue: value})
+			}
+			got, ends := joinContinuations(lines, tt.yaml)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("joinContinuations() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(ends, tt.wantEnds) {
+				t.Errorf("joinContinuations() ends = %v, want %v", ends, tt.wantEnds)
+			}
+		})
+	}
+}
+
+func TestSearchFilesContinuations(t *testing.T) {
+	filePath := path.Join(t.TempDir(), "deploy.sh")
+	content := "#!/bin/sh\nset -e\nexport DB_PASSWORD=\\\n  \"SecretValue1673\"\necho done\n"
+	if err := os.WriteFile(filePath, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		join      bool
+		wantLines []int
+	}{
+		{
+			name: "Continued lines are scanned one by one by default",
+		},
+		{
+			name:      "Joined lines are reported on their first line",
+			join:      true,
+			wantLines: []int{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			joinCfg := cfg
+			joinCfg.JoinContinuations = tt.join
+			joinCfg.ContextLines = 1
+			hits := make(chan Hit)
+			go SearchFiles(&joinCfg, []File{{Name: filePath, Path: filePath}}, nil, nil, hits)
+
+			var gotLines []int
+			for hit := range hits {
+				if hit.Code != 3001 {
+					continue
+				}
+				gotLines = append(gotLines, hit.Line)
+				// The secret is masked on the lines joined
+				if want := []ContextLine{{Line: 2, Value: "set -e"}, {Line: 3, Value: "export DB_P***************************", Match: true}, {Line: 4, Value: `  "S***************`, Match: true}, {Line: 5, Value: "echo done"}}; !reflect.DeepEqual(hit.Context, want) {
+					t.Errorf("SearchFiles() context = %+v, want %+v", hit.Context, want)
+				}
+			}
+			if !reflect.DeepEqual(gotLines, tt.wantLines) {
+				t.Errorf("SearchFiles() lines = %v, want %v", gotLines, tt.wantLines)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+// CollapseDuplicates merges the hits of the same rule matching the same value, e.g. a key copied around the repository,
+// into the first hit found, listing every occurrence in its Locations.  The hits are sent once the scan is done.
+func CollapseDuplicates(hits <-chan Hit) chan Hit {
+	collapsed := make(chan Hit)
+	go func() {
+		defer close(collapsed)
+		var unique []Hit
+		index := make(map[findingKey]int) //Finding:index in the unique hits
+		for hit := range hits {
+			key := findingKey{code: hit.Code, matchValue: hit.unmaskedValue(), suppressed: hit.Suppressed}
+			location := Location{Filename: hit.Filename, Line: hit.Line}
+			if i, ok := index[key]; ok {
+				unique[i].Locations = append(unique[i].Locations, location)
+				continue
+			}
+			index[key] = len(unique)
+			hit.Locations = []Location{location}
+			unique = append(unique, hit)
+		}
+		for _, hit := range unique {
+			collapsed <- hit
+		}
+	}()
+	return collapsed
+}
+
+// findingKey identifies the same secret found by the same rule
+type findingKey struct {
+	code       int
+	matchValue string
+	suppressed bool
+}
@@ -0,0 +1,65 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCollapseDuplicates(t *testing.T) {
+	key := `aws_secret_access_key = "wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY"`
+	hits := make(chan Hit)
+	go func() {
+		defer close(hits)
+		for _, hit := range []Hit{
+			{Code: 1002, Filename: "deploy/prod.env", Line: 3, MatchValue: key},
+			{Code: 3001, Filename: "settings.py", Line: 12, MatchValue: `password = "SecretValue1673"`},
+			{Code: 1002, Filename: "deploy/stage.env", Line: 5, MatchValue: key},
+			// A different secret found by the same rule
+			{Code: 3001, Filename: "settings.py", Line: 40, MatchValue: `password = "OtherValue9000"`},
+			// The same value found by a different rule
+			{Code: 3002, Filename: "settings.py", Line: 12, MatchValue: `password = "SecretValue1673"`},
+			{Code: 1002, Filename: "scripts/setup.sh", Line: 1, MatchValue: key},
+		} {
+			hits <- hit
+		}
+	}()
+
+	var got []Hit
+	for hit := range CollapseDuplicates(hits) {
+		got = append(got, hit)
+	}
+	if len(got) != 4 {
+		t.Fatalf("CollapseDuplicates() = %d hits, want 4", len(got))
+	}
+	wantLocations := [][]Location{
+		{{Filename: "deploy/prod.env", Line: 3}, {Filename: "deploy/stage.env", Line: 5}, {Filename: "scripts/setup.sh", Line: 1}},
+		{{Filename: "settings.py", Line: 12}},
+		{{Filename: "settings.py", Line: 40}},
+		{{Filename: "settings.py", Line: 12}},
+	}
+	for i, hit := range got {
+		if !reflect.DeepEqual(hit.Locations, wantLocations[i]) {
+			t.Errorf("CollapseDuplicates() hit %d locations = %v, want %v", i, hit.Locations, wantLocations[i])
+		}
+	}
+	// The collapsed hit is the first occurrence
+	if got[0].Filename != "deploy/prod.env" || got[0].Line != 3 {
+		t.Errorf("CollapseDuplicates() = %v:%v, want the first occurrence", got[0].Filename, got[0].Line)
+	}
+}
@@ -0,0 +1,50 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"io"
+
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+var (
+	utf8BOM    = []byte{0xEF, 0xBB, 0xBF}
+	utf16LEBOM = []byte{0xFF, 0xFE}
+	utf16BEBOM = []byte{0xFE, 0xFF}
+)
+
+// decodeBOM returns a reader of the file content as UTF-8.  Files starting with a UTF-16 byte order mark, like
+// PowerShell scripts saved on Windows, are transcoded and a UTF-8 byte order mark is dropped.  Files without a byte
+// order mark are read as is.  The line breaks are kept, so the line numbers still match the original file.  The reader
+// buffers a sample large enough to sniff binary content.
+func decodeBOM(r io.Reader) *bufio.Reader {
+	reader := bufio.NewReaderSize(r, binarySampleLength)
+	bom, _ := reader.Peek(len(utf8BOM))
+	switch {
+	case bytes.HasPrefix(bom, utf8BOM):
+		reader.Discard(len(utf8BOM))
+	case bytes.HasPrefix(bom, utf16LEBOM):
+		return bufio.NewReaderSize(transform.NewReader(reader, unicode.UTF16(unicode.LittleEndian, unicode.ExpectBOM).NewDecoder()), binarySampleLength)
+	case bytes.HasPrefix(bom, utf16BEBOM):
+		return bufio.NewReaderSize(transform.NewReader(reader, unicode.UTF16(unicode.BigEndian, unicode.ExpectBOM).NewDecoder()), binarySampleLength)
+	}
+	return reader
+}
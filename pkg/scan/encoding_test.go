@@ -0,0 +1,101 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_decodeBOM(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "UTF-16LE",
+			input: "\xff\xfep\x00w\x00=\x00\xe9\x00\n\x00",
+			want:  "pw=é\n",
+		},
+		{
+			name:  "UTF-16BE",
+			input: "\xfe\xff\x00p\x00w\x00=\x00\xe9\x00\n",
+			want:  "pw=é\n",
+		},
+		{
+			name:  "UTF-8 with BOM",
+			input: "\xef\xbb\xbfpw=\xc3\xa9\n",
+			want:  "pw=é\n",
+		},
+		{
+			name:  "No BOM is read as is",
+			input: "pw=\xe9\n",
+			want:  "pw=\xe9\n",
+		},
+		{
+			name:  "Empty file",
+			input: "",
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got strings.Builder
+			if _, err := decodeBOM(strings.NewReader(tt.input)).WriteTo(&got); err != nil {
+				t.Fatalf("decodeBOM() err = %v", err)
+			}
+			if got.String() != tt.want {
+				t.Errorf("decodeBOM() = %q, want %q", got.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestScanFilesUTF16(t *testing.T) {
+	tests := []struct {
+		name string
+		path string
+	}{
+		{name: "UTF-16LE PowerShell script", path: "test_data/password_utf16le.ps1"},
+		{name: "UTF-16BE PowerShell script", path: "test_data/password_utf16be.ps1"},
+		{name: "UTF-8 with BOM PowerShell script", path: "test_data/password_utf8bom.ps1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The secret is shown to compare the decoded line
+			cfg := cfg
+			cfg.ShowSecrets = true
+			hits := make(chan Hit)
+			go SearchFiles(&cfg, []File{{Name: tt.path, Path: tt.path}}, nil, nil, hits)
+
+			var found bool
+			for hit := range hits {
+				if hit.Code != 3001 {
+					continue
+				}
+				found = true
+				if hit.Line != 2 || hit.LineValue != `$password = "SecretValue1673"` {
+					t.Errorf("SearchFiles() hit on line %v %q, want line 2 with the decoded text", hit.Line, hit.LineValue)
+				}
+			}
+			if !found {
+				t.Errorf("SearchFiles() didn't find the password in %v", tt.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Fingerprint is the hash identifying a finding in the baseline and the GitLab report.  It's the SHA-256 of the rule
+// code, the path of the file relative to the scanned directory with forward slashes and the normalized secret, so it's
+// the same on every machine and OS the repository is checked out on.  The line number isn't part of it, so the finding
+// keeps its hash when lines are added above it, and the secret is hashed before it's masked.  The hash is taken once
+// by the scan, so making the paths relative to another directory for the reports doesn't change it.
+func Fingerprint(hit Hit, searchDir string) string {
+	if hit.fingerprint != "" {
+		return hit.fingerprint
+	}
+	digest := sha256.Sum256([]byte(strconv.Itoa(hit.Code) + "\x00" + RelativeFileName(hit.Filename, searchDir) + "\x00" + fingerprintValue(hit)))
+	return hex.EncodeToString(digest[:])
+}
+
+// fingerprintValue normalizes the secret of the hit, dropping the surrounding white space, e.g. the carriage return of
+// a file checked out with Windows line endings, and quotes
+func fingerprintValue(hit Hit) string {
+	return strings.Trim(strings.TrimSpace(hit.unmaskedValue()), "\"'`")
+}
+
+// RelativeFileName makes the file relative to the scanned directory, so the findings don't depend on where the repository is checked out.
+// When a single file is scanned, it's its name, and a path which is already relative, e.g. ./config/app.yaml, is cleaned.
+func RelativeFileName(fileName, searchDir string) string {
+	if searchDir != "" {
+		if rel, err := filepath.Rel(searchDir, fileName); err == nil && rel == "." {
+			return filepath.Base(fileName)
+		} else if err == nil && !strings.HasPrefix(rel, "..") {
+			return filepath.ToSlash(rel)
+		}
+	}
+	if fileName == "" {
+		return fileName
+	}
+	return path.Clean(filepath.ToSlash(fileName))
+}
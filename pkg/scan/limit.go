@@ -0,0 +1,44 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+// LimitHits passes on the first max findings and calls stop when the scan finds more, so the scan ends early rather
+// than collecting findings which aren't reported.  The findings suppressed with an inline comment aren't counted.  The
+// findings still in flight once stopped are dropped, and truncated is set before the channel returned is closed.
+func LimitHits(hits <-chan Hit, max int, stop func(), truncated *bool) chan Hit {
+	limited := make(chan Hit)
+	go func() {
+		defer close(limited)
+		count := 0
+		for hit := range hits {
+			if *truncated {
+				// Drain the findings sent before the scan stopped
+				continue
+			}
+			if !hit.Suppressed {
+				if count == max {
+					*truncated = true
+					stop()
+					continue
+				}
+				count++
+			}
+			limited <- hit
+		}
+	}()
+	return limited
+}
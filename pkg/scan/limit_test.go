@@ -0,0 +1,123 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLimitHits(t *testing.T) {
+	tests := []struct {
+		name          string
+		hits          []Hit
+		max           int
+		wantCount     int
+		wantTruncated bool
+	}{
+		{
+			name:      "Under the maximum",
+			hits:      []Hit{{Line: 1}, {Line: 2}},
+			max:       3,
+			wantCount: 2,
+		},
+		{
+			name:      "At the maximum",
+			hits:      []Hit{{Line: 1}, {Line: 2}, {Line: 3}},
+			max:       3,
+			wantCount: 3,
+		},
+		{
+			name:          "Over the maximum",
+			hits:          []Hit{{Line: 1}, {Line: 2}, {Line: 3}, {Line: 4}, {Line: 5}},
+			max:           2,
+			wantCount:     2,
+			wantTruncated: true,
+		},
+		{
+			name:      "Suppressed findings aren't counted",
+			hits:      []Hit{{Line: 1, Suppressed: true}, {Line: 2}, {Line: 3}},
+			max:       2,
+			wantCount: 3,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := make(chan Hit, len(tt.hits))
+			for _, hit := range tt.hits {
+				hits <- hit
+			}
+			close(hits)
+			var truncated bool
+			stopped := 0
+			count := 0
+			for range LimitHits(hits, tt.max, func() { stopped++ }, &truncated) {
+				count++
+			}
+			if count != tt.wantCount || truncated != tt.wantTruncated {
+				t.Errorf("LimitHits() = %d hits, truncated %v, want %d hits, truncated %v", count, truncated, tt.wantCount, tt.wantTruncated)
+			}
+			wantStopped := 0
+			if tt.wantTruncated {
+				wantStopped = 1
+			}
+			if stopped != wantStopped {
+				t.Errorf("LimitHits() stopped the scan %d times, want %d", stopped, wantStopped)
+			}
+		})
+	}
+}
+
+func TestLimitHitsStopsScan(t *testing.T) {
+	dir := t.TempDir()
+	var files []File
+	for i := 0; i < 50; i++ {
+		filePath := filepath.Join(dir, fmt.Sprintf("fixture%02d.txt", i))
+		if err := os.WriteFile(filePath, []byte(strings.Repeat("password = \"Sup3rS3cretValue!\"\n", 20)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, File{Name: filePath, Path: filePath})
+	}
+	limitCfg := cfg
+	limitCfg.WorkerCount = 1
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	hits := make(chan Hit)
+	go SearchFilesContext(ctx, &limitCfg, files, nil, nil, hits)
+	var truncated bool
+	count := 0
+	for range LimitHits(hits, 5, stop, &truncated) {
+		count++
+	}
+	if count != 5 || !truncated {
+		t.Fatalf("LimitHits() = %d hits, truncated %v, want 5 hits, truncated", count, truncated)
+	}
+	canceled := 0
+	for _, file := range files {
+		if file.Skipped == skipCanceled {
+			canceled++
+		}
+	}
+	if canceled == 0 {
+		t.Error("SearchFilesContext() scanned every file, want the scan stopped once truncated")
+	}
+}
@@ -0,0 +1,67 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"strings"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// MaskSecret keeps the start of the value, usually the name of the key, and masks the rest of it.  Masking a masked value
+// returns it unchanged.
+func MaskSecret(value string) string {
+	runes := []rune(value)
+	visible := len(runes) / 4
+	if visible > maskPrefixLength {
+		visible = maskPrefixLength
+	}
+	return string(runes[:visible]) + strings.Repeat(maskCharacter, len(runes)-visible)
+}
+
+// maskHit masks the secret of the hit, on its line and on its context lines, before it reaches the writers, unless the
+// secrets are shown.  The secret is kept unmasked in the hit, so the findings are still identified by their secret once
+// masked.  Suppressed secrets are masked completely, along with the whole line.
+func maskHit(cfg *cfgReader.EarlybirdConfig, hit Hit) Hit {
+	if hit.MatchValue == "" || (cfg.ShowSecrets && !cfg.Suppress) {
+		return hit
+	}
+	hit.secret = hit.MatchValue
+	if cfg.Suppress {
+		hit.MatchValue = maskValue(hit.MatchValue)
+		hit.LineValue = maskValue(hit.LineValue)
+		return hit
+	}
+	masked := MaskSecret(hit.secret)
+	if strings.Contains(hit.LineValue, hit.secret) {
+		hit.LineValue = strings.ReplaceAll(hit.LineValue, hit.secret, masked)
+	} else {
+		// The match value was changed by the post processing, the secret on the line is unknown
+		hit.LineValue = MaskSecret(hit.LineValue)
+	}
+	hit.MatchValue = masked
+	hit.Context = maskContext(hit)
+	return hit
+}
+
+// unmaskedValue is the match value of the hit before it was masked
+func (hit Hit) unmaskedValue() string {
+	if hit.secret != "" {
+		return hit.secret
+	}
+	return hit.MatchValue
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+func TestMaskSecret(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		want  string
+	}{
+		{
+			name:  "Keep the name of the key",
+			value: `password = "SecretValue1673"`,
+			want:  "pass************************",
+		},
+		{
+			name:  "Mask short values completely",
+			value: "abc",
+			want:  "***",
+		},
+		{
+			name:  "Count characters rather than bytes",
+			value: "clé=sécurité",
+			want:  "clé*********",
+		},
+		{
+			name:  "Masked value is unchanged",
+			value: "pass************************",
+			want:  "pass************************",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MaskSecret(tt.value); got != tt.want {
+				t.Errorf("MaskSecret() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_maskHit(t *testing.T) {
+	hit := Hit{MatchValue: `password = "SecretValue1673"`, LineValue: `  password = "SecretValue1673";`}
+	tests := []struct {
+		name           string
+		cfg            cfgReader.EarlybirdConfig
+		hit            Hit
+		wantMatchValue string
+		wantLineValue  string
+	}{
+		{
+			name:           "Mask the secret on the line",
+			hit:            hit,
+			wantMatchValue: "pass************************",
+			wantLineValue:  "  pass************************;",
+		},
+		{
+			name:           "Show the secrets",
+			cfg:            cfgReader.EarlybirdConfig{ShowSecrets: true},
+			hit:            hit,
+			wantMatchValue: `password = "SecretValue1673"`,
+			wantLineValue:  `  password = "SecretValue1673";`,
+		},
+		{
+			name:           "Suppressed secrets are masked completely",
+			cfg:            cfgReader.EarlybirdConfig{Suppress: true, ShowSecrets: true},
+			hit:            hit,
+			wantMatchValue: strings.Repeat("*", 28),
+			wantLineValue:  strings.Repeat("*", 31),
+		},
+		{
+			name:           "Mask the line when the match value was changed by the post processing",
+			hit:            Hit{MatchValue: "4111********1111", LineValue: "card=4111111111111111"},
+			wantMatchValue: "4111************",
+			wantLineValue:  "card*****************",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := maskHit(&tt.cfg, tt.hit)
+			if got.MatchValue != tt.wantMatchValue || got.LineValue != tt.wantLineValue {
+				t.Errorf("maskHit() = %q %q, want %q %q", got.MatchValue, got.LineValue, tt.wantMatchValue, tt.wantLineValue)
+			}
+			if got.unmaskedValue() != tt.hit.MatchValue {
+				t.Errorf("maskHit() unmasked value = %q, want %q", got.unmaskedValue(), tt.hit.MatchValue)
+			}
+		})
+	}
+}
+
+func TestSearchFilesMasked(t *testing.T) {
+	dir := t.TempDir()
+	var files []File
+	// The passwords only differ after the masked prefix
+	for i, secret := range []string{"SecretValue1673", "SecretValue9402", "SecretValue1673"} {
+		filePath := path.Join(dir, fmt.Sprintf("settings%d.py", i))
+		if err := os.WriteFile(filePath, []byte(`password = "`+secret+`"`+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, File{Name: filePath, Path: filePath})
+	}
+	cfg := cfg
+	cfg.WorkerCount = 1
+	hits := make(chan Hit)
+	go SearchFiles(&cfg, files, nil, nil, hits)
+
+	var found []Hit
+	for hit := range CollapseDuplicates(hits) {
+		if hit.Code != 3001 {
+			continue
+		}
+		if strings.Contains(hit.MatchValue, "SecretValue") || strings.Contains(hit.LineValue, "SecretValue") {
+			t.Errorf("SearchFiles() hit %q %q isn't masked", hit.MatchValue, hit.LineValue)
+		}
+		found = append(found, hit)
+	}
+	if len(found) != 2 || len(found[0].Locations) != 2 || len(found[1].Locations) != 1 {
+		t.Fatalf("CollapseDuplicates() = %v, want the two passwords told apart by their unmasked value", found)
+	}
+	// The baseline identifies the finding by its secret rather than its masked value
+	unmasked := found[0]
+	unmasked.MatchValue, unmasked.secret = `password = "SecretValue1673"`, ""
+	if Fingerprint(found[0], dir) != Fingerprint(unmasked, dir) {
+		t.Error("Fingerprint() of the masked hit differs from the hit of the secret")
+	}
+}
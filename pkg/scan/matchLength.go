@@ -0,0 +1,49 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"unicode/utf8"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// truncatedMarker ends the match values cut at the maximum match length
+const truncatedMarker = "..."
+
+// limitMatchLength bounds the match value of the hit to the maximum match length of the rule, or of the configuration
+// when the rule has none, so a greedy pattern matching a minified line doesn't carry megabytes to the writers and the
+// fingerprints.  A longer value is cut on a character boundary and ends with truncatedMarker, or the hit is dropped
+// with DropLongMatches, returning false.
+func (hit *Hit) limitMatchLength(cfg *cfgReader.EarlybirdConfig, rule *Rule) bool {
+	limit := rule.MaxMatchLength
+	if limit <= 0 {
+		limit = cfg.MaxMatchLength
+	}
+	if limit <= 0 || len(hit.MatchValue) <= limit {
+		return true
+	}
+	if cfg.DropLongMatches {
+		return false
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(hit.MatchValue[cut]) {
+		cut--
+	}
+	hit.MatchValue = hit.MatchValue[:cut] + truncatedMarker
+	return true
+}
@@ -0,0 +1,133 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+func TestLimitMatchLength(t *testing.T) {
+	tests := []struct {
+		name           string
+		ruleLimit      int
+		cfgLimit       int
+		drop           bool
+		matchValue     string
+		wantMatchValue string
+		wantKept       bool
+	}{
+		{
+			name:           "Shorter than the limit",
+			cfgLimit:       16,
+			matchValue:     "Kx9vLq2Tz7",
+			wantMatchValue: "Kx9vLq2Tz7",
+			wantKept:       true,
+		},
+		{
+			name:           "Truncated at the limit of the configuration",
+			cfgLimit:       4,
+			matchValue:     "Kx9vLq2Tz7",
+			wantMatchValue: "Kx9v...",
+			wantKept:       true,
+		},
+		{
+			name:           "The limit of the rule takes precedence",
+			ruleLimit:      6,
+			cfgLimit:       4,
+			matchValue:     "Kx9vLq2Tz7",
+			wantMatchValue: "Kx9vLq...",
+			wantKept:       true,
+		},
+		{
+			name:           "Truncated on a character boundary",
+			cfgLimit:       4,
+			matchValue:     "Kx9é2Tz7",
+			wantMatchValue: "Kx9...",
+			wantKept:       true,
+		},
+		{
+			name:       "Dropped",
+			cfgLimit:   4,
+			drop:       true,
+			matchValue: "Kx9vLq2Tz7",
+		},
+		{
+			name:           "No limit",
+			matchValue:     "Kx9vLq2Tz7",
+			wantMatchValue: "Kx9vLq2Tz7",
+			wantKept:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hit := Hit{MatchValue: tt.matchValue}
+			kept := hit.limitMatchLength(&cfgReader.EarlybirdConfig{MaxMatchLength: tt.cfgLimit, DropLongMatches: tt.drop}, &Rule{MaxMatchLength: tt.ruleLimit})
+			if kept != tt.wantKept {
+				t.Fatalf("limitMatchLength() = %v, want %v", kept, tt.wantKept)
+			}
+			if kept && hit.MatchValue != tt.wantMatchValue {
+				t.Errorf("limitMatchLength() match value = %q, want %q", hit.MatchValue, tt.wantMatchValue)
+			}
+		})
+	}
+}
+
+func TestScanLineMaxMatchLength(t *testing.T) {
+	defer func(rules []Rule) { CombinedRules = rules }(CombinedRules)
+	CombinedRules = []Rule{
+		{Code: 9001, Caption: "Greedy", Severity: 2, Confidence: 2, CompiledPattern: regexp.MustCompile(`data=.+`)},
+		{Code: 9002, Caption: "Bounded", Severity: 2, Confidence: 2, CompiledPattern: regexp.MustCompile(`data=.+`), MaxMatchLength: 64},
+	}
+	// A minified line of 2MB
+	line := Line{LineValue: "var data=" + strings.Repeat("x", 2<<20), LineNum: 1, FilePath: "app.min.js", FileName: "app.min.js"}
+
+	tests := []struct {
+		name      string
+		drop      bool
+		wantCodes map[int]int
+	}{
+		{
+			name:      "Truncated",
+			wantCodes: map[int]int{9001: 1024 + len(truncatedMarker), 9002: 64 + len(truncatedMarker)},
+		},
+		{
+			name:      "Dropped",
+			drop:      true,
+			wantCodes: map[int]int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lengthCfg := cfg
+			lengthCfg.MaxMatchLength, lengthCfg.DropLongMatches = 1024, tt.drop
+			_, hits := scanLine(context.Background(), line, nil, &lengthCfg)
+			if len(hits) != len(tt.wantCodes) {
+				t.Fatalf("scanLine() found %d hits, want %d", len(hits), len(tt.wantCodes))
+			}
+			for _, hit := range hits {
+				if len(hit.MatchValue) != tt.wantCodes[hit.Code] || !strings.HasSuffix(hit.MatchValue, truncatedMarker) {
+					t.Errorf("scanLine() rule %d match value of %d bytes, want %d bytes ending with %q", hit.Code, len(hit.MatchValue), tt.wantCodes[hit.Code], truncatedMarker)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"regexp"
+	"strings"
+)
+
+// pemBeginPattern matches the BEGIN marker of a PEM private key block, capturing its label, e.g. RSA PRIVATE KEY
+var pemBeginPattern = regexp.MustCompile(`-----BEGIN ([A-Z0-9 ]*PRIVATE KEY(?: BLOCK)?)-----`)
+
+// unterminatedLabel flags the private key blocks without an END marker
+const unterminatedLabel = "unterminated block"
+
+// pemBlock is a private key block of a file, from the index of the line of its BEGIN marker to the index of the line of
+// its END marker, or of the last line before the next block or the end of the file when it isn't terminated
+type pemBlock struct {
+	start, end int
+	terminated bool
+}
+
+// pemBlocks finds the private key blocks spanning several lines, each block ends at the END marker with the label of
+// its BEGIN marker
+func pemBlocks(lines []Line) (blocks []pemBlock) {
+	for i := 0; i < len(lines); i++ {
+		match := pemBeginPattern.FindStringSubmatchIndex(lines[i].LineValue)
+		if match == nil {
+			continue
+		}
+		endMarker := "-----END " + lines[i].LineValue[match[2]:match[3]] + "-----"
+		if strings.Contains(lines[i].LineValue[match[1]:], endMarker) {
+			// The whole key is on the line, e.g. in a string with escaped line breaks, the line hits report it
+			continue
+		}
+		block := pemBlock{start: i, end: len(lines) - 1}
+		for j := i + 1; j < len(lines); j++ {
+			if strings.Contains(lines[j].LineValue, endMarker) {
+				block.end, block.terminated = j, true
+				break
+			}
+			if pemBeginPattern.MatchString(lines[j].LineValue) {
+				block.end = j - 1
+				break
+			}
+		}
+		blocks = append(blocks, block)
+		i = block.end
+	}
+	return blocks
+}
+
+// collapsePEMBlocks reports each private key block of the file as a single hit on the line of its BEGIN marker, the hit
+// of the first private key rule matching the marker.  Its match is the whole block and the hits of the other rules on
+// the lines of the block, e.g. the entropy of the key, are dropped.  The blocks without a private key rule hit are left
+// as they are.
+func collapsePEMBlocks(hits []Hit, lines []Line) []Hit {
+	var blockHits bool
+	for _, hit := range hits {
+		blockHits = blockHits || hit.pemBlock
+	}
+	if !blockHits {
+		return hits
+	}
+	for _, block := range pemBlocks(lines) {
+		startLine, endLine := lines[block.start].LineNum, lines[block.end].LineNum
+		first := -1
+		for i, hit := range hits {
+			if hit.pemBlock && hit.Line == startLine {
+				first = i
+				break
+			}
+		}
+		if first < 0 {
+			continue
+		}
+		blockHit := hits[first]
+		values := make([]string, 0, block.end-block.start+1)
+		for _, line := range lines[block.start : block.end+1] {
+			values = append(values, strings.TrimSpace(line.LineValue))
+		}
+		blockHit.MatchValue = strings.Join(values, "\n")
+		blockHit.EndLine = endLine
+		if !block.terminated {
+			blockHit.Labels = append(blockHit.Labels, unterminatedLabel)
+		}
+		collapsed := hits[:0]
+		for i, hit := range hits {
+			switch {
+			case i == first:
+				collapsed = append(collapsed, blockHit)
+			case hit.Line < startLine || hit.Line > endLine:
+				collapsed = append(collapsed, hit)
+			}
+		}
+		hits = collapsed
+	}
+	return hits
+}
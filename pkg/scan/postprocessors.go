@@ -0,0 +1,42 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import "github.com/americanexpress/earlybird/v4/pkg/postprocess"
+
+// PostProcessor validates the match value of a hit.  It returns false to drop the hit, or true with the confidence ID to
+// report the hit at, where 0 keeps the confidence of the rule.
+type PostProcessor func(hit Hit) (keep bool, confidence int)
+
+// postProcessors are the post-processors referenced by name from the Postprocess field of the rules
+var postProcessors = map[string]PostProcessor{
+	"aws-key": awsKeyPostProcessor,
+}
+
+// RegisterPostProcessor adds a post-processor the rules can reference by name, replacing any post-processor of the same
+// name.  It must be called before the scan starts; the built-in names such as password or mod10 take precedence.
+func RegisterPostProcessor(name string, processor PostProcessor) {
+	postProcessors[name] = processor
+}
+
+// awsKeyPostProcessor drops the AWS key IDs that can't be real and raises the confidence of the well formed ones by a level
+func awsKeyPostProcessor(hit Hit) (bool, int) {
+	if !postprocess.ValidAWSKeyID(hit.MatchValue) {
+		return false, 0
+	}
+	return true, max(hit.ConfidenceID-1, 1)
+}
@@ -0,0 +1,98 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// progressOutput is where the progress of the scan is reported, apart from the findings
+var progressOutput io.Writer = os.Stderr
+
+// progress reports the number of files scanned out of the total, with the scan rate and an estimate of the remaining time
+type progress struct {
+	w        io.Writer
+	total    int
+	done     int
+	start    time.Time
+	last     time.Time // Time of the last report
+	interval time.Duration
+	// The reports overwrite each other on a terminal, they're printed on separate lines otherwise, e.g. in CI logs
+	terminal bool
+	now      func() time.Time
+}
+
+// newProgress starts reporting the progress of a scan of total files to w
+func newProgress(w io.Writer, total int) *progress {
+	p := &progress{w: w, total: total, interval: progressLogInterval, terminal: isTerminal(w), now: time.Now}
+	if p.terminal {
+		p.interval = progressTerminalInterval
+	}
+	p.start = p.now()
+	p.last = p.start
+	return p
+}
+
+// fileDone counts a scanned file, the progress is reported once the interval elapsed since the last report and when the
+// last file is scanned
+func (p *progress) fileDone() {
+	if p == nil {
+		return
+	}
+	p.done++
+	now := p.now()
+	if p.done < p.total && now.Sub(p.last) < p.interval {
+		return
+	}
+	p.last = now
+	if !p.terminal {
+		fmt.Fprintln(p.w, p.status(now))
+		return
+	}
+	// Clear the rest of the previous report, which may be longer
+	fmt.Fprintf(p.w, "\r%s\x1b[K", p.status(now))
+	if p.done >= p.total {
+		fmt.Fprintln(p.w)
+	}
+}
+
+// status describes the progress of the scan at the time now
+func (p *progress) status(now time.Time) string {
+	var rate float64
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		rate = float64(p.done) / elapsed
+	}
+	status := fmt.Sprintf("Scanned %d/%d files (%.1f files/s)", p.done, p.total, rate)
+	if p.done < p.total && rate > 0 {
+		eta := time.Duration(float64(p.total-p.done) / rate * float64(time.Second))
+		status += fmt.Sprintf(", ETA %v", eta.Round(time.Second))
+	}
+	return status
+}
+
+// isTerminal reports if w is a terminal
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
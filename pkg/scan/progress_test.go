@@ -0,0 +1,81 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+	"testing"
+	"time"
+)
+
+func Test_progress(t *testing.T) {
+	var output bytes.Buffer
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	p := newProgress(&output, 4)
+	p.start, p.last = start, start
+	// Seconds elapsed when each file is done
+	for _, elapsed := range []int{1, 2, 10, 11} {
+		p.now = func() time.Time { return start.Add(time.Duration(elapsed) * time.Second) }
+		p.fileDone()
+	}
+	// The first files are done within the interval, the last file is always reported
+	want := "Scanned 3/4 files (0.3 files/s), ETA 3s\nScanned 4/4 files (0.4 files/s)\n"
+	if got := output.String(); got != want {
+		t.Errorf("progress = %q, want %q", got, want)
+	}
+}
+
+func TestSearchFilesProgress(t *testing.T) {
+	dir := t.TempDir()
+	var files []File
+	for i := 0; i < 5; i++ {
+		filePath := path.Join(dir, fmt.Sprintf("file%d.txt", i))
+		if err := os.WriteFile(filePath, []byte("nothing to see here\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, File{Name: filePath, Path: filePath})
+	}
+	defer func() { progressOutput = os.Stderr }()
+
+	tests := []struct {
+		name  string
+		quiet bool
+		want  string
+	}{
+		{name: "Progress of the files", want: "Scanned 5/5 files"},
+		{name: "No progress in quiet mode", quiet: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output bytes.Buffer
+			progressOutput = &output
+			cfg := cfg
+			cfg.Progress, cfg.Quiet = true, tt.quiet
+			hits := make(chan Hit)
+			go SearchFiles(&cfg, files, nil, nil, hits)
+			for range hits {
+			}
+			if got := output.String(); !strings.HasPrefix(got, tt.want) || (tt.want == "") != (got == "") {
+				t.Errorf("SearchFiles() progress = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
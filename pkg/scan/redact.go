@@ -0,0 +1,44 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"fmt"
+	"strings"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// redact replaces the file content logged, e.g. a match value, with its length so the logs don't become a second copy
+// of the secrets.  The content is only logged as is with --unsafe-log.
+func redact(cfg *cfgReader.EarlybirdConfig, content string) string {
+	if cfg.UnsafeLog {
+		return content
+	}
+	return fmt.Sprintf("[REDACTED %d bytes]", len(content))
+}
+
+// logHit logs the rule code and the location of the hit in verbose mode, the byte offset of the match in the line being
+// -1 when the post processing changed the match value
+func logHit(cfg *cfgReader.EarlybirdConfig, hit Hit, line Line) {
+	if !cfg.VerboseEnabled {
+		return
+	}
+	offset := strings.Index(line.LineValue, hit.MatchValue)
+	utils.InfoLog.Printf("Rule %d matched %s:%d at offset %d: %s\n", hit.Code, hit.Filename, hit.Line, offset, redact(cfg, hit.MatchValue))
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"bytes"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+func Test_redact(t *testing.T) {
+	tests := []struct {
+		name      string
+		unsafeLog bool
+		content   string
+		want      string
+	}{
+		{name: "Redact the secret", content: "SecretValue1673", want: "[REDACTED 15 bytes]"},
+		{name: "Redact an empty value", content: "", want: "[REDACTED 0 bytes]"},
+		{name: "Log the secret with unsafe logging", unsafeLog: true, content: "SecretValue1673", want: "SecretValue1673"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := redact(&cfgReader.EarlybirdConfig{UnsafeLog: tt.unsafeLog}, tt.content); got != tt.want {
+				t.Errorf("redact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilesVerboseLog(t *testing.T) {
+	secret := "SecretValue1673"
+	filePath := path.Join(t.TempDir(), "settings.py")
+	if err := os.WriteFile(filePath, []byte("debug = True\npassword = \""+secret+"\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	var logs bytes.Buffer
+	utils.InfoLog.SetOutput(&logs)
+	defer utils.SetQuiet(false)
+
+	for _, unsafeLog := range []bool{false, true} {
+		logs.Reset()
+		cfg := cfg
+		cfg.VerboseEnabled = true
+		cfg.UnsafeLog = unsafeLog
+		hits := make(chan Hit)
+		go SearchFiles(&cfg, []File{{Name: "settings.py", Path: filePath}}, nil, nil, hits)
+		for range hits {
+		}
+
+		if !strings.Contains(logs.String(), "Rule 3001 matched "+filePath+":2 at offset ") {
+			t.Errorf("SearchFiles() verbose log = %q, want the rule code and offset of the password", logs.String())
+		}
+		if strings.Contains(logs.String(), secret) != unsafeLog {
+			t.Errorf("SearchFiles() with unsafe log %v logged %q", unsafeLog, logs.String())
+		}
+	}
+}
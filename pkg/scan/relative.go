@@ -0,0 +1,70 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// RelativeHits makes the file paths of the hits relative to the base directory, with forward slashes, before they reach
+// the writers.  The files outside of the base directory keep their absolute path, with a warning for each of them, and
+// the paths which aren't absolute, e.g. stdin, are kept as they are.
+func RelativeHits(hits <-chan Hit, baseDir string) chan Hit {
+	relative := make(chan Hit)
+	go func() {
+		defer close(relative)
+		warned := make(map[string]bool)
+		relativize := func(fileName string) string {
+			rel, ok := relativePath(fileName, baseDir)
+			if !ok && !warned[fileName] {
+				warned[fileName] = true
+				log.Printf("Warning: %s is outside of the base directory %s, its findings keep the absolute path", fileName, baseDir)
+			}
+			return rel
+		}
+		for hit := range hits {
+			hit.Filename = relativize(hit.Filename)
+			if len(hit.Locations) > 0 {
+				locations := make([]Location, len(hit.Locations))
+				for i, location := range hit.Locations {
+					locations[i] = Location{Filename: relativize(location.Filename), Line: location.Line}
+				}
+				hit.Locations = locations
+			}
+			relative <- hit
+		}
+	}()
+	return relative
+}
+
+// relativePath returns the path relative to the base directory, or the path unchanged and false when it's outside of it
+func relativePath(fileName, baseDir string) (string, bool) {
+	if !filepath.IsAbs(fileName) {
+		return fileName, true
+	}
+	rel, err := filepath.Rel(baseDir, fileName)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fileName, false
+	}
+	if rel == "." {
+		// The base is the file scanned itself
+		return filepath.Base(fileName), true
+	}
+	return filepath.ToSlash(rel), true
+}
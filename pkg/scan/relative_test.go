@@ -0,0 +1,124 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRelativeHits(t *testing.T) {
+	base := filepath.Join(string(filepath.Separator), "work", "repo")
+	outside := filepath.Join(string(filepath.Separator), "tmp", "other", "app.env")
+	hits := make(chan Hit)
+	go func() {
+		defer close(hits)
+		for _, hit := range []Hit{
+			{Code: 3001, Filename: filepath.Join(base, "config", "settings.py"), Line: 12},
+			{Code: 3001, Filename: outside, Line: 1},
+			{Code: 3001, Filename: "stdin", Line: 2},
+			{Code: 1002, Filename: filepath.Join(base, "prod.env"), Line: 3, Locations: []Location{
+				{Filename: filepath.Join(base, "prod.env"), Line: 3},
+				{Filename: outside, Line: 5},
+			}},
+		} {
+			hits <- hit
+		}
+	}()
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	defer log.SetOutput(os.Stderr)
+	var got []Hit
+	for hit := range RelativeHits(hits, base) {
+		got = append(got, hit)
+	}
+
+	wantFilenames := []string{"config/settings.py", outside, "stdin", "prod.env"}
+	for i, hit := range got {
+		if hit.Filename != wantFilenames[i] {
+			t.Errorf("RelativeHits() hit %d filename = %s, want %s", i, hit.Filename, wantFilenames[i])
+		}
+	}
+	wantLocations := []Location{{Filename: "prod.env", Line: 3}, {Filename: outside, Line: 5}}
+	if !reflect.DeepEqual(got[3].Locations, wantLocations) {
+		t.Errorf("RelativeHits() locations = %v, want %v", got[3].Locations, wantLocations)
+	}
+	if warnings := strings.Count(logs.String(), "Warning: "+outside); warnings != 1 {
+		t.Errorf("RelativeHits() logged %d warnings for %s, want 1:\n%s", warnings, outside, logs.String())
+	}
+}
+
+func Test_relativePath(t *testing.T) {
+	base := filepath.Join(string(filepath.Separator), "work", "repo")
+	tests := []struct {
+		name     string
+		fileName string
+		want     string
+		wantOk   bool
+	}{
+		{
+			name:     "Inside the base directory",
+			fileName: filepath.Join(base, "src", "main.go"),
+			want:     "src/main.go",
+			wantOk:   true,
+		},
+		{
+			name:     "Outside the base directory",
+			fileName: filepath.Join(string(filepath.Separator), "work", "other", "main.go"),
+			want:     filepath.Join(string(filepath.Separator), "work", "other", "main.go"),
+			wantOk:   false,
+		},
+		{
+			name:     "Sibling directory sharing the prefix",
+			fileName: filepath.Join(string(filepath.Separator), "work", "repo2", "main.go"),
+			want:     filepath.Join(string(filepath.Separator), "work", "repo2", "main.go"),
+			wantOk:   false,
+		},
+		{
+			name:     "File name starting with two dots",
+			fileName: filepath.Join(base, "..env"),
+			want:     "..env",
+			wantOk:   true,
+		},
+		{
+			name:     "Base which is the file itself",
+			fileName: base,
+			want:     "repo",
+			wantOk:   true,
+		},
+		{
+			name:     "Path which isn't absolute",
+			fileName: "stdin",
+			want:     "stdin",
+			wantOk:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := relativePath(tt.fileName, base)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("relativePath() = %v, %v, want %v, %v", got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
@@ -17,20 +17,28 @@
 package scan
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path"
 	"path/filepath"
 	"regexp"
+	"sort"
+	"strings"
 
 	cfgreader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+	"github.com/americanexpress/earlybird/v4/pkg/wildcard"
+	"github.com/ghodss/yaml"
 )
 
 // Init loads in all the Earlybird rules into the CombinedRules global variable
 func Init(cfg cfgreader.EarlybirdConfig) {
-	if cfg.OutputFormat != "json" && !cfg.HideMeta {
-		log.Println("Go-EarlyBird version: ", cfg.Version)
+	// The JSON reports are written to stdout as well, keep them parseable
+	if cfg.OutputFormat != "json" && cfg.OutputFormat != "ndjson" && !cfg.HideMeta && !cfg.Quiet {
+		utils.InfoLog.Println("Go-EarlyBird version: ", cfg.Version)
 		// Display options
 		fmt.Println("Severity Fail threshold (at or above): ", cfgreader.Settings.TranslateLevelID(cfg.SeverityFailLevel))
 		fmt.Println("Confidence Fail threshold (at or above): ", cfgreader.Settings.TranslateLevelID(cfg.ConfidenceFailLevel))
@@ -39,11 +47,60 @@ func Init(cfg cfgreader.EarlybirdConfig) {
 		fmt.Println("Max file size to scan: ", cfg.MaxFileSize, " bytes")
 	}
 
+	if err := LoadRules(cfg); err != nil {
+		utils.Fatal(err)
+	}
+
+	// If we're only displaying the rules to be run, filter out anything that we wouldn't fail on and exit to skip the scan.
+	// Only one output option since we exit before any interaction with Writers
+	if cfg.RulesOnly {
+		fmt.Println("\nShowing Rules Only (no scan to be executed)")
+		fmt.Println()
+		for _, combinedRule := range CombinedRules {
+			if combinedRule.Severity <= cfg.SeverityFailLevel && combinedRule.Confidence <= cfg.ConfidenceFailLevel {
+				fmt.Println("Code: ", combinedRule.Code)
+				fmt.Println("Caption: ", combinedRule.Caption)
+				fmt.Println("Pattern: ", combinedRule.Pattern)
+				fmt.Println("Severity: ", cfgreader.Settings.TranslateLevelID(combinedRule.Severity))
+				fmt.Println("Confidence: ", cfgreader.Settings.TranslateLevelID(combinedRule.Confidence))
+				fmt.Println("Solution: ", combinedRule.Solution)
+				fmt.Println("Example: ", combinedRule.Example)
+				fmt.Println()
+			}
+		}
+		os.Exit(0)
+	}
+}
+
+// LoadRules loads the rules of the enabled modules and the custom rules into CombinedRules, replacing the rules loaded
+// before, along with the labels, the false positive rules and the solutions.  Unlike Init, it returns the configuration
+// errors rather than exiting, e.g. for the library scans.
+func LoadRules(cfg cfgreader.EarlybirdConfig) error {
+	// Report every invalid pattern at once, before compiling the rules
+	if err := validateRules(ruleFilePaths(cfg)); err != nil {
+		return fmt.Errorf("invalid rule patterns:\n%w", err)
+	}
+
 	// Init rule set for modules
+	CombinedRules, rulesLoadErr = nil, nil
 	for moduleName, fileName := range cfg.EnabledModulesMap {
-		log.Println("loading module: ", moduleName)
+		utils.InfoLog.Println("loading module: ", moduleName)
 		CombinedRules = append(CombinedRules, loadRuleConfigs(cfg, moduleName, fileName)...)
 	}
+	if cfg.CustomRulesDir != "" {
+		utils.InfoLog.Println("loading custom rules: ", cfg.CustomRulesDir)
+		customRules, err := loadCustomRules(cfg)
+		if err != nil {
+			return fmt.Errorf("error loading custom rules: %w", err)
+		}
+		CombinedRules = mergeRules(CombinedRules, customRules)
+	}
+	for _, code := range unknownRuleCodes(cfg) {
+		log.Println("Warning: rule code", code, "doesn't match any rule of the enabled modules")
+	}
+	for _, category := range unknownCategories(cfg) {
+		log.Println("Warning: rule category", category, "doesn't match any rule of the enabled modules")
+	}
 
 	var err error
 	//Load solutions for the rules
@@ -51,7 +108,7 @@ func Init(cfg cfgreader.EarlybirdConfig) {
 		SolutionConfigs, err = loadSolutions(cfg.SolutionsConfigDir)
 
 		if err != nil {
-			log.Fatal("error loading solutions", err)
+			return fmt.Errorf("error loading solutions: %w", err)
 		}
 	}
 
@@ -59,82 +116,324 @@ func Init(cfg cfgreader.EarlybirdConfig) {
 	Labels, err = loadLabelConfigs(cfg.LabelsConfigDir)
 
 	if err != nil {
-		log.Fatal("error loading labels file")
+		return fmt.Errorf("error loading labels file: %w", err)
 	}
 
 	//Load false positive rules
 	FalsePositiveRules, err = loadFalsePositives(cfg.FalsePositivesConfigDir)
 
 	if err != nil {
-		log.Fatal("error loading false positive rules", err)
+		return fmt.Errorf("error loading false positive rules: %w", err)
 	}
 
-	// If we're only displaying the rules to be run, filter out anything that we wouldn't fail on and exit to skip the scan.
-	// Only one output option since we exit before any interaction with Writers
-	if cfg.RulesOnly {
-		fmt.Println("\nShowing Rules Only (no scan to be executed)")
-		fmt.Println()
-		for _, combinedRule := range CombinedRules {
-			if combinedRule.Severity <= cfg.SeverityFailLevel && combinedRule.Confidence <= cfg.ConfidenceFailLevel {
-				fmt.Println("Code: ", combinedRule.Code)
-				fmt.Println("Caption: ", combinedRule.Caption)
-				fmt.Println("Pattern: ", combinedRule.Pattern)
-				fmt.Println("Severity: ", cfgreader.Settings.TranslateLevelID(combinedRule.Severity))
-				fmt.Println("Confidence: ", cfgreader.Settings.TranslateLevelID(combinedRule.Confidence))
-				fmt.Println("Solution: ", combinedRule.Solution)
-				fmt.Println("Example: ", combinedRule.Example)
-				fmt.Println()
-			}
+	// Compile adjusted severity regex patterns
+	for i := range cfg.AdjustedSeverityCategories {
+		if cfg.AdjustedSeverityCategories[i].Category == "" {
+			return errors.New("missing required field category")
 		}
-		os.Exit(0)
-	}
 
-	// Compile adjusted severity regex patterns
-	if cfg.AdjustedSeverityCategories != nil {
-		for i := range cfg.AdjustedSeverityCategories {
-			if cfg.AdjustedSeverityCategories[i].Category == "" {
-				log.Fatal("Missing required field category")
-			}
+		if cfg.AdjustedSeverityCategories[i].Patterns == nil {
+			return errors.New("missing required field patterns")
+		}
 
-			if cfg.AdjustedSeverityCategories[i].Patterns == nil {
-				log.Fatal("Missing required field patterns")
-			}
+		if cfg.AdjustedSeverityCategories[i].AdjustedDisplaySeverity == "" {
+			return errors.New("missing required field adjusted_display_severity")
+		}
 
-			if cfg.AdjustedSeverityCategories[i].AdjustedDisplaySeverity == "" {
-				log.Fatal("Missing required field adjusted_display_severity")
+		cfg.AdjustedSeverityCategories[i].CompiledPatterns = nil
+		for _, regEx := range cfg.AdjustedSeverityCategories[i].Patterns {
+			compiled, err := regexp.Compile(regEx)
+			if err != nil {
+				return fmt.Errorf("invalid adjusted severity pattern of category %s: %w", cfg.AdjustedSeverityCategories[i].Category, err)
 			}
 
-			for _, regEx := range cfg.AdjustedSeverityCategories[i].Patterns {
-				compiled := regexp.MustCompile(regEx)
-
-				cfg.AdjustedSeverityCategories[i].CompiledPatterns = append(cfg.AdjustedSeverityCategories[i].CompiledPatterns, compiled)
-			}
+			cfg.AdjustedSeverityCategories[i].CompiledPatterns = append(cfg.AdjustedSeverityCategories[i].CompiledPatterns, compiled)
 		}
 	}
+	return nil
+}
+
+// ErrNoRules is returned by Ready when no rule was loaded, e.g. every rule is below the display levels
+var ErrNoRules = errors.New("no rules loaded")
+
+// Ready reports whether the rules were loaded, a rule file which failed to load or no rule at all leaves the scans incomplete
+func Ready() error {
+	if rulesLoadErr != nil {
+		return rulesLoadErr
+	}
+	if len(CombinedRules) == 0 {
+		return ErrNoRules
+	}
+	return nil
 }
 
 // loadRuleConfigs loads the rules from the JSON config file, compiles the rules and defines the search area
 func loadRuleConfigs(cfg cfgreader.EarlybirdConfig, moduleName, fileName string) []Rule {
-	var rules, tmpRules Rules
 	rulePath := path.Join(cfg.RulesConfigDir, fileName)
 
-	err := cfgreader.LoadConfig(&tmpRules, rulePath)
+	tmpRules, err := loadRuleFile(rulePath)
 	if err != nil {
 		log.Println("Failed to load rules file", err)
+		rulesLoadErr = errors.Join(rulesLoadErr, fmt.Errorf("module %s: %w", moduleName, err))
 	}
 
+	rules, err := compileRules(cfg, moduleName, rulePath, tmpRules)
+	if err != nil {
+		// The patterns are validated before the rules are loaded, the module is left out like a file which failed to load
+		log.Println("Failed to compile rules", err)
+		rulesLoadErr = errors.Join(rulesLoadErr, fmt.Errorf("module %s: %w", moduleName, err))
+	}
+	return rules
+}
+
+// loadRuleFile reads a rules file, parsing it as YAML or JSON based on its extension
+func loadRuleFile(rulePath string) (rules Rules, err error) {
+	data, err := os.ReadFile(rulePath)
+	if err != nil {
+		return rules, err
+	}
+	switch strings.ToLower(filepath.Ext(rulePath)) {
+	case ".yaml", ".yml":
+		if data, err = yaml.YAMLToJSON(data); err != nil {
+			return rules, &cfgreader.ConfigError{Kind: cfgreader.ErrConfigParse, File: rulePath, Err: err}
+		}
+	case ".json":
+		// JSON is unmarshalled as is
+	default:
+		// Rule files without a known extension are loaded like the other config files
+		err = cfgreader.LoadConfig(&rules, rulePath)
+		return rules, err
+	}
+	if err = json.Unmarshal(data, &rules); err != nil {
+		return rules, &cfgreader.ConfigError{Kind: cfgreader.ErrConfigParse, File: rulePath, Err: err}
+	}
+	return rules, nil
+}
+
+// loadCustomRules loads the rule files of the custom rules directory, each file being a module named after the file
+func loadCustomRules(cfg cfgreader.EarlybirdConfig) (rules []Rule, err error) {
+	rulePaths, err := customRuleFiles(cfg.CustomRulesDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, rulePath := range rulePaths {
+		tmpRules, err := loadRuleFile(rulePath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load rules file %s: %w", rulePath, err)
+		}
+		moduleName := strings.TrimSuffix(filepath.Base(rulePath), filepath.Ext(rulePath))
+		moduleRules, err := compileRules(cfg, moduleName, rulePath, tmpRules)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, moduleRules...)
+	}
+	return rules, nil
+}
+
+// customRuleFiles lists the JSON and YAML rule files of the custom rules directory
+func customRuleFiles(dir string) (rulePaths []string, err error) {
+	if dir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, &cfgreader.ConfigError{Kind: cfgreader.ErrMissingRuleDir, File: dir, Err: err}
+	}
+	for _, entry := range entries {
+		switch strings.ToLower(filepath.Ext(entry.Name())) {
+		case ".json", ".yaml", ".yml":
+			if !entry.IsDir() {
+				rulePaths = append(rulePaths, filepath.Join(dir, entry.Name()))
+			}
+		}
+	}
+	return rulePaths, nil
+}
+
+// mergeRules adds the custom rules to the built-in rules, a custom rule replaces the built-in rule with the same code
+func mergeRules(builtIn, custom []Rule) []Rule {
+	codeIndex := make(map[int]int, len(builtIn))
+	for i, rule := range builtIn {
+		codeIndex[rule.Code] = i
+	}
+	for _, rule := range custom {
+		if i, ok := codeIndex[rule.Code]; ok {
+			log.Println("Warning: custom rule", rule.Code, "overrides the built-in rule", builtIn[i].Caption)
+			builtIn[i] = rule
+			continue
+		}
+		codeIndex[rule.Code] = len(builtIn)
+		builtIn = append(builtIn, rule)
+	}
+	return builtIn
+}
+
+// compileRules compiles the enabled rules of a rules file which are displayed at the configured levels
+func compileRules(cfg cfgreader.EarlybirdConfig, moduleName, rulePath string, tmpRules Rules) ([]Rule, error) {
+	var rules Rules
 	for i := range tmpRules.Rules {
-		if customRules, ok := cfg.ModuleConfigs.Modules[moduleName]; ok && tmpRules.Rules[i].Severity <= customRules.DisplaySeverityLevel && tmpRules.Rules[i].Confidence <= customRules.DisplayConfidenceLevel {
-			tmpRules.Rules[i].Searcharea = tmpRules.Searcharea
-			tmpRules.Rules[i].CompiledPattern = regexp.MustCompile(tmpRules.Rules[i].Pattern)
-			rules.Rules = append(rules.Rules, tmpRules.Rules[i])
-		} else if tmpRules.Rules[i].Severity <= cfg.SeverityDisplayLevel && tmpRules.Rules[i].Confidence <= cfg.ConfidenceDisplayLevel {
+		// Disabled rules aren't compiled
+		if !ruleCodeEnabled(cfg, tmpRules.Rules[i].Code) || !ruleCategoryEnabled(cfg, tmpRules.Rules[i].Category) {
+			continue
+		}
+		// The levels of the rules are those of the default scale, a custom scale with fewer levels reports the levels
+		// beyond it at its lowest level
+		tmpRules.Rules[i].Severity = clampLevel(tmpRules.Rules[i].Severity, cfg.LevelMap)
+		tmpRules.Rules[i].Confidence = clampLevel(tmpRules.Rules[i].Confidence, cfg.LevelMap)
+		if severity, ok := cfg.SeverityOverrides[tmpRules.Rules[i].Code]; ok {
+			tmpRules.Rules[i].Severity = severity
+		}
+		customRules, ok := cfg.ModuleConfigs.Modules[moduleName]
+		if (ok && tmpRules.Rules[i].Severity <= customRules.DisplaySeverityLevel && tmpRules.Rules[i].Confidence <= customRules.DisplayConfidenceLevel) ||
+			(tmpRules.Rules[i].Severity <= cfg.SeverityDisplayLevel && tmpRules.Rules[i].Confidence <= cfg.ConfidenceDisplayLevel) {
+			compiled, err := regexp.Compile(tmpRules.Rules[i].Pattern)
+			if err != nil {
+				return nil, ruleError(tmpRules.Rules[i].Code, rulePath, "", err)
+			}
+			if tmpRules.Rules[i].Allowlist != "" {
+				if tmpRules.Rules[i].CompiledAllowlist, err = regexp.Compile(tmpRules.Rules[i].Allowlist); err != nil {
+					return nil, ruleError(tmpRules.Rules[i].Code, rulePath, "allowlist", err)
+				}
+			}
+			if tmpRules.Rules[i].Glob != "" {
+				glob, err := wildcard.Compile(tmpRules.Rules[i].Glob)
+				if err != nil {
+					return nil, ruleError(tmpRules.Rules[i].Code, rulePath, "glob", err)
+				}
+				tmpRules.Rules[i].CompiledGlob = &glob
+			}
 			tmpRules.Rules[i].Searcharea = tmpRules.Searcharea
-			tmpRules.Rules[i].CompiledPattern = regexp.MustCompile(tmpRules.Rules[i].Pattern)
+			if tmpRules.Rules[i].SearchEntireLine == nil {
+				tmpRules.Rules[i].SearchEntireLine = tmpRules.SearchEntireLine
+			}
+			tmpRules.Rules[i].CompiledPattern = compiled
 			rules.Rules = append(rules.Rules, tmpRules.Rules[i])
 		}
 	}
-	return rules.Rules
+	return rules.Rules, nil
+}
+
+// ruleCodeEnabled applies the rule codes enabled and disabled from the CLI, disabling a rule takes precedence over enabling it
+func ruleCodeEnabled(cfg cfgreader.EarlybirdConfig, code int) bool {
+	for _, disabled := range cfg.DisabledRuleCodes {
+		if code == disabled {
+			return false
+		}
+	}
+	if len(cfg.EnabledRuleCodes) == 0 {
+		return true
+	}
+	for _, enabled := range cfg.EnabledRuleCodes {
+		if code == enabled {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleCategoryEnabled applies the rule categories included and excluded from the CLI, excluding a category takes precedence
+// over including it.  The categories are compared case-insensitively.
+func ruleCategoryEnabled(cfg cfgreader.EarlybirdConfig, category string) bool {
+	for _, excluded := range cfg.ExcludeCategories {
+		if strings.EqualFold(category, excluded) {
+			return false
+		}
+	}
+	if len(cfg.IncludeCategories) == 0 {
+		return true
+	}
+	for _, included := range cfg.IncludeCategories {
+		if strings.EqualFold(category, included) {
+			return true
+		}
+	}
+	return false
+}
+
+// unknownCategories returns the rule categories included or excluded from the CLI which aren't the category of any rule
+// of the enabled modules
+func unknownCategories(cfg cfgreader.EarlybirdConfig) (unknown []string) {
+	if len(cfg.IncludeCategories) == 0 && len(cfg.ExcludeCategories) == 0 {
+		return nil
+	}
+	known := make(map[string]bool)
+	for _, rulePath := range ruleFilePaths(cfg) {
+		rules, err := loadRuleFile(rulePath)
+		if err != nil {
+			continue
+		}
+		for _, rule := range rules.Rules {
+			known[strings.ToLower(rule.Category)] = true
+		}
+	}
+	for _, category := range append(append([]string{}, cfg.IncludeCategories...), cfg.ExcludeCategories...) {
+		if !known[strings.ToLower(category)] {
+			unknown = append(unknown, category)
+		}
+	}
+	return unknown
+}
+
+// unknownRuleCodes returns the rule codes enabled or disabled from the CLI which aren't in the rules of the enabled modules
+func unknownRuleCodes(cfg cfgreader.EarlybirdConfig) (unknown []int) {
+	if len(cfg.EnabledRuleCodes) == 0 && len(cfg.DisabledRuleCodes) == 0 {
+		return nil
+	}
+	known := make(map[int]bool)
+	for _, rulePath := range ruleFilePaths(cfg) {
+		rules, err := loadRuleFile(rulePath)
+		if err != nil {
+			continue
+		}
+		for _, rule := range rules.Rules {
+			known[rule.Code] = true
+		}
+	}
+	for _, code := range append(append([]int{}, cfg.EnabledRuleCodes...), cfg.DisabledRuleCodes...) {
+		if !known[code] {
+			unknown = append(unknown, code)
+		}
+	}
+	return unknown
+}
+
+// ruleFilePaths lists the rule files of the enabled modules followed by the custom rule files
+func ruleFilePaths(cfg cfgreader.EarlybirdConfig) (rulePaths []string) {
+	for _, fileName := range cfg.EnabledModulesMap {
+		rulePaths = append(rulePaths, path.Join(cfg.RulesConfigDir, fileName))
+	}
+	sort.Strings(rulePaths)
+	customRulePaths, _ := customRuleFiles(cfg.CustomRulesDir)
+	return append(rulePaths, customRulePaths...)
+}
+
+// validateRules compiles the pattern of every rule in the rule files, returning all the invalid patterns as a single error
+func validateRules(rulePaths []string) error {
+	var errs []error
+	for _, rulePath := range rulePaths {
+		rules, err := loadRuleFile(rulePath)
+		if err != nil {
+			continue
+		}
+		for _, rule := range rules.Rules {
+			if _, err := regexp.Compile(rule.Pattern); err != nil {
+				errs = append(errs, ruleError(rule.Code, rulePath, "", err))
+			}
+			if _, err := regexp.Compile(rule.Allowlist); err != nil {
+				errs = append(errs, ruleError(rule.Code, rulePath, "allowlist", err))
+			}
+			if _, err := wildcard.Compile(rule.Glob); err != nil {
+				errs = append(errs, ruleError(rule.Code, rulePath, "glob", err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ruleError reports the field of a rule of the rule file which doesn't compile, the pattern when the field is empty
+func ruleError(code int, rulePath, field string, err error) error {
+	return &cfgreader.ConfigError{Kind: cfgreader.ErrRuleCompile, File: rulePath, Rule: code, Field: field, Err: err}
 }
 
 // loadLabelConfigs loads the labels from the config file
@@ -142,6 +441,9 @@ func loadLabelConfigs(dirPath string) (LabelConfigRules map[int]LabelConfigs, er
 	LabelConfigRules = make(map[int]LabelConfigs)
 
 	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
 		if info.IsDir() {
 			return nil
 		}
@@ -149,7 +451,7 @@ func loadLabelConfigs(dirPath string) (LabelConfigRules map[int]LabelConfigs, er
 		var tmpRules LabelConfigs
 		err = cfgreader.LoadConfig(&tmpRules, path)
 		if err != nil {
-			log.Fatal("Failed to load labels file", err)
+			return fmt.Errorf("failed to load labels file %s: %w", path, err)
 		}
 
 		for i := range tmpRules.Labels {
@@ -171,6 +473,9 @@ func loadFalsePositives(dirPath string) (FalsePositiveRules map[int]FalsePositiv
 	FalsePositiveRules = make(map[int]FalsePositives)
 
 	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
 		if info.IsDir() {
 			return nil
 		}
@@ -178,11 +483,13 @@ func loadFalsePositives(dirPath string) (FalsePositiveRules map[int]FalsePositiv
 		var tmpRules FalsePositives
 		err = cfgreader.LoadConfig(&tmpRules, path)
 		if err != nil {
-			log.Fatal("Failed to load false positives file ", path, err)
+			return fmt.Errorf("failed to load false positives file %s: %w", path, err)
 		}
 
 		for i := range tmpRules.FalsePositives {
-			tmpRules.FalsePositives[i].CompiledPattern = regexp.MustCompile(tmpRules.FalsePositives[i].Pattern)
+			if tmpRules.FalsePositives[i].CompiledPattern, err = regexp.Compile(tmpRules.FalsePositives[i].Pattern); err != nil {
+				return fmt.Errorf("invalid false positive pattern in %s: %w", path, err)
+			}
 			for _, code := range tmpRules.FalsePositives[i].Codes {
 				var AppendedFalsePositives FalsePositives
 				AppendedFalsePositives.FalsePositives = append(FalsePositiveRules[code].FalsePositives, tmpRules.FalsePositives[i])
@@ -201,6 +508,9 @@ func loadSolutions(dirPath string) (solutionConfigs map[int]Solution, err error)
 	solutionConfigs = make(map[int]Solution)
 
 	err = filepath.Walk(dirPath, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
 		if info.IsDir() {
 			return nil
 		}
@@ -208,7 +518,7 @@ func loadSolutions(dirPath string) (solutionConfigs map[int]Solution, err error)
 		var tmp Solutions
 		err = cfgreader.LoadConfig(&tmp, path)
 		if err != nil {
-			log.Fatal("Failed to load solutions file", err)
+			return fmt.Errorf("failed to load solutions file %s: %w", path, err)
 		}
 		for i := range tmp.Solutions {
 			solutionConfigs[tmp.Solutions[i].ID] = tmp.Solutions[i]
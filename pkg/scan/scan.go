@@ -18,13 +18,17 @@ package scan
 
 import (
 	"bufio"
+	"context"
 	"crypto/sha1"
 	"io"
 	"io/fs"
 	"log"
+	"math"
 	"os"
 	"path/filepath"
 	"regexp"
+	"runtime"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -32,6 +36,7 @@ import (
 
 	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
 	"github.com/americanexpress/earlybird/v4/pkg/postprocess"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
 )
 
 var (
@@ -48,76 +53,249 @@ var (
 	//ConvertPattern is a pattern used to identify files that need to be converted to plaintext to be scanned
 	ConvertPattern = regexp.MustCompile(convertRegex)
 	tempPattern    = regexp.MustCompile(tempRegex)
+	//rulesLoadErr records the rule files which failed to load, the scans go on without their rules
+	rulesLoadErr error
 )
 
 // SearchFiles will use the EarlybirdConfig, the provided file list, decompressed zip files and converted files temporary paths to send found secrets to the Hit channel
+// The files whose content is skipped, e.g. binary files, are marked with the reason once the Hit channel is closed.
 func SearchFiles(cfg *cfgReader.EarlybirdConfig, files []File, compressPaths []string, convertPaths []string, hits chan<- Hit) {
+	SearchFilesContext(context.Background(), cfg, files, compressPaths, convertPaths, hits)
+}
+
+// SearchFilesContext is SearchFiles stopping once the context is cancelled: the files being scanned stop at their current
+// line and the files left aren't scanned, they are marked as skipped.  The findings so far are still sent.
+func SearchFilesContext(ctx context.Context, cfg *cfgReader.EarlybirdConfig, files []File, compressPaths []string, convertPaths []string, hits chan<- Hit) {
 	//Delete tmp file directory when we're done
 	defer DeleteFiles(compressPaths)
 	defer DeleteFiles(convertPaths)
 	defer close(hits)
 
-	//Create our channels and mutex
-	var jobMutex = &sync.Mutex{}
-	jobs := make(chan WorkJob)
+	//Scan the file names, the scanners of the search areas without rules are skipped, e.g. when only the filename module is enabled
+	if hasRules("body") {
+		nameScanner(cfg, files, hits)
+	}
+	if !hasRules("filename") {
+		return
+	}
+
+	//Reuse the findings of the files unchanged since the cached scans
+	cache, err := openCache(cfg)
+	if err != nil {
+		log.Println("Failed to open the cache file, scanning without it", err)
+	}
+	defer cache.close()
+
+	//Create our channels
+	jobs := make(chan int)
+	results := make(chan fileResult)
 	wg := new(sync.WaitGroup)
 
 	//Create our worker pool
-	scanPool(cfg, wg, jobMutex, jobs, hits)
+	scanPool(ctx, cfg, wg, files, cache, jobs, results)
+
+	//Dispatch the files to the scanPool by index
+	go func() {
+		dispatched := 0
+	dispatch:
+		for ; dispatched < len(files) && ctx.Err() == nil; dispatched++ {
+			select {
+			case jobs <- dispatched:
+			case <-ctx.Done():
+				break dispatch
+			}
+		}
+		//Close our channels
+		close(jobs)
+		wg.Wait()
+		for i := dispatched; i < len(files); i++ {
+			files[i].Skipped = skipCanceled
+		}
+		close(results)
+	}()
+
+	var scanProgress *progress
+	if cfg.Progress && !cfg.Quiet {
+		scanProgress = newProgress(progressOutput, len(files))
+	}
+	collectHits(cfg, results, hits, scanProgress)
+}
+
+// hasRules reports if one of the rules applies to another search area than skippedArea: hasRules("body") reports if
+// one of them matches the file names, and hasRules("filename") if one of them matches the file contents
+func hasRules(skippedArea string) bool {
+	for i := range CombinedRules {
+		if CombinedRules[i].Searcharea != skippedArea {
+			return true
+		}
+	}
+	return false
+}
 
-	//Scan the file names
-	nameScanner(cfg, files, hits)
+// fileResult holds the findings of a single file, by the index of the file in the scan
+type fileResult struct {
+	index int
+	hits  []Hit
+	// warning is reported when the file timed out
+	warning *Hit
+	// skipped is the reason the content of the file wasn't scanned
+	skipped string
+	// canceled is set when the scan was cancelled before the end of the file
+	canceled bool
+}
 
-	//Create work from file content for the scanPool
-	contentJobWriter(cfg, files, jobs)
+// workerCount is the number of files scanned in parallel, defaulting to the number of CPUs
+func workerCount(cfg *cfgReader.EarlybirdConfig) int {
+	if cfg.WorkerCount < 1 {
+		return runtime.NumCPU()
+	}
+	return cfg.WorkerCount
+}
 
-	//Close our channels
-	close(jobs)
-	wg.Wait()
+// scanPool scans the files of incoming jobs for secrets and writes the findings of each file to the results channel, the
+// findings of the files unchanged since they were cached are reused
+func scanPool(ctx context.Context, cfg *cfgReader.EarlybirdConfig, wg *sync.WaitGroup, files []File, cache *resultCache, jobs <-chan int, results chan<- fileResult) {
+	for w := 1; w <= workerCount(cfg); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result := cachedScanFile(ctx, cfg, cache, files[i])
+				result.index = i
+				files[i].Skipped = result.skipped
+				results <- result
+			}
+		}()
+	}
 }
 
-// scanPool searches incoming jobs for secrets and write findings to hits channel
-func scanPool(cfg *cfgReader.EarlybirdConfig, wg *sync.WaitGroup, jobMutex *sync.Mutex, jobs chan WorkJob, hits chan<- Hit) {
+// collectHits writes the findings to the hits channel in the order of the files, regardless of which worker finished first,
+// so the report is the same for any number of workers.  The scanned files are counted by the progress if it's reported.
+func collectHits(cfg *cfgReader.EarlybirdConfig, results <-chan fileResult, hits chan<- Hit, scanProgress *progress) {
 	//Create duplicate map
 	dupeMap := make(map[string]bool) //HASH:true
-	for w := 1; w <= cfg.WorkerCount; w++ {
-		wg.Add(1)
-		go func(w int) {
-			for j := range jobs {
-				if IsIgnoreAnnotation(cfg, j.WorkLine.LineValue) {
-					j.WorkLine.LineValue = ""
+	//Commit which first introduced each finding of the git history
+	firstCommits := make(map[string]string)
+	//Commit of each line of the files blamed
+	blame := make(blameCache)
+	//Results of files which finished before the files ahead of them
+	pending := make(map[int]fileResult)
+	next := 0
+	for result := range results {
+		scanProgress.fileDone()
+		pending[result.index] = result
+		for result, ok := pending[next]; ok; result, ok = pending[next] {
+			delete(pending, next)
+			next++
+			for _, hit := range result.hits {
+				hit.fingerprint = Fingerprint(hit, cfg.SearchDir)
+				if !confident(cfg, hit) || inBaseline(cfg, hit) || inAllowlist(cfg, hit) || !hitUnique(dupeMap, hit) || introducedEarlier(firstCommits, hit) {
+					continue
 				}
 
-				// Scan the line based on common password rules
-				hitFound, tmpHits := scanLine(j.WorkLine, j.FileLines, cfg)
-				if cfg.Suppress {
-					for i := range tmpHits {
-						tmpHits[i].MatchValue = maskValue(tmpHits[i].MatchValue)
-						tmpHits[i].LineValue = maskValue(tmpHits[i].LineValue)
+				if hit.ConfidenceID <= cfg.ConfidenceDisplayLevel {
+					if cfg.Blame && hit.Commit == nil && hit.Line > 0 {
+						hit.Commit = blame.commit(hit.Filename, hit.Line)
 					}
+					hits <- maskHit(cfg, hit) //Push hits to channel
 				}
-				if hitFound {
-					for _, hit := range tmpHits {
-						jobMutex.Lock() // put a mutex on it to avoid collisions/misses
-						if !hitUnique(dupeMap, hit) {
-							jobMutex.Unlock()
-							continue
-						}
-						jobMutex.Unlock()
-
-						if hit.ConfidenceID <= cfg.ConfidenceDisplayLevel {
-							hits <- hit //Push hits to channel
-						}
-
-						if !cfg.FailScan {
-							cfg.FailScan = determineScanFail(cfg, &hit)
-						}
-					}
+
+				if !cfg.FailScan && !hit.Suppressed {
+					cfg.FailScan = determineScanFail(cfg, &hit)
 				}
 			}
-			defer wg.Done()
-		}(w)
+			if result.warning != nil {
+				hits <- *result.warning
+			}
+		}
+	}
+}
+
+// cachedScanFile returns the cached result of the file, or scans it and caches its result
+func cachedScanFile(ctx context.Context, cfg *cfgReader.EarlybirdConfig, cache *resultCache, searchFile File) fileResult {
+	if cache.isCacheFile(searchFile) {
+		return fileResult{skipped: skipCache}
+	}
+	entry, result, ok := cache.lookup(cfg, searchFile)
+	if ok {
+		return result
+	}
+	result = scanFile(ctx, cfg, searchFile)
+	if err := cache.record(entry, result); err != nil {
+		log.Println("Failed to write the cache file", err)
+	}
+	return result
+}
+
+// scanFile searches the content of the file for secrets line by line, until the file times out or the scan is cancelled
+func scanFile(ctx context.Context, cfg *cfgReader.EarlybirdConfig, searchFile File) (result fileResult) {
+	work, skipped := fileJobs(cfg, searchFile)
+	result.skipped = skipped
+	if len(work) == 0 {
+		return result
+	}
+
+	//The timeout of the file starts once it's read
+	fileCtx, cancel := fileContext(ctx, cfg)
+	defer cancel()
+	//The lines of the last job are all the lines of the file
+	fileLines := work[len(work)-1].FileLines
+	ignored := ignoredRules(fileLines)
+	for _, j := range work {
+		for _, hit := range scanJob(fileCtx, cfg, j, fileLines, ignored) {
+			hit.Commit = searchFile.Commit
+			result.hits = append(result.hits, hit)
+		}
+		if ctx.Err() != nil {
+			// The scan was cancelled, the rest of the file isn't a timeout
+			result.canceled = true
+			break
+		}
+		if fileCtx.Err() != nil {
+			// The file took too long to scan, skip the rest of its lines
+			warning := timeoutHit(cfg, searchFile.Path)
+			result.warning = &warning
+			break
+		}
 	}
+	result.hits = collapsePEMBlocks(result.hits, fileLines)
+	return result
+}
+
+// scanJob searches a line for secrets, fileLines are the lines around it to add as context to its hits.  The hits of the
+// ignored rules are suppressed.
+func scanJob(ctx context.Context, cfg *cfgReader.EarlybirdConfig, j WorkJob, fileLines []Line, ignored map[int]bool) []Hit {
+	if IsIgnoreAnnotation(cfg, j.WorkLine.LineValue) {
+		j.WorkLine.LineValue = ""
+	}
+	// Lines suppressed with an inline comment are only scanned to list them in verbose mode
+	suppressed := isSuppressed(j.WorkLine, j.FileLines)
+	if suppressed && !cfg.VerboseEnabled {
+		return nil
+	}
+
+	// Scan the line based on common password rules
+	hitFound, tmpHits := scanLine(ctx, j.WorkLine, j.FileLines, cfg)
+	// The lines of the job end with the last line joined to the line scanned
+	end := j.WorkLine.LineNum
+	if n := len(j.FileLines); cfg.JoinContinuations && n > 0 {
+		end = j.FileLines[n-1].LineNum
+	}
+	for i := range tmpHits {
+		tmpHits[i].Suppressed = suppressed || ignored[tmpHits[i].Code]
+		// The context would show the secrets around the hit, so it isn't added when the secrets are suppressed
+		if cfg.ContextLines > 0 && !cfg.Suppress {
+			tmpHits[i].Context = hitContext(tmpHits[i], fileLines, cfg.ContextLines, end)
+		}
+	}
+	if !hitFound {
+		return nil
+	}
+	if len(ignored) > 0 && !cfg.VerboseEnabled {
+		// The hits of the rules ignored in the file are only listed in verbose mode, like the suppressed lines
+		tmpHits = slices.DeleteFunc(tmpHits, func(hit Hit) bool { return hit.Suppressed })
+	}
+	return tmpHits
 }
 
 // determine if we should fail scan based on severity and confidence
@@ -125,64 +303,92 @@ func determineScanFail(cfg *cfgReader.EarlybirdConfig, hit *Hit) bool {
 	return hit.SeverityID <= cfg.SeverityFailLevel && hit.ConfidenceID <= cfg.ConfidenceFailLevel
 }
 
-// contentJobWriter creates work based off file content for scanning
-func contentJobWriter(cfg *cfgReader.EarlybirdConfig, files []File, jobs chan WorkJob) {
-	var e error
-	// Loop through each File
-	for _, searchFile := range files {
-		//FileOS refers to the file object that's open, not the file object which contains the name and path
-		if searchFile.Path == "buffer" || searchFile.Name == "buffer" {
-			for _, workline := range searchFile.Lines {
-				jobs <- WorkJob{
-					WorkLine:  workline,
-					FileLines: searchFile.Lines,
-				}
+// fileJobs creates work based off file content for scanning, or returns the reason the content of the file is skipped
+func fileJobs(cfg *cfgReader.EarlybirdConfig, searchFile File) (work []WorkJob, skipped string) {
+	//FileOS refers to the file object that's open, not the file object which contains the name and path
+	if searchFile.Path == "buffer" || searchFile.Name == "buffer" {
+		if cfg.JoinContinuations {
+			return continuationJobs(searchFile.Lines, searchFile.Name, cfg.WorkLength), ""
+		}
+		for _, workline := range searchFile.Lines {
+			work = append(work, WorkJob{
+				WorkLine:  workline,
+				FileLines: searchFile.Lines,
+			})
+		}
+		return work, ""
+	}
+
+	//Don't do file read/scan on files we know will trigger the filename scan -- Don't open compressed files either
+	if isExcludedFileType(cfg, searchFile.Name) || len(CompressPattern.FindStringSubmatch(searchFile.Name)) > 0 {
+		return nil, ""
+	}
+	var fileOS io.ReadCloser
+	if searchFile.Open != nil {
+		//Stream the content of files which aren't on disk, e.g. archive entries
+		var err error
+		if fileOS, err = searchFile.Open(); err != nil {
+			log.Println("Can't open file", searchFile.Path, err)
+			return nil, skipUnreadable
+		}
+	} else {
+		fileInfo, err := os.Lstat(searchFile.Path)
+		if err == nil && fileInfo != nil && fileInfo.Mode()&fs.ModeSymlink != 0 {
+			// Only symlinks to regular files are scanned, the file walker makes sure each of them is scanned once
+			if target, err := os.Stat(searchFile.Path); err != nil || !target.Mode().IsRegular() {
+				return nil, ""
 			}
-		} else {
-			//Don't do file read/scan on files we know will trigger the filename scan -- Don't open compressed files either
-			if !isExcludedFileType(cfg, searchFile.Name) && len(CompressPattern.FindStringSubmatch(searchFile.Name)) <= 0 {
-				fileInfo, err := os.Lstat(searchFile.Path)
-				if err == nil && fileInfo != nil && fileInfo.Mode()&fs.ModeSymlink != 0 {
-					continue
-				}
+		}
 
-				fileOS, err := os.Open(searchFile.Path) //Open file path
-				if err != nil {
-					fileOS, err = os.Open(searchFile.Name) //If file path open fails, try file name
-					if err != nil {
-						log.Fatal("Can't open file", err)
-					}
-				}
-				defer fileOS.Close()
-
-				var work []WorkJob
-				var job WorkJob
-				job.FileLines = searchFile.Lines
-
-				//Search line by line
-				reader := bufio.NewReader(fileOS)
-				job.WorkLine.LineValue, e = readln(reader)
-				for e == nil {
-					job.WorkLine.LineNum = job.WorkLine.LineNum + 1
-					job.WorkLine.FileName = jobFileName(cfg.Gitrepo, searchFile.Name)
-					job.WorkLine.FilePath = searchFile.Path
-					job.FileLines = append(job.FileLines, job.WorkLine)
-
-					//Add our split up jobs to the work array
-					work = append(work, splitJob(job, cfg.WorkLength)...)
-					//Search next line to break out of loop
-					job.WorkLine.LineValue, e = readln(reader)
-					if e != nil && e != io.EOF {
-						log.Println("Error reading file:", e)
-					}
-				}
-				//Push our work to the jobs channel
-				for _, job := range work {
-					jobs <- job
-				}
+		fileOS, err = os.Open(searchFile.Path) //Open file path
+		if err != nil {
+			fileOS, err = os.Open(searchFile.Name) //If file path open fails, try file name
+			if err != nil {
+				log.Println("Can't open file", searchFile.Path, err)
+				return nil, skipUnreadable
 			}
 		}
 	}
+	defer fileOS.Close()
+
+	var (
+		job WorkJob
+		e   error
+	)
+	job.FileLines = searchFile.Lines
+
+	//Search line by line, decoding UTF-16 files to UTF-8 first
+	reader := decodeBOM(fileOS)
+	if sample, _ := reader.Peek(binarySampleLength); isBinary(sample, sniffThreshold(cfg)) && !isBinaryScanned(cfg, searchFile.Name) {
+		if cfg.VerboseEnabled {
+			utils.InfoLog.Println("Ignoring", searchFile.Path, ". File is binary.")
+		}
+		return nil, skipBinary
+	}
+	fileName := jobFileName(cfg.Gitrepo, searchFile.Name)
+	job.WorkLine.LineValue, e = readln(reader)
+	for e == nil {
+		job.WorkLine.LineNum = job.WorkLine.LineNum + 1
+		job.WorkLine.FileName = fileName
+		job.WorkLine.FilePath = searchFile.Path
+		job.FileLines = append(job.FileLines, job.WorkLine)
+
+		//Add our split up jobs to the work array, the continued lines are joined once the whole file is read
+		if !cfg.JoinContinuations {
+			work = appendJob(work, job, cfg.WorkLength)
+		}
+		//Search next line to break out of loop
+		job.WorkLine.LineValue, e = readln(reader)
+		if e != nil && e != io.EOF {
+			log.Println("Error reading file:", e)
+			// The lines read so far are scanned, the file is still counted as skipped as the rest of it wasn't
+			skipped = skipUnreadable
+		}
+	}
+	if cfg.JoinContinuations {
+		work = continuationJobs(job.FileLines, searchFile.Name, cfg.WorkLength)
+	}
+	return work, skipped
 }
 
 // nameScanner scans file names for sensitive values
@@ -190,7 +396,8 @@ func nameScanner(cfg *cfgReader.EarlybirdConfig, files []File, hits chan<- Hit)
 	for _, file := range files {
 		// Scan the filename based on the Filename rules
 		hitFound, hit := scanName(file, CombinedRules, cfg)
-		if hitFound {
+		hit.fingerprint = Fingerprint(hit, cfg.SearchDir)
+		if hitFound && confident(cfg, hit) && !inBaseline(cfg, hit) && !inAllowlist(cfg, hit) {
 
 			hits <- hit //push hit to channel
 
@@ -203,6 +410,12 @@ func nameScanner(cfg *cfgReader.EarlybirdConfig, files []File, hits chan<- Hit)
 
 }
 
+// confident determines if the confidence of a finding reaches the minimum confidence, the findings below it are dropped
+// before reaching the writers and don't fail the scan
+func confident(cfg *cfgReader.EarlybirdConfig, hit Hit) bool {
+	return cfg.MinConfidence == 0 || hit.ConfidenceID <= cfg.MinConfidence
+}
+
 // DeleteFiles removes files and folders in target path array
 func DeleteFiles(paths []string) {
 	for _, p := range paths {
@@ -245,18 +458,38 @@ func hitUnique(dupeMap map[string]bool, hit Hit) bool {
 	return true
 }
 
+// introducedEarlier determines if a finding of the git history persists from an earlier commit, the files being scanned
+// from the oldest commit the finding is only reported for the commit which introduced it
+func introducedEarlier(firstCommits map[string]string, hit Hit) bool {
+	if hit.Commit == nil {
+		return false
+	}
+	key := hit.Filename + strconv.Itoa(hit.Code) + hit.MatchValue
+	if hash, ok := firstCommits[key]; ok {
+		return hash != hit.Commit.Hash
+	}
+	firstCommits[key] = hit.Commit.Hash
+	return false
+}
+
 // Take a line and run through the rules, looking for a hit
-func scanLine(line Line, fileLines []Line, cfg *cfgReader.EarlybirdConfig) (isHit bool, hits []Hit) {
-	for _, rule := range CombinedRules {
+func scanLine(ctx context.Context, line Line, fileLines []Line, cfg *cfgReader.EarlybirdConfig) (isHit bool, hits []Hit) {
+	for i := range CombinedRules {
+		// The rules are large, take them by reference rather than copying each of them for every line
+		rule := &CombinedRules[i]
+		// Stop matching rules once the file has timed out
+		if ctx.Err() != nil {
+			break
+		}
 		var hit Hit
 		//Skip rules that do not apply
 		if rule.Searcharea == "filename" || cfg.SkipComments && rule.Category == "comment" {
 			continue
 		}
 
-		patternMatch, matchValue := findHit(line.LineValue, rule.CompiledPattern)
+		patternMatch, matchValue := rule.find(line.LineValue)
 
-		if !patternMatch {
+		if !patternMatch || rule.allowlisted(matchValue) {
 			continue
 		}
 
@@ -279,16 +512,18 @@ func scanLine(line Line, fileLines []Line, cfg *cfgReader.EarlybirdConfig) (isHi
 			hit.Filename = line.FileName
 		}
 		hit.Time = time.Now().UTC().Format(time.RFC3339)
-		hit.determineSeverity(cfg, &rule)
+		hit.determineSeverity(cfg, rule)
 
 		// Apply labels to the hit if appropriate
 		labelHit(&hit, fileLines)
 
 		//Check if our hit has any false positives
-		isStillHit := hit.postProcess(cfg, &rule)
-		if isStillHit {
+		isStillHit := hit.postProcess(cfg, rule)
+		if isStillHit && hit.limitMatchLength(cfg, rule) {
+			hit.adjustConfidence(cfg)
 			isHit = true
 			hits = append(hits, hit)
+			logHit(cfg, hit, line)
 		}
 
 	}
@@ -306,10 +541,13 @@ func scanName(file File, rules []Rule, cfg *cfgReader.EarlybirdConfig) (isHit bo
 			file.Path = file.Name
 		}
 
-		patternMatch, _ := findHit(file.Path, rule.CompiledPattern)
+		patternMatch, matchValue := findHit(file.Path, rule.CompiledPattern)
+		if rule.CompiledGlob != nil {
+			patternMatch, matchValue = rule.CompiledGlob.Match(filepath.ToSlash(file.Path)), file.Name
+		}
 
 		// If we found a match to the Regexp pattern, build a Hit
-		if patternMatch {
+		if patternMatch && !rule.allowlisted(matchValue) {
 			hit.Code = rule.Code
 			hit.Severity = getLevelNameFromID(rule.Severity, cfg.LevelMap)
 			hit.SeverityID = rule.Severity
@@ -348,11 +586,12 @@ func scanName(file File, rules []Rule, cfg *cfgReader.EarlybirdConfig) (isHit bo
 // An error is returned iff there is an error with the
 // buffered reader.
 func readln(r *bufio.Reader) (string, error) {
-	var (
-		isPrefix bool  = true
-		err      error = nil
-		line, ln []byte
-	)
+	line, isPrefix, err := r.ReadLine()
+	if !isPrefix || err != nil {
+		// Most lines fit in the reader's buffer, convert them without going through an intermediate copy
+		return string(line), err
+	}
+	ln := append([]byte(nil), line...)
 	for isPrefix && err == nil {
 		line, isPrefix, err = r.ReadLine()
 		ln = append(ln, line...)
@@ -384,9 +623,14 @@ func jobFileName(gitRepo, fileName string) string {
 
 // splitJob splits up the job into an array of jobs if too long otherwise returns a single job
 func splitJob(inJob WorkJob, worklength int) (work []WorkJob) {
+	return appendJob(nil, inJob, worklength)
+}
+
+// appendJob appends the job to work, split up if too long, without allocating an array for each line
+func appendJob(work []WorkJob, inJob WorkJob, worklength int) []WorkJob {
 	//If line isn't too long, just push job to work channel
 	if len(inJob.WorkLine.LineValue) <= worklength {
-		return []WorkJob{inJob}
+		return append(work, inJob)
 	}
 
 	//For VERY long lines, split it up at WORK_LENGTH, creating another string that overlaps the split
@@ -508,7 +752,7 @@ func (hit *Hit) determineSeverity(cfg *cfgReader.EarlybirdConfig, rule *Rule) {
 					test = hit.MatchValue
 				}
 
-				if p.Match([]byte(test)) {
+				if p.MatchString(test) {
 					severityId := getIdFromLevelName(adjustedSeverityCategoryCfg.AdjustedDisplaySeverity, cfg.LevelMap)
 
 					hit.Severity = getLevelNameFromID(severityId, cfg.LevelMap)
@@ -621,8 +865,12 @@ func (hit *Hit) postProcess(cfg *cfgReader.EarlybirdConfig, rule *Rule) (isHit b
 
 		// Verify credit card hits against a mod10 check
 	case rule.Postprocess == "mod10":
-		// If the match passed a Luhn/mod-10 check, build a Hit
-		if postprocess.IsCard(hit.MatchValue) {
+		// If the match passed a Luhn/mod-10 check, build a Hit showing the last four digits of the card number only
+		if postprocess.IsPAN(hit.MatchValue) {
+			card := strings.TrimSpace(hit.MatchValue)
+			masked := postprocess.MaskPAN(card)
+			hit.LineValue = strings.ReplaceAll(hit.LineValue, card, masked)
+			hit.MatchValue = masked
 			isHit = true
 		}
 	case rule.Postprocess == "basicAuth":
@@ -638,6 +886,12 @@ func (hit *Hit) postProcess(cfg *cfgReader.EarlybirdConfig, rule *Rule) (isHit b
 			isHit = true
 		}
 
+		// Look for the base64 or hex token of the line with the highest entropy
+	case rule.Postprocess == "entropy-base64":
+		isHit = hit.highEntropyToken(postprocess.Base64Charset, cfg.EntropyBase64Threshold)
+	case rule.Postprocess == "entropy-hex":
+		isHit = hit.highEntropyToken(postprocess.HexCharset, cfg.EntropyHexThreshold)
+
 		// No additional validation needed
 	case rule.Postprocess == "key":
 		// Skip same key/value pair
@@ -646,12 +900,37 @@ func (hit *Hit) postProcess(cfg *cfgReader.EarlybirdConfig, rule *Rule) (isHit b
 			isHit = false
 			break
 		}
+
+		// The private key blocks are reported as a single hit once the whole file is scanned
+	case rule.Postprocess == "pem":
+		hit.pemBlock = true
+		isHit = true
+
+		// Run the post-processor registered under the name of the rule
+	case postProcessors[rule.Postprocess] != nil:
+		keep, confidence := postProcessors[rule.Postprocess](*hit)
+		if keep && confidence != 0 {
+			hit.Confidence = getLevelNameFromID(confidence, cfg.LevelMap)
+			hit.ConfidenceID = confidence
+		}
+		isHit = keep
 	default:
 		isHit = true
 	}
 	return isHit
 }
 
+// highEntropyToken reports the token of the line with the highest entropy as the match value, if its entropy exceeds the threshold
+func (hit *Hit) highEntropyToken(charset string, threshold float64) bool {
+	token, entropy := postprocess.HighEntropyToken(hit.LineValue, charset, entropyTokenLength)
+	if entropy <= threshold {
+		return false
+	}
+	hit.MatchValue = token
+	hit.Entropy = math.Round(entropy*100) / 100
+	return true
+}
+
 // removeTempPrefix removes the temp path prefix if it exists
 func removeTempPrefix(path string) string {
 	if strings.Contains(path, "ebzip") || strings.Contains(path, "ebgit") || strings.Contains(path, "ebconv") {
@@ -19,9 +19,8 @@ package scan
 import (
 	"regexp"
 	"strings"
-
-	"golang.org/x/text/language"
-	"golang.org/x/text/search"
+	"sync"
+	"unicode"
 )
 
 // Look for a regexp pattern hit in a string
@@ -35,9 +34,56 @@ func findHit(target string, CompiledPattern *regexp.Regexp) (isHit bool, retMatc
 	return false, ""
 }
 
+// find matches the pattern of the rule against the whole line, or against each token of the line for the rules which
+// don't search the entire line, returning the first match
+func (rule *Rule) find(line string) (isHit bool, retMatch string) {
+	if rule.SearchEntireLine == nil || *rule.SearchEntireLine {
+		return findHit(line, rule.CompiledPattern)
+	}
+	for _, token := range tokenize(line) {
+		if isHit, retMatch = findHit(token, rule.CompiledPattern); isHit {
+			return isHit, retMatch
+		}
+	}
+	return false, ""
+}
+
+// tokenize splits the line into tokens on the whitespace and the delimiters of tokenDelimiters
+func tokenize(line string) []string {
+	return strings.FieldsFunc(line, func(r rune) bool {
+		return unicode.IsSpace(r) || strings.ContainsRune(tokenDelimiters, r)
+	})
+}
+
+// allowlisted determines if the match value of a rule also matches its allowlist
+func (rule *Rule) allowlisted(matchValue string) bool {
+	return rule.CompiledAllowlist != nil && rule.CompiledAllowlist.MatchString(matchValue)
+}
+
+// labelKeys caches the case-insensitive patterns of the label keys, which are matched against every hit
+var labelKeys = struct {
+	sync.RWMutex
+	patterns map[string]*regexp.Regexp
+}{patterns: make(map[string]*regexp.Regexp)}
+
+// labelKeyPattern returns the compiled case-insensitive pattern of expr, compiling it on first use
+func labelKeyPattern(expr string) *regexp.Regexp {
+	labelKeys.RLock()
+	reg, ok := labelKeys.patterns[expr]
+	labelKeys.RUnlock()
+	if ok {
+		return reg
+	}
+	reg = regexp.MustCompile("(?i)" + expr)
+	labelKeys.Lock()
+	labelKeys.patterns[expr] = reg
+	labelKeys.Unlock()
+	return reg
+}
+
 // substringExistsInLines Search for a regexp pattern occurring anywhere in a file
 func substringExistsInLines(fileLines []Line, str string) bool {
-	reg := regexp.MustCompile("(?i)" + str)
+	reg := labelKeyPattern(str)
 	for _, line := range fileLines {
 		if reg.MatchString(line.LineValue) {
 			return true
@@ -46,11 +92,9 @@ func substringExistsInLines(fileLines []Line, str string) bool {
 	return false
 }
 
-// substringExistsInString check if sub exists in string
+// substringExistsInString check if sub exists in string, ignoring case
 func substringExistsInString(str string, substr string) bool {
-	m := search.New(language.English, search.IgnoreCase)
-	start, _ := m.IndexString(str, substr)
-	return start >= 0
+	return labelKeyPattern(regexp.QuoteMeta(substr)).MatchString(str)
 }
 
 // If it makes sense to strip leading/trailing characters for readability, let's do it
@@ -98,7 +142,7 @@ func findFalsePositive(hit Hit) (isFP bool) {
 			if len(rule.FileExtensions) > 0 { //Check if this rule only applies to certain files
 				scan = false
 				for _, fileExtension := range rule.FileExtensions { //Cycle through file extensions to verify rule applies to hit
-					if strings.HasSuffix(strings.ToLower(hit.Filename), strings.ToLower(fileExtension)) {
+					if hasSuffixFold(hit.Filename, fileExtension) {
 						scan = true //Trigger a value scan if the file name matches
 					}
 				}
@@ -117,6 +161,11 @@ func findFalsePositive(hit Hit) (isFP bool) {
 	return false
 }
 
+// hasSuffixFold tests whether s ends with suffix, ignoring case
+func hasSuffixFold(s, suffix string) bool {
+	return len(s) >= len(suffix) && strings.EqualFold(s[len(s)-len(suffix):], suffix)
+}
+
 // Translate the severity level from int value to string value
 func getLevelNameFromID(level int, levelMap map[string]int) string {
 	levelName := "low"
@@ -128,6 +177,38 @@ func getLevelNameFromID(level int, levelMap map[string]int) string {
 	return levelName
 }
 
+// levelBounds returns the IDs of the highest and lowest levels of the level map, the highest level has the lowest ID
+func levelBounds(levelMap map[string]int) (highest, lowest int) {
+	for _, id := range levelMap {
+		if highest == 0 || id < highest {
+			highest = id
+		}
+		if id > lowest {
+			lowest = id
+		}
+	}
+	return highest, lowest
+}
+
+// clampLevel brings a level ID beyond the levels of the level map to its highest or lowest level, e.g. the levels of the
+// default scale set by the rules when the config has a custom scale with fewer levels
+func clampLevel(level int, levelMap map[string]int) int {
+	if len(levelMap) == 0 {
+		return level
+	}
+	highest, lowest := levelBounds(levelMap)
+	return max(highest, min(level, lowest))
+}
+
+// lowestLevel returns the name and ID of the lowest level of the level map, info without levels
+func lowestLevel(levelMap map[string]int) (string, int) {
+	if len(levelMap) == 0 {
+		return infoLevelSeverity, 0
+	}
+	_, lowest := levelBounds(levelMap)
+	return getLevelNameFromID(lowest, levelMap), lowest
+}
+
 // Translate the display severity from string value to int value
 func getIdFromLevelName(displaySeverity string, levelMap map[string]int) int {
 	id := 1
@@ -17,6 +17,7 @@
 package scan
 
 import (
+	"reflect"
 	"regexp"
 	"testing"
 )
@@ -55,6 +56,76 @@ func Test_findHit(t *testing.T) {
 	}
 }
 
+func TestRule_find(t *testing.T) {
+	entireLine, tokens := true, false
+	// A 32 character hex token, anchored to match the whole token
+	hexToken := regexp.MustCompile(`^[0-9a-f]{32}$`)
+	// An assignment, spanning the tokens of the line
+	assignment := regexp.MustCompile(`password\s*=\s*\w{8,}`)
+	tests := []struct {
+		name             string
+		pattern          *regexp.Regexp
+		searchEntireLine *bool
+		line             string
+		wantIsHit        bool
+		wantMatch        string
+	}{
+		{
+			name:      "Token pattern searching the entire line by default",
+			pattern:   hexToken,
+			line:      `api_key = "0123456789abcdef0123456789abcdef"`,
+			wantIsHit: false,
+		},
+		{
+			name:             "Token pattern matching a token",
+			pattern:          hexToken,
+			searchEntireLine: &tokens,
+			line:             `api_key = "0123456789abcdef0123456789abcdef"`,
+			wantIsHit:        true,
+			wantMatch:        "0123456789abcdef0123456789abcdef",
+		},
+		{
+			name:             "Token pattern doesn't match a part of a longer token",
+			pattern:          hexToken,
+			searchEntireLine: &tokens,
+			line:             `commit 0123456789abcdef0123456789abcdef01234567`,
+			wantIsHit:        false,
+		},
+		{
+			name:             "Line pattern matching across the tokens",
+			pattern:          assignment,
+			searchEntireLine: &entireLine,
+			line:             `password = hunter2hunter2`,
+			wantIsHit:        true,
+			wantMatch:        "password = hunter2hunter2",
+		},
+		{
+			name:             "Line pattern can't match across the tokens",
+			pattern:          assignment,
+			searchEntireLine: &tokens,
+			line:             `password = hunter2hunter2`,
+			wantIsHit:        false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := Rule{CompiledPattern: tt.pattern, SearchEntireLine: tt.searchEntireLine}
+			isHit, match := rule.find(tt.line)
+			if isHit != tt.wantIsHit || match != tt.wantMatch {
+				t.Errorf("find() = %v, %q, want %v, %q", isHit, match, tt.wantIsHit, tt.wantMatch)
+			}
+		})
+	}
+}
+
+func Test_tokenize(t *testing.T) {
+	got := tokenize("export TOKEN='abc123'; curl -H \"Authorization: Bearer eyJ0\" https://api.example.com/v1 (id=[7])")
+	want := []string{"export", "TOKEN", "abc123", "curl", "-H", "Authorization", "Bearer", "eyJ0", "https", "//api.example.com/v1", "id", "7"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("tokenize() = %q, want %q", got, want)
+	}
+}
+
 func Test_substringExistsInLines(t *testing.T) {
 	type args struct {
 		fileLines []Line
@@ -0,0 +1,51 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+
+package scan
+
+import "sort"
+
+// SortHits sends the hits in a canonical order once the scan is done, by file name, line and rule code, so the reports
+// are the same from one scan to the next whatever the order the files were walked and scanned in
+func SortHits(hits <-chan Hit) chan Hit {
+	sorted := make(chan Hit)
+	go func() {
+		defer close(sorted)
+		var all []Hit
+		for hit := range hits {
+			all = append(all, hit)
+		}
+		sortHits(all)
+		for _, hit := range all {
+			sorted <- hit
+		}
+	}()
+	return sorted
+}
+
+// sortHits sorts the hits by file name, line and rule code, the hits which compare equal keep their order
+func sortHits(hits []Hit) {
+	sort.SliceStable(hits, func(i, j int) bool {
+		if hits[i].Filename != hits[j].Filename {
+			return hits[i].Filename < hits[j].Filename
+		}
+		if hits[i].Line != hits[j].Line {
+			return hits[i].Line < hits[j].Line
+		}
+		return hits[i].Code < hits[j].Code
+	})
+}
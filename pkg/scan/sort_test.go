@@ -0,0 +1,69 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+
+package scan
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+func TestSortHits(t *testing.T) {
+	want := []Hit{
+		{Filename: "/app/.env", Line: 0, Code: 4110},
+		{Filename: "/app/.env", Line: 2, Code: 3001},
+		{Filename: "/app/.env", Line: 2, Code: 3001, MatchValue: "second match on the line"},
+		{Filename: "/app/.env", Line: 2, Code: 3005},
+		{Filename: "/app/.env", Line: 10, Code: 3001},
+		{Filename: "/app/config/settings.py", Line: 1, Code: 3002},
+		{Filename: "/app/main.go", Line: 7, Code: 5002},
+	}
+	for seed := int64(1); seed <= 20; seed++ {
+		shuffled := append([]Hit(nil), want...)
+		rand.New(rand.NewSource(seed)).Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+		// The hits which compare equal keep their order
+		first, second := -1, -1
+		for i, hit := range shuffled {
+			if hit.Line == 2 && hit.Code == 3001 {
+				if first < 0 {
+					first = i
+				} else {
+					second = i
+				}
+			}
+		}
+		if shuffled[first].MatchValue != "" {
+			shuffled[first], shuffled[second] = shuffled[second], shuffled[first]
+		}
+
+		hits := make(chan Hit)
+		go func() {
+			defer close(hits)
+			for _, hit := range shuffled {
+				hits <- hit
+			}
+		}()
+		var got []Hit
+		for hit := range SortHits(hits) {
+			got = append(got, hit)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("SortHits() of seed %d = %v, want %v", seed, got, want)
+		}
+	}
+}
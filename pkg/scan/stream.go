@@ -0,0 +1,84 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"context"
+	"io"
+	"log"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+)
+
+// SearchStream searches the lines of the reader for secrets as they're read, so input like stdin of any size is scanned
+// without being buffered whole.  The lines are named after name in the hits.  Each line is scanned once the context lines
+// after it are read, and the rules see a window of the previous lines instead of the whole file, e.g. to label the hits.
+func SearchStream(cfg *cfgReader.EarlybirdConfig, name string, reader io.Reader, hits chan<- Hit) {
+	SearchStreamContext(context.Background(), cfg, name, reader, hits)
+}
+
+// SearchStreamContext is SearchStream stopping once the context is cancelled, the lines read after it aren't scanned
+func SearchStreamContext(ctx context.Context, cfg *cfgReader.EarlybirdConfig, name string, reader io.Reader, hits chan<- Hit) {
+	defer close(hits)
+	results := make(chan fileResult)
+	go func() {
+		defer close(results)
+		var (
+			window  []Line
+			next    int // Index in the window of the next line to scan
+			index   int
+			ignored map[int]bool
+		)
+		scanNext := func() {
+			result := fileResult{index: index}
+			for _, j := range splitJob(WorkJob{WorkLine: window[next], FileLines: window}, cfg.WorkLength) {
+				result.hits = append(result.hits, scanJob(ctx, cfg, j, window, ignored)...)
+			}
+			results <- result
+			index++
+			next++
+			// Drop the line which fell out of the window
+			if next > streamWindowLines {
+				window = window[1:]
+				next--
+			}
+		}
+
+		bufReader := decodeBOM(reader)
+		for lineNum := 1; ctx.Err() == nil; lineNum++ {
+			value, err := readln(bufReader)
+			if err != nil {
+				if err != io.EOF {
+					log.Println("Error reading stream:", err)
+				}
+				break
+			}
+			window = append(window, Line{LineNum: lineNum, LineValue: value, FileName: name, FilePath: name})
+			if lineNum <= ignoreRulesLines {
+				// The rules ignored at the top of the stream apply to the lines scanned after the comment is read
+				ignored = ignoredRules(window)
+			}
+			if len(window)-next > cfg.ContextLines {
+				scanNext()
+			}
+		}
+		for next < len(window) && ctx.Err() == nil {
+			scanNext()
+		}
+	}()
+	collectHits(cfg, results, hits, nil)
+}
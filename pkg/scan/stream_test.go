@@ -0,0 +1,101 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSearchStream(t *testing.T) {
+	var content strings.Builder
+	content.WriteString("name = app\n")
+	content.WriteString("password = \"SecretValue1673\"\n")
+	// More lines than the window, the findings after it keep their line numbers
+	for i := 0; i < streamWindowLines+50; i++ {
+		fmt.Fprintf(&content, "filler line %d\n", i)
+	}
+	content.WriteString("// earlybird:disable\n")
+	content.WriteString("password = \"SuppressedValue7890\"\n")
+	content.WriteString("password = \"LastValue4521\"")
+
+	streamCfg := cfg
+	streamCfg.ContextLines = 1
+	hits := make(chan Hit)
+	go SearchStream(&streamCfg, "secrets.env", strings.NewReader(content.String()), hits)
+
+	type finding struct {
+		Filename string
+		Line     int
+		Context  int
+	}
+	var got []finding
+	for hit := range hits {
+		if hit.Code != 3001 {
+			continue
+		}
+		got = append(got, finding{hit.Filename, hit.Line, len(hit.Context)})
+	}
+	lastLine := streamWindowLines + 55
+	want := []finding{
+		{"secrets.env", 2, 3},
+		{"secrets.env", lastLine, 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SearchStream() = %v, want %v", got, want)
+	}
+}
+
+// lineReader generates the lines of a long stream as they are read
+type lineReader struct {
+	lines, read int
+	pending     string
+}
+
+func (r *lineReader) Read(p []byte) (int, error) {
+	if r.pending == "" {
+		if r.read == r.lines {
+			return 0, io.EOF
+		}
+		r.read++
+		r.pending = fmt.Sprintf("value = %d\n", r.read)
+		if r.read == r.lines {
+			r.pending = "password = \"SecretValue1673\"\n"
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
+
+func TestSearchStreamLarge(t *testing.T) {
+	reader := &lineReader{lines: 20000}
+	hits := make(chan Hit)
+	go SearchStream(&cfg, "stdin", reader, hits)
+	var lines []int
+	for hit := range hits {
+		if hit.Code == 3001 {
+			lines = append(lines, hit.Line)
+		}
+	}
+	if !reflect.DeepEqual(lines, []int{reader.lines}) {
+		t.Errorf("SearchStream() hits on lines %v, want line %d", lines, reader.lines)
+	}
+}
@@ -17,13 +17,18 @@
 package scan
 
 import (
+	"io"
 	"regexp"
+
+	"github.com/americanexpress/earlybird/v4/pkg/wildcard"
 )
 
 // Rules is the exported definition of the Rules structure for Earlybird
 type Rules struct {
 	Rules      []Rule `json:"rules"`
 	Searcharea string `json:"Searcharea"`
+	// SearchEntireLine is the default of the rules of the module which don't set it
+	SearchEntireLine *bool `json:"SearchEntireLine"`
 }
 
 // Rule Each module config is a set of rules
@@ -34,6 +39,19 @@ type Rule struct {
 	Searcharea                                        string
 	CWE                                               []string
 	Example                                           string
+	// Allowlist drops the findings whose match value also matches it, e.g. placeholders like YOUR_API_KEY_HERE
+	Allowlist         string
+	CompiledAllowlist *regexp.Regexp
+	// Glob is a wildcard pattern matched case-insensitively against the file path instead of Pattern, e.g. *.keystore,
+	// for the rules of the filename search area
+	Glob         string
+	CompiledGlob *wildcard.Matcher
+	// SearchEntireLine matches Pattern against the whole line, the default, or when it's false against each token of the
+	// line, split on the whitespace and the delimiters of tokenDelimiters, e.g. to anchor a pattern to a whole token
+	SearchEntireLine *bool
+	// MaxMatchLength is the longest match value of the findings of the rule, in bytes, the default of the configuration
+	// applies when it's 0
+	MaxMatchLength int
 }
 
 // Hit is a match in a file against a specific rule
@@ -53,6 +71,44 @@ type Hit struct {
 	Labels       []string `json:"labels"`
 	CWE          []string `json:"cwe"`
 	Time         string   `json:"time"`
+	// Suppressed findings were disabled with an inline comment, they are only reported in verbose mode
+	Suppressed bool `json:"suppressed,omitempty" csv:"-"`
+	// Locations lists every occurrence of the finding when duplicates are collapsed
+	Locations []Location `json:"locations,omitempty" csv:"-"`
+	// Entropy is the Shannon entropy of the match value of the entropy module findings
+	Entropy float64 `json:"entropy,omitempty" csv:"-"`
+	// Context lists the lines around the finding when context lines are enabled
+	Context []ContextLine `json:"context,omitempty" csv:"-"`
+	// Commit is the commit which introduced the finding when the git history is scanned
+	Commit *Commit `json:"commit,omitempty" csv:"-"`
+	// EndLine is the last line of a finding spanning several lines, e.g. a private key block
+	EndLine int `json:"end_line,omitempty" csv:"-"`
+	// secret is the match value before it was masked
+	secret string
+	// fingerprint is the hash of the finding, taken before its path is made relative to another directory
+	fingerprint string
+	// pemBlock is set when the match is the BEGIN marker of a private key block, see collapsePEMBlocks
+	pemBlock bool
+}
+
+// Commit identifies a commit of the git history
+type Commit struct {
+	Hash   string `json:"hash"`
+	Author string `json:"author"`
+	Date   string `json:"date"`
+}
+
+// ContextLine is a line around a finding, Match marks the line of the finding
+type ContextLine struct {
+	Line  int    `json:"line"`
+	Value string `json:"value"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// Location is an occurrence of a finding
+type Location struct {
+	Filename string `json:"filename"`
+	Line     int    `json:"line"`
 }
 
 // File to scan
@@ -61,6 +117,12 @@ type File struct {
 	Path  string
 	Lines []Line
 	Raw   []byte
+	// Open streams the content of a file which isn't on disk, e.g. an entry of a zip archive
+	Open func() (io.ReadCloser, error)
+	// Commit is the commit which introduced this content of the file when the git history is scanned
+	Commit *Commit
+	// Skipped is the reason the content of the file wasn't scanned, e.g. binary, set by the scan
+	Skipped string
 }
 
 // Line in a file to scan
@@ -72,17 +134,39 @@ type Line struct {
 // Report is the Earlybird end output
 type Report struct {
 	Version       string   `json:"version"`
+	Commit        string   `json:"commit,omitempty"`
+	BuildDate     string   `json:"build_date,omitempty"`
 	Skipped       []string `json:"skipped"`
 	Ignore        []string `json:"ignore"`
 	Threshold     int      `json:"threshold"`
 	Modules       []string `json:"modules"`
 	Hits          []Hit    `json:"hits"`
+	Suppressed    []Hit    `json:"suppressed,omitempty"`
 	HitCount      int      `json:"hit_count"`
 	FilesScanned  int      `json:"files_scanned"`
 	RulesObserved int      `json:"rules_observed"`
 	StartTime     string   `json:"start_time"`
 	EndTime       string   `json:"end_time"`
 	Duration      string   `json:"duration"`
+	Summary       *Summary `json:"summary,omitempty"`
+}
+
+// Summary aggregates the counts of a scan, e.g. for dashboards
+type Summary struct {
+	FilesScanned int `json:"files_scanned"`
+	FilesSkipped int `json:"files_skipped"`
+	// FilesIncomplete counts the skipped files which couldn't be scanned, as they're too large, binary or unreadable,
+	// rather than being left out of the scan on purpose, e.g. ignored.  They fail the scan in strict mode.
+	FilesIncomplete int `json:"files_incomplete"`
+	// SkipReasons counts the skipped files by reason: ignored, too_large, extension, unmodified, binary, unreadable, cache
+	// or canceled
+	SkipReasons map[string]int `json:"skip_reasons"`
+	// Severities and Confidences count the findings reported by level name
+	Severities  map[string]int `json:"severities"`
+	Confidences map[string]int `json:"confidences"`
+	DurationMS  int64          `json:"duration_ms"`
+	// Truncated is set when the scan stopped at the maximum number of findings, the findings after it aren't reported
+	Truncated bool `json:"truncated,omitempty"`
 }
 
 // WorkJob As we add jobs to the pool, they need to contain the line being scanned and the file content (in Lines)
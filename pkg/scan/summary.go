@@ -0,0 +1,70 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+
+package scan
+
+import "time"
+
+// Summarize counts the files scanned and skipped, and the findings reported by severity and confidence.  The files are
+// the files of the scan, the ones whose content was skipped by the scan being counted as skipped, and skipReasons are
+// the reasons of the files skipped before the scan, by path.
+func Summarize(hits []Hit, files []File, skipReasons map[string]string, duration time.Duration) Summary {
+	summary := Summary{
+		SkipReasons: make(map[string]int),
+		Severities:  make(map[string]int),
+		Confidences: make(map[string]int),
+		DurationMS:  duration.Milliseconds(),
+	}
+	skip := func(reason string) {
+		summary.SkipReasons[reason]++
+		summary.FilesSkipped++
+		if Incomplete(reason) {
+			summary.FilesIncomplete++
+		}
+	}
+	for _, reason := range skipReasons {
+		skip(reason)
+	}
+	for _, file := range files {
+		if file.Skipped != "" {
+			skip(file.Skipped)
+			continue
+		}
+		summary.FilesScanned++
+	}
+	for _, hit := range hits {
+		summary.Count(hit)
+	}
+	return summary
+}
+
+// Count adds a finding reported to the counts by severity and confidence, e.g. to summarize the findings streamed
+// without holding all of them
+func (summary *Summary) Count(hit Hit) {
+	summary.Severities[hit.Severity]++
+	summary.Confidences[hit.Confidence]++
+}
+
+// Incomplete reports if the files skipped for the reason couldn't be scanned, as opposed to the files left out of the
+// scan on purpose, e.g. by the ignore patterns or the extension filters.  The files too large are skipped by the walk.
+func Incomplete(reason string) bool {
+	switch reason {
+	case "too_large", skipBinary, skipUnreadable:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,91 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"strconv"
+	"strings"
+)
+
+// isSuppressed reports if the line, or the line above it, contains the earlybird:disable (or earlybird:disable-line) comment.
+// The comment syntax of the language doesn't matter, the token is matched anywhere in the line.
+func isSuppressed(line Line, fileLines []Line) bool {
+	// Long lines are split up, so the token may not be in the part of the line being scanned
+	current, ok := fileLine(fileLines, line.LineNum)
+	if !ok {
+		current = line.LineValue
+	}
+	if strings.Contains(current, suppressToken) {
+		return true
+	}
+	previous, _ := fileLine(fileLines, line.LineNum-1)
+	return strings.Contains(previous, suppressToken)
+}
+
+// ignoredRules returns the codes of the rules disabled for the whole file by an earlybird:ignore-rules comment in its
+// first lines, e.g. # earlybird:ignore-rules 3012,3015.  The codes are separated by commas or spaces, and the first
+// word which isn't a code ends the list, e.g. the end of an HTML comment.
+func ignoredRules(fileLines []Line) map[int]bool {
+	var ignored map[int]bool
+	for _, line := range fileLines {
+		if line.LineNum > ignoreRulesLines {
+			break
+		}
+		i := strings.Index(line.LineValue, ignoreRulesToken)
+		if i < 0 {
+			continue
+		}
+		codes := strings.FieldsFunc(line.LineValue[i+len(ignoreRulesToken):], func(r rune) bool { return r == ',' || r == ' ' || r == '\t' })
+		for _, code := range codes {
+			n, err := strconv.Atoi(code)
+			if err != nil {
+				break
+			}
+			if ignored == nil {
+				ignored = make(map[int]bool)
+			}
+			ignored[n] = true
+		}
+	}
+	return ignored
+}
+
+// fileLine returns the full value of the line of the file, the lines are numbered from 1.  The lines of a stream are
+// a window which starts after the first line.
+func fileLine(fileLines []Line, lineNum int) (string, bool) {
+	if len(fileLines) == 0 {
+		return "", false
+	}
+	if i := lineNum - fileLines[0].LineNum; i >= 0 && i < len(fileLines) && fileLines[i].LineNum == lineNum {
+		return fileLines[i].LineValue, true
+	}
+	return "", false
+}
+
+// DropSuppressed filters the suppressed hits out, for the writers which don't report them separately
+func DropSuppressed(hits <-chan Hit) chan Hit {
+	filtered := make(chan Hit)
+	go func() {
+		defer close(filtered)
+		for hit := range hits {
+			if !hit.Suppressed {
+				filtered <- hit
+			}
+		}
+	}()
+	return filtered
+}
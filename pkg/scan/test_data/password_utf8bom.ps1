@@ -0,0 +1,3 @@
+﻿# Connect to the build database
+$password = "SecretValue1673"
+Invoke-Sqlcmd -Password $password
@@ -0,0 +1,52 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// fileContext is cancelled with the scan context or once the file took longer than cfg.FileTimeout to scan, it never
+// times out if the timeout isn't set
+func fileContext(ctx context.Context, cfg *cfgReader.EarlybirdConfig) (context.Context, context.CancelFunc) {
+	if cfg.FileTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, cfg.FileTimeout)
+}
+
+// timeoutHit is the warning finding reported when the rest of the file was skipped after the timeout, at the lowest level
+func timeoutHit(cfg *cfgReader.EarlybirdConfig, path string) Hit {
+	utils.InfoLog.Printf("Scanning %s timed out after %v, the rest of the file was skipped", path, cfg.FileTimeout)
+	level, levelID := lowestLevel(cfg.LevelMap)
+	return Hit{
+		Code:         fileTimeoutCode,
+		Filename:     removeTempPrefix(path),
+		Caption:      fmt.Sprintf("File scan timed out after %v, the rest of the file was not scanned", cfg.FileTimeout),
+		Category:     "warning",
+		Severity:     level,
+		SeverityID:   levelID,
+		Confidence:   level,
+		ConfidenceID: levelID,
+		Time:         time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,124 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package scan
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+// addSlowRule adds a deliberately slow rule to the rules for the test, each adversarial line takes milliseconds to match
+func addSlowRule(t *testing.T) {
+	savedRules := CombinedRules
+	t.Cleanup(func() { CombinedRules = savedRules })
+	CombinedRules = append(append([]Rule{}, savedRules...), Rule{
+		Code:            99999,
+		Caption:         "Slow rule",
+		Category:        "test",
+		Severity:        1,
+		Confidence:      1,
+		Searcharea:      "body",
+		CompiledPattern: regexp.MustCompile(`(?:a?){500}a{500}x`),
+	})
+}
+
+func TestSearchFilesTimeout(t *testing.T) {
+	addSlowRule(t)
+
+	dir := t.TempDir()
+	slowFile := path.Join(dir, "bundle.min.js")
+	if err := os.WriteFile(slowFile, []byte(strings.Repeat(strings.Repeat("a", 2000)+"\n", 60)), 0644); err != nil {
+		t.Fatal(err)
+	}
+	secretFile := path.Join(dir, "settings.py")
+	if err := os.WriteFile(secretFile, []byte(`password = "SecretValue1673"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	timeoutCfg := cfg
+	timeoutCfg.WorkerCount = 1
+	timeoutCfg.FileTimeout = 100 * time.Millisecond
+	hits := make(chan Hit)
+	go SearchFiles(&timeoutCfg, []File{{Name: slowFile, Path: slowFile}, {Name: secretFile, Path: secretFile}}, nil, nil, hits)
+
+	codes := make(map[int]Hit)
+	for hit := range hits {
+		codes[hit.Code] = hit
+	}
+	warning, timedOut := codes[fileTimeoutCode]
+	if !timedOut {
+		t.Fatalf("SearchFiles() didn't report the timeout, got %v", codes)
+	}
+	if warning.Filename != slowFile || warning.Severity != infoLevelSeverity {
+		t.Errorf("SearchFiles() timeout warning = %+v, want an info finding for %v", warning, slowFile)
+	}
+	// The scan continues with the next file
+	if hit, found := codes[3001]; !found || hit.Filename != secretFile {
+		t.Errorf("SearchFiles() didn't scan the file after the timeout, got %v", codes)
+	}
+}
+
+func TestSearchFilesContextCanceled(t *testing.T) {
+	addSlowRule(t)
+
+	dir := t.TempDir()
+	secretFile := path.Join(dir, "settings.py")
+	if err := os.WriteFile(secretFile, []byte(`password = "SecretValue1673"`+"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	files := []File{{Name: secretFile, Path: secretFile}}
+	// Scanning the slow files takes seconds
+	for i := 0; i < 40; i++ {
+		slowFile := path.Join(dir, fmt.Sprintf("bundle%d.min.js", i))
+		if err := os.WriteFile(slowFile, []byte(strings.Repeat(strings.Repeat("a", 2000)+"\n", 60)), 0644); err != nil {
+			t.Fatal(err)
+		}
+		files = append(files, File{Name: slowFile, Path: slowFile})
+	}
+
+	cancelCfg := cfg
+	cancelCfg.WorkerCount = 2
+	ctx, cancel := context.WithCancel(context.Background())
+	hits := make(chan Hit)
+	go SearchFilesContext(ctx, &cancelCfg, files, nil, nil, hits)
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	codes := make(map[int]bool)
+	for hit := range hits {
+		codes[hit.Code] = true
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("SearchFilesContext() returned %v after the cancellation, want a prompt return", elapsed)
+	}
+	// The findings of the files scanned before the cancellation are sent
+	if !codes[3001] {
+		t.Errorf("SearchFilesContext() didn't send the findings scanned before the cancellation, got %v", codes)
+	}
+	if codes[fileTimeoutCode] {
+		t.Error("SearchFilesContext() reported the cancellation as a timeout")
+	}
+	if skipped := files[len(files)-1].Skipped; skipped != skipCanceled {
+		t.Errorf("SearchFilesContext() last file skipped = %q, want %q", skipped, skipCanceled)
+	}
+}
@@ -30,6 +30,12 @@ const (
 	Staged string = "staged"
 	//Tracked is the const for tracking tracked files
 	Tracked string = "tracked"
+	//Range is the const for tracking the files changed in a git revision range
+	Range string = "range"
+	//Index is the const for tracking the content of the staged files in the git index
+	Index string = "index"
+	//History is the const for tracking the files introduced by each commit of the git history
+	History string = "history"
 	//All is the const for tracking all files
 	All string = "all"
 )
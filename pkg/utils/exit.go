@@ -0,0 +1,46 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// The exit codes of Earlybird, which tell a scan failing on its findings from a scan which couldn't run
+const (
+	// ExitClean is the exit code of a scan without findings at or above the fail thresholds
+	ExitClean = 0
+	// ExitFindings is the exit code of a scan with findings at or above the fail thresholds, or with files which couldn't
+	// be scanned in strict mode
+	ExitFindings = 1
+	// ExitError is the exit code of a scan which couldn't run, e.g. an invalid configuration or a path which can't be read
+	ExitError = 2
+)
+
+// Fatal logs the error like log.Fatal and exits with ExitError
+func Fatal(v ...interface{}) {
+	_ = log.Output(2, fmt.Sprint(v...))
+	os.Exit(ExitError)
+}
+
+// Fatalf logs the error like log.Fatalf and exits with ExitError
+func Fatalf(format string, v ...interface{}) {
+	_ = log.Output(2, fmt.Sprintf(format, v...))
+	os.Exit(ExitError)
+}
@@ -0,0 +1,36 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package utils
+
+import (
+	"io"
+	"log"
+	"os"
+)
+
+// InfoLog logs the progress and informational messages to stderr, e.g. the files read or skipped.  The errors are logged
+// with the standard logger instead, so they're still reported in quiet mode.
+var InfoLog = log.New(os.Stderr, "", log.LstdFlags)
+
+// SetQuiet discards the messages of InfoLog in quiet mode, leaving only the findings and the errors in the output
+func SetQuiet(quiet bool) {
+	if quiet {
+		InfoLog.SetOutput(io.Discard)
+		return
+	}
+	InfoLog.SetOutput(os.Stderr)
+}
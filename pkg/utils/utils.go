@@ -17,13 +17,18 @@
 package utils
 
 import (
+	"fmt"
 	"log"
+	"math"
 	"net/url"
 	"os"
 	"path/filepath"
 	"regexp"
 	"runtime"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/howeyc/gopass"
@@ -42,11 +47,128 @@ func Contains(haystack []string, needle string) bool {
 	return false
 }
 
+// ParseCodes parses a comma separated list of codes, e.g. "3001,3002"
+func ParseCodes(list string) (codes []int, err error) {
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		code, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid code %q: %w", value, err)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+// ParseList parses a comma separated list of names, e.g. "pii, key"
+func ParseList(list string) (values []string) {
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// ParseExtensions parses a comma separated list of file extensions, e.g. ".yml, JSON" -- the extensions are lowercased
+// and start with a dot
+func ParseExtensions(list string) (extensions []string) {
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.ToLower(strings.TrimSpace(value)); value == "" {
+			continue
+		}
+		if !strings.HasPrefix(value, ".") {
+			value = "." + value
+		}
+		extensions = append(extensions, value)
+	}
+	return extensions
+}
+
+// byteUnits are the units of the sizes, from the largest, KB, MB and GB being multiples of 1024
+var byteUnits = []struct {
+	suffix string
+	size   int64
+}{{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10}, {"B", 1}}
+
+// ParseByteSize parses a size in bytes, with an optional unit, e.g. "5MB", "512KB" or "10240000"
+func ParseByteSize(value string) (int64, error) {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	// KiB, MiB and GiB are the same units
+	number = strings.Replace(number, "IB", "B", 1)
+	size := int64(1)
+	for _, unit := range byteUnits {
+		if strings.HasSuffix(number, unit.suffix) {
+			number, size = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix)), unit.size
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a number of bytes with an optional unit, e.g. 5MB", value)
+	}
+	return int64(n * float64(size)), nil
+}
+
+// FormatByteSize formats a size in bytes with the largest unit it reaches, to one decimal, e.g. 5242880 is "5MB"
+func FormatByteSize(size int64) string {
+	for _, unit := range byteUnits {
+		if size >= unit.size {
+			return strconv.FormatFloat(math.Round(float64(size)/float64(unit.size)*10)/10, 'f', -1, 64) + unit.suffix
+		}
+	}
+	return strconv.FormatInt(size, 10) + "B"
+}
+
+// timestampLayouts are the layouts of the timestamps, from the most precise, the ones without a time zone being local
+var timestampLayouts = []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02"}
+
+// ParseSince parses a point in time, either a timestamp, e.g. "2024-05-02T10:14:03Z" or "2024-05-02", or a duration
+// before now, e.g. "36h" or "7d"
+func ParseSince(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	for _, layout := range timestampLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q, expected a timestamp, e.g. 2024-05-02T10:14:03Z or 2024-05-02, or a duration, e.g. 36h or 7d", value)
+}
+
+// CommonDir returns the deepest directory containing all of the paths, e.g. /src for /src/app and /src/lib
+func CommonDir(paths []string) string {
+	if len(paths) == 0 {
+		return ""
+	}
+	common := filepath.Clean(paths[0])
+	for _, path := range paths[1:] {
+		path = filepath.Clean(path)
+		for common != filepath.Dir(common) && path != common && !strings.HasPrefix(path, common+string(filepath.Separator)) {
+			common = filepath.Dir(common)
+		}
+		if path != common && !strings.HasPrefix(path, common) {
+			// The paths don't share a root, e.g. relative paths or Windows volumes
+			return ""
+		}
+	}
+	return common
+}
+
 // PathMustExist exit if path is invalid
 func PathMustExist(path string) {
 	if fileExists, err := Exists(path); !fileExists {
 		if err != nil {
-			log.Fatal(errInvalidPath)
+			Fatal(errInvalidPath)
 		}
 	}
 }
@@ -59,7 +181,7 @@ func GetConfigDir() (configDir string) {
 
 	userHomeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatal("Home directory doesn't exist", err)
+		Fatal("Home directory doesn't exist", err)
 	}
 	cwd := MustGetED()
 
@@ -87,7 +209,7 @@ func GetConfigDir() (configDir string) {
 func MustGetED() string {
 	ex, err := os.Executable()
 	if err != nil {
-		log.Fatal(err)
+		Fatal(err)
 	}
 	return filepath.Dir(ex)
 }
@@ -96,14 +218,20 @@ func MustGetED() string {
 func MustGetWD() string {
 	cwd, err := os.Getwd()
 	if err != nil {
-		log.Fatal(err)
+		Fatal(err)
 	}
 	return cwd
 }
 
 // GetTargetType returns the file scan context
-func GetTargetType(GitStagedFlag, GitTrackedFlag bool) (targetType string) {
-	if GitStagedFlag {
+func GetTargetType(GitStagedFlag, GitTrackedFlag, PreCommitFlag, GitHistoryFlag bool, GitRange string) (targetType string) {
+	if PreCommitFlag {
+		targetType = Index
+	} else if GitHistoryFlag {
+		targetType = History
+	} else if GitRange != "" {
+		targetType = Range
+	} else if GitStagedFlag {
 		targetType = Staged
 	} else if GitTrackedFlag {
 		targetType = Tracked
@@ -127,6 +255,33 @@ func GetEnabledModulesMap(enableFlags []string, availableModules map[string]stri
 	return enabledModules
 }
 
+// SelectModules returns a map of module name to filename of the modules enabled, every available module by default,
+// less the modules disabled -- a module both enabled and disabled is disabled.  Naming a module which isn't available,
+// or disabling every module, is an error as the scan would silently find nothing.
+func SelectModules(enableFlags, disableFlags []string, availableModules map[string]string) (selectedModules map[string]string, err error) {
+	var names []string
+	for moduleName := range availableModules {
+		names = append(names, moduleName)
+	}
+	sort.Strings(names)
+	for _, moduleName := range append(append([]string(nil), enableFlags...), disableFlags...) {
+		if _, ok := availableModules[moduleName]; !ok {
+			return nil, fmt.Errorf("unknown rule module %q, expected one of %v", moduleName, names)
+		}
+	}
+
+	selectedModules = make(map[string]string)
+	for moduleName, fileName := range GetEnabledModulesMap(enableFlags, availableModules) {
+		if !Contains(disableFlags, moduleName) {
+			selectedModules[moduleName] = fileName
+		}
+	}
+	if len(selectedModules) == 0 {
+		return nil, fmt.Errorf("no rule module is enabled, enable at least one of %v", names)
+	}
+	return selectedModules, nil
+}
+
 // GetDisplayList Build the string to display an array in a human readable format
 func GetDisplayList(levelNames []string) string {
 	return "[ " + strings.Join(levelNames, " | ") + " ]"
@@ -163,8 +318,7 @@ func GetGitRepo(gitURL string) (repository string) {
 func GetBBProject(bbURL string) (project string) {
 	results := bbProjectsPattern.FindStringSubmatch(bbURL) // Match second capture group, 1 = project/XXX, 2 = XXX
 	if len(results) < 1 {
-		log.Println("Failed To Get BB Project from URL:", bbURL)
-		os.Exit(1)
+		Fatal("Failed To Get BB Project from URL: ", bbURL)
 	} else {
 		project = results[1]
 	}
@@ -215,8 +369,7 @@ func GetGitURL(ptrRepo, ptrRepoUser *string) (Password string) {
 		log.Print(gitPasswdPrompt)
 		RepoPass, err := gopass.GetPasswdMasked()
 		if err != nil {
-			log.Println(errGitPasswd, err)
-			os.Exit(1)
+			Fatal(errGitPasswd, " ", err)
 		}
 		// Format git URL with user and password
 		return string(RepoPass)
@@ -18,7 +18,10 @@ package utils
 
 import (
 	"os"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestContains(t *testing.T) {
@@ -49,6 +52,201 @@ func TestContains(t *testing.T) {
 	}
 }
 
+func TestParseCodes(t *testing.T) {
+	tests := []struct {
+		name    string
+		list    string
+		want    []int
+		wantErr bool
+	}{
+		{
+			name: "Empty list",
+			list: "",
+		},
+		{
+			name: "Comma separated codes",
+			list: "3001, 3002,,3005",
+			want: []int{3001, 3002, 3005},
+		},
+		{
+			name:    "Invalid code",
+			list:    "3001,abc",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCodes(tt.list)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCodes() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCodes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseList(t *testing.T) {
+	if got := ParseList(""); got != nil {
+		t.Errorf("ParseList() = %v, want nil", got)
+	}
+	want := []string{"pii", "password-secret"}
+	if got := ParseList(" pii,,password-secret "); !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseList() = %v, want %v", got, want)
+	}
+}
+
+func TestParseExtensions(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+		want []string
+	}{
+		{
+			name: "Empty list",
+			list: "",
+		},
+		{
+			name: "Extensions are lowercased and start with a dot",
+			list: ".PNG, woff,,.min.js",
+			want: []string{".png", ".woff", ".min.js"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseExtensions(tt.list); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseExtensions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:  "Bytes without unit",
+			value: "10240000",
+			want:  10240000,
+		},
+		{
+			name:  "Megabytes",
+			value: "5MB",
+			want:  5 * 1024 * 1024,
+		},
+		{
+			name:  "Lower case kilobytes with a space",
+			value: "512 kb",
+			want:  512 * 1024,
+		},
+		{
+			name:  "Fraction of gigabytes",
+			value: "1.5GiB",
+			want:  3 * 512 * 1024 * 1024,
+		},
+		{
+			name:    "Unknown unit",
+			value:   "5XB",
+			wantErr: true,
+		},
+		{
+			name:    "Negative size",
+			value:   "-1MB",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseByteSize(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseByteSize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatByteSize(t *testing.T) {
+	tests := []struct {
+		size int64
+		want string
+	}{
+		{size: 0, want: "0B"},
+		{size: 999, want: "999B"},
+		{size: 5 * 1024 * 1024, want: "5MB"},
+		{size: 10240000, want: "9.8MB"},
+		{size: 1 << 30, want: "1GB"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := FormatByteSize(tt.size); got != tt.want {
+				t.Errorf("FormatByteSize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 5, 10, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "RFC 3339 timestamp", value: "2024-05-02T10:14:03Z", want: time.Date(2024, 5, 2, 10, 14, 3, 0, time.UTC)},
+		{name: "Local timestamp", value: "2024-05-02 10:14:03", want: time.Date(2024, 5, 2, 10, 14, 3, 0, time.Local)},
+		{name: "Local date", value: "2024-05-02", want: time.Date(2024, 5, 2, 0, 0, 0, 0, time.Local)},
+		{name: "Hours before now", value: "36h", want: now.Add(-36 * time.Hour)},
+		{name: "Days before now", value: " 7d ", want: now.AddDate(0, 0, -7)},
+		{name: "Negative duration", value: "-1h", wantErr: true},
+		{name: "Invalid date", value: "2024-13-02", wantErr: true},
+		{name: "Unknown unit", value: "2w", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSince(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSince() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseSince() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCommonDir(t *testing.T) {
+	tests := []struct {
+		name  string
+		paths []string
+		want  string
+	}{
+		{name: "Sibling directories", paths: []string{"/src/app", "/src/lib"}, want: "/src"},
+		{name: "Common name prefix", paths: []string{"/src/app", "/src/application/"}, want: "/src"},
+		{name: "Nested directory", paths: []string{"/src/app", "/src/app/vendor", "/src/app"}, want: "/src/app"},
+		{name: "Only the root in common", paths: []string{"/home/dev/app", "/opt/app"}, want: "/"},
+		{name: "Relative and absolute paths", paths: []string{"app", "/opt/app"}, want: ""},
+		{name: "No path", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CommonDir(tt.paths); got != tt.want {
+				t.Errorf("CommonDir() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestPathMustExist(t *testing.T) {
 	currentDir, err := os.Getwd()
 	if err != nil {
@@ -94,6 +292,74 @@ func TestMustGetWD(t *testing.T) {
 	}
 }
 
+func TestSelectModules(t *testing.T) {
+	available := map[string]string{"content": "content.yaml", "entropy": "entropy.yaml", "filename": "filename.yaml"}
+	tests := []struct {
+		name    string
+		enable  []string
+		disable []string
+		want    map[string]string
+		wantErr string
+	}{
+		{
+			name: "Every module by default",
+			want: available,
+		},
+		{
+			name:   "Enabled modules only",
+			enable: []string{"filename"},
+			want:   map[string]string{"filename": "filename.yaml"},
+		},
+		{
+			name:    "Disabled modules are skipped",
+			disable: []string{"entropy"},
+			want:    map[string]string{"content": "content.yaml", "filename": "filename.yaml"},
+		},
+		{
+			name:    "Disabling takes precedence over enabling",
+			enable:  []string{"content", "entropy"},
+			disable: []string{"entropy"},
+			want:    map[string]string{"content": "content.yaml"},
+		},
+		{
+			name:    "Unknown module",
+			enable:  []string{"no-such-module"},
+			wantErr: `unknown rule module "no-such-module", expected one of [content entropy filename]`,
+		},
+		{
+			name:    "Unknown disabled module",
+			disable: []string{"entrop"},
+			wantErr: `unknown rule module "entrop"`,
+		},
+		{
+			name:    "Every module disabled",
+			enable:  []string{"content"},
+			disable: []string{"content"},
+			wantErr: "no rule module is enabled, enable at least one of [content entropy filename]",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SelectModules(tt.enable, tt.disable, available)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("SelectModules() err = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SelectModules() err = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SelectModules() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if len(available) != 3 {
+		t.Errorf("SelectModules() modified the available modules: %v", available)
+	}
+}
+
 func TestGetDisplayList(t *testing.T) {
 	type args struct {
 		levelNames []string
@@ -16,7 +16,57 @@
 
 package wildcard
 
-import "strings"
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// ErrBadPattern is returned by Compile when the pattern is malformed
+var ErrBadPattern = errors.New("syntax error in pattern")
+
+const (
+	pathSeparator string = "/"
+	globstar      string = "**"
+	// escape makes the following character match literally, e.g. foo\*bar.txt
+	escape rune = '\\'
+)
+
+type tokenKind int
+
+const (
+	literal tokenKind = iota
+	anyChar
+	anySequence
+	charClass
+)
+
+// token is a single element of a pattern segment: a literal, '?', '*' or a bracket expression
+type token struct {
+	kind    tokenKind
+	char    rune
+	negate  bool
+	ranges  []charRange
+	classes []func(rune) bool
+}
+
+// charRange is an inclusive range of characters in a bracket expression, e.g. [a-z]
+type charRange struct {
+	lo, hi rune
+}
+
+// posixClasses are the named character classes supported inside bracket expressions, e.g. [[:digit:]]
+var posixClasses = map[string]func(rune) bool{
+	"alnum":  func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) },
+	"alpha":  unicode.IsLetter,
+	"blank":  func(r rune) bool { return r == ' ' || r == '\t' },
+	"digit":  unicode.IsDigit,
+	"lower":  unicode.IsLower,
+	"punct":  unicode.IsPunct,
+	"space":  unicode.IsSpace,
+	"upper":  unicode.IsUpper,
+	"xdigit": func(r rune) bool { return unicode.IsDigit(r) || ('a' <= r && r <= 'f') || ('A' <= r && r <= 'F') },
+}
 
 func initLookupTable(row, column int) [][]bool {
 	lookup := make([][]bool, row)
@@ -26,10 +76,129 @@ func initLookupTable(row, column int) [][]bool {
 	return lookup
 }
 
-//PatternMatch Function that matches input str with given wildcard pattern
+// PatternMatch Function that matches input str with given wildcard pattern
+// A single '*' never crosses a path separator while a '**' segment matches zero or more path segments.
+// A backslash escapes the following character, so '\*' only matches a literal '*'.
+// Patterns without a separator are matched against the last segment of str, so they apply at any depth,
+// while a leading separator anchors the pattern to the root of str.
+// Matching is case-insensitive, e.g. *.jpg matches PHOTO.JPG.
+// Use Compile instead when matching the same pattern against many paths.
 func PatternMatch(str, pattern string) bool {
-	s := []rune(strings.ToLower(str))
-	p := []rune(strings.ToLower(pattern))
+	m, err := Compile(pattern)
+	return err == nil && m.Match(str)
+}
+
+// PatternMatchCase is the case-sensitive variant of PatternMatch, e.g. Thumbs.db doesn't match thumbs.db
+func PatternMatchCase(str, pattern string) bool {
+	m, err := CompileCase(pattern)
+	return err == nil && m.Match(str)
+}
+
+// Matcher is a wildcard pattern parsed once by Compile, so it can be matched against any number of paths
+type Matcher struct {
+	foldCase bool
+	empty    bool
+	// basename patterns have no separator and are matched against the last segment of the path
+	basename bool
+	segments []segment
+}
+
+// segment is a single compiled segment of the pattern, either a '**' or the tokens matched by segmentMatch
+type segment struct {
+	globstar bool
+	tokens   []token
+}
+
+// Compile parses the wildcard pattern into a Matcher, following the same rules as PatternMatch.
+// It returns ErrBadPattern when a bracket expression uses an unknown character class.
+func Compile(pattern string) (Matcher, error) {
+	return compile(pattern, true)
+}
+
+// CompileCase is the case-sensitive variant of Compile
+func CompileCase(pattern string) (Matcher, error) {
+	return compile(pattern, false)
+}
+
+func compile(pattern string, foldCase bool) (m Matcher, err error) {
+	m.foldCase = foldCase
+	// Fold both sides the same way so bracket ranges like [A-Z] keep working
+	if foldCase {
+		pattern = strings.ToLower(pattern)
+	}
+	anchored := strings.HasPrefix(pattern, pathSeparator)
+	pattern = strings.TrimPrefix(pattern, pathSeparator)
+
+	// empty pattern can only match with empty string
+	if len(pattern) == 0 {
+		m.empty = true
+		return m, nil
+	}
+
+	m.basename = !anchored && !strings.Contains(pattern, pathSeparator)
+	for _, part := range strings.Split(pattern, pathSeparator) {
+		if part == globstar && !m.basename {
+			m.segments = append(m.segments, segment{globstar: true})
+			continue
+		}
+		tokens, err := tokenize(part)
+		if err != nil {
+			return Matcher{}, err
+		}
+		m.segments = append(m.segments, segment{tokens: tokens})
+	}
+	return m, nil
+}
+
+// Match reports if the path matches the compiled pattern
+func (m Matcher) Match(str string) bool {
+	if m.foldCase {
+		str = strings.ToLower(str)
+	}
+	str = strings.TrimPrefix(str, pathSeparator)
+	if m.empty {
+		return len(str) == 0
+	}
+
+	s := strings.Split(str, pathSeparator)
+	if m.basename {
+		return segmentMatch(s[len(s)-1], m.segments[0].tokens)
+	}
+	return segmentsMatch(s, m.segments)
+}
+
+// segmentsMatch matches the path segments against the pattern segments, expanding '**' to any number of segments
+func segmentsMatch(s []string, p []segment) bool {
+	lookup := initLookupTable(len(s)+1, len(p)+1)
+	lookup[0][0] = true
+
+	// Only a leading or mid-pattern '**' can match zero segments
+	for j := 1; j < len(p); j++ {
+		if p[j-1].globstar {
+			lookup[0][j] = lookup[0][j-1]
+		}
+	}
+
+	for i := 1; i < len(s)+1; i++ {
+		for j := 1; j < len(p)+1; j++ {
+			switch {
+			case p[j-1].globstar && j == len(p):
+				// A trailing '**' matches everything inside, but not the directory itself
+				lookup[i][j] = lookup[i-1][j-1] || lookup[i-1][j]
+			case p[j-1].globstar:
+				// Either skip the '**' or let it swallow the ith segment
+				lookup[i][j] = lookup[i][j-1] || lookup[i-1][j]
+			default:
+				lookup[i][j] = lookup[i-1][j-1] && segmentMatch(s[i-1], p[j-1].tokens)
+			}
+		}
+	}
+	return lookup[len(s)][len(p)]
+}
+
+// segmentMatch matches a single path segment against the tokens of a single pattern segment
+func segmentMatch(str string, p []token) bool {
+	s := []rune(str)
 
 	// empty pattern can only match with empty string
 	if len(p) == 0 {
@@ -45,7 +214,7 @@ func PatternMatch(str, pattern string) bool {
 
 	// Only '*' can match with empty string
 	for j := 1; j < len(p)+1; j++ {
-		if p[j-1] == '*' {
+		if p[j-1].kind == anySequence {
 			lookup[0][j] = lookup[0][j-1]
 		}
 	}
@@ -53,7 +222,7 @@ func PatternMatch(str, pattern string) bool {
 	// fill the table in bottom-up fashion
 	for i := 1; i < len(s)+1; i++ {
 		for j := 1; j < len(p)+1; j++ {
-			if p[j-1] == '*' {
+			if p[j-1].kind == anySequence {
 				// Two cases if we see a '*'
 				// a) We ignore ‘*’ character and move
 				//    to next  character in the pattern,
@@ -62,11 +231,12 @@ func PatternMatch(str, pattern string) bool {
 				//     character in input
 				lookup[i][j] = lookup[i][j-1] || lookup[i-1][j]
 
-			} else if p[j-1] == '?' || s[i-1] == p[j-1] {
+			} else if p[j-1].matches(s[i-1]) {
 				// Current characters are considered as
-				// matching in two cases
+				// matching in three cases
 				// (a) current character of pattern is '?'
 				// (b) characters actually match
+				// (c) character is part of the bracket expression
 				lookup[i][j] = lookup[i-1][j-1]
 
 			} else {
@@ -78,3 +248,111 @@ func PatternMatch(str, pattern string) bool {
 
 	return lookup[len(s)][len(p)]
 }
+
+// tokenize breaks a pattern segment down into the tokens used by segmentMatch
+func tokenize(pattern string) (tokens []token, err error) {
+	p := []rune(pattern)
+	for i := 0; i < len(p); i++ {
+		switch p[i] {
+		case '*':
+			tokens = append(tokens, token{kind: anySequence})
+		case '?':
+			tokens = append(tokens, token{kind: anyChar})
+		case '[':
+			class, end, ok, err := parseBracket(p, i)
+			if err != nil {
+				return nil, err
+			}
+			if ok {
+				tokens = append(tokens, class)
+				i = end
+				continue
+			}
+			// An unterminated bracket is matched literally
+			tokens = append(tokens, token{kind: literal, char: p[i]})
+		case escape:
+			// The escaped character is matched literally, a trailing backslash matches itself
+			if i+1 < len(p) {
+				i++
+			}
+			tokens = append(tokens, token{kind: literal, char: p[i]})
+		default:
+			tokens = append(tokens, token{kind: literal, char: p[i]})
+		}
+	}
+	return tokens, nil
+}
+
+// parseBracket parses the bracket expression starting at p[start], returning the index of the closing ']'.
+// ok is false when the bracket is never closed.
+func parseBracket(p []rune, start int) (class token, end int, ok bool, err error) {
+	class.kind = charClass
+	i := start + 1
+	if i < len(p) && (p[i] == '!' || p[i] == '^') {
+		class.negate = true
+		i++
+	}
+
+	// A ']' directly after the opening bracket (or negation) is a literal
+	first := i
+	for ; i < len(p); i++ {
+		switch {
+		case p[i] == ']' && i != first:
+			return class, i, true, nil
+		case p[i] == '[' && i+1 < len(p) && p[i+1] == ':':
+			// POSIX named class, e.g. [:digit:]
+			closing := strings.Index(string(p[i+2:]), ":]")
+			if closing < 0 {
+				class.ranges = append(class.ranges, charRange{p[i], p[i]})
+				continue
+			}
+			name := string(p[i+2:])[:closing]
+			isClass, known := posixClasses[name]
+			if !known {
+				return class, 0, false, ErrBadPattern
+			}
+			class.classes = append(class.classes, isClass)
+			i += len([]rune(name)) + 3
+		default:
+			lo := unescape(p, &i)
+			if i+2 < len(p) && p[i+1] == '-' && p[i+2] != ']' {
+				i += 2
+				class.ranges = append(class.ranges, charRange{lo, unescape(p, &i)})
+				continue
+			}
+			class.ranges = append(class.ranges, charRange{lo, lo})
+		}
+	}
+	return class, 0, false, nil
+}
+
+// unescape returns the character at p[*i], moving past the backslash when it's escaped
+func unescape(p []rune, i *int) rune {
+	if p[*i] == escape && *i+1 < len(p) {
+		*i++
+	}
+	return p[*i]
+}
+
+// matches reports if the rune is accepted by a single character token
+func (t token) matches(r rune) bool {
+	switch t.kind {
+	case anyChar:
+		return true
+	case literal:
+		return t.char == r
+	case charClass:
+		for _, cr := range t.ranges {
+			if cr.lo <= r && r <= cr.hi {
+				return !t.negate
+			}
+		}
+		for _, isClass := range t.classes {
+			if isClass(r) {
+				return !t.negate
+			}
+		}
+		return t.negate
+	}
+	return false
+}
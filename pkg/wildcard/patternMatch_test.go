@@ -18,7 +18,9 @@ package wildcard
 
 import (
 	"reflect"
+	"strings"
 	"testing"
+	"unicode/utf8"
 )
 
 func Test_initLookupTable(t *testing.T) {
@@ -107,6 +109,118 @@ func TestWildcardPatternMatch(t *testing.T) {
 			},
 			want: true,
 		},
+		{
+			name: "Single wildcard does not cross separators",
+			args: args{
+				str:     "src/a/b/foo.test.js",
+				pattern: "src/*.test.js",
+			},
+			want: false,
+		},
+		{
+			name: "Leading globstar matches at the root",
+			args: args{
+				str:     "/node_modules",
+				pattern: "**/node_modules",
+			},
+			want: true,
+		},
+		{
+			name: "Leading globstar matches nested directories",
+			args: args{
+				str:     "/packages/web/node_modules",
+				pattern: "**/node_modules",
+			},
+			want: true,
+		},
+		{
+			name: "Mid-pattern globstar matches zero segments",
+			args: args{
+				str:     "src/foo.test.js",
+				pattern: "src/**/*.test.js",
+			},
+			want: true,
+		},
+		{
+			name: "Mid-pattern globstar matches several segments",
+			args: args{
+				str:     "src/a/b/foo.test.js",
+				pattern: "src/**/*.test.js",
+			},
+			want: true,
+		},
+		{
+			name: "Mid-pattern globstar is anchored to the root",
+			args: args{
+				str:     "lib/src/a/foo.test.js",
+				pattern: "src/**/*.test.js",
+			},
+			want: false,
+		},
+		{
+			name: "Trailing globstar matches directory contents",
+			args: args{
+				str:     "vendor/github.com/pkg/errors/errors.go",
+				pattern: "vendor/**",
+			},
+			want: true,
+		},
+		{
+			name: "Trailing globstar does not match the directory itself",
+			args: args{
+				str:     "vendor",
+				pattern: "vendor/**",
+			},
+			want: false,
+		},
+		{
+			name: "Go test files",
+			args: args{
+				str:     "/pkg/scan/scan_test.go",
+				pattern: "**/*_test.go",
+			},
+			want: true,
+		},
+		{
+			name: "Go vendored sources",
+			args: args{
+				str:     "/cmd/vendor/golang.org/x/net/http2/server.go",
+				pattern: "**/vendor/**",
+			},
+			want: true,
+		},
+		{
+			name: "Node test files",
+			args: args{
+				str:     "/src/components/button/button.test.js",
+				pattern: "src/**/*.test.js",
+			},
+			want: true,
+		},
+		{
+			name: "Node modules",
+			args: args{
+				str:     "/node_modules/express/lib/router/index.js",
+				pattern: "**/node_modules/**",
+			},
+			want: true,
+		},
+		{
+			name: "Python bytecode cache",
+			args: args{
+				str:     "/app/models/__pycache__/user.cpython-39.pyc",
+				pattern: "**/__pycache__/*",
+			},
+			want: true,
+		},
+		{
+			name: "Python virtualenv is not matched outside its directory",
+			args: args{
+				str:     "/app/venv.py",
+				pattern: "**/venv/**",
+			},
+			want: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -116,3 +230,301 @@ func TestWildcardPatternMatch(t *testing.T) {
 		})
 	}
 }
+
+func TestWildcardPatternMatchBrackets(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		pattern string
+		want    bool
+	}{
+		{name: "Character set", str: "lib.o", pattern: "*.[oa]", want: true},
+		{name: "Character set miss", str: "lib.so", pattern: "*.[oa]", want: false},
+		{name: "Mixed case set", str: "/docs/Thumbs.db", pattern: "[Tt]humbs.db", want: true},
+		{name: "Range", str: "file7.log", pattern: "file[0-9].log", want: true},
+		{name: "Range miss", str: "filex.log", pattern: "file[0-9].log", want: false},
+		{name: "Range matches a single character only", str: "file10.log", pattern: "file[0-9].log", want: false},
+		{name: "Negation with bang", str: "file.c", pattern: "file.[!abc]", want: false},
+		{name: "Negation with bang miss", str: "file.d", pattern: "file.[!abc]", want: true},
+		{name: "Negation with caret", str: "file.d", pattern: "file.[^abc]", want: true},
+		{name: "Literal closing bracket first", str: "a]", pattern: "a[]]", want: true},
+		{name: "Literal closing bracket after negation", str: "a]", pattern: "a[!]]", want: false},
+		{name: "POSIX class", str: "build42", pattern: "build[[:digit:]][[:digit:]]", want: true},
+		{name: "POSIX class miss", str: "buildxy", pattern: "build[[:digit:]]*", want: false},
+		{name: "Bracket with star", str: "/logs/app-2021.log", pattern: "**/app-[0-9]*.log", want: true},
+		{name: "Bracket does not match separator", str: "a/b", pattern: "a[/]b", want: false},
+		{name: "Unterminated bracket is literal", str: "file[1.txt", pattern: "file[1.txt", want: true},
+		{name: "Unterminated bracket does not act as a set", str: "file1.txt", pattern: "file[1.txt", want: false},
+		{name: "Lone opening bracket", str: "[", pattern: "[", want: true},
+		{name: "Unterminated negation", str: "[!", pattern: "[!", want: true},
+		{name: "Unterminated POSIX class", str: "[[:digit", pattern: "[[:digit", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PatternMatch(tt.str, tt.pattern); got != tt.want {
+				t.Errorf("PatternMatch(%q, %q) = %v, want %v", tt.str, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWildcardPatternMatchAnchored(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		pattern string
+		want    bool
+	}{
+		{name: "Anchored pattern matches at the root", str: "/secret.txt", pattern: "/secret.txt", want: true},
+		{name: "Anchored pattern matches a path without a leading separator", str: "secret.txt", pattern: "/secret.txt", want: true},
+		{name: "Anchored pattern does not match nested paths", str: "/sub/secret.txt", pattern: "/secret.txt", want: false},
+		{name: "Floating pattern matches at the root", str: "/secret.txt", pattern: "secret.txt", want: true},
+		{name: "Floating pattern matches nested paths", str: "/sub/secret.txt", pattern: "secret.txt", want: true},
+		{name: "Anchored wildcard", str: "/config.yml", pattern: "/*.yml", want: true},
+		{name: "Anchored wildcard does not match nested paths", str: "/sub/config.yml", pattern: "/*.yml", want: false},
+		{name: "Anchored globstar", str: "/sub/config.yml", pattern: "/**/config.yml", want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PatternMatch(tt.str, tt.pattern); got != tt.want {
+				t.Errorf("PatternMatch(%q, %q) = %v, want %v", tt.str, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWildcardPatternMatchCase(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		pattern string
+		want    bool
+		wantCS  bool
+	}{
+		{name: "Same case", str: "Thumbs.db", pattern: "Thumbs.db", want: true, wantCS: true},
+		{name: "Lower case path", str: "thumbs.db", pattern: "Thumbs.db", want: true, wantCS: false},
+		{name: "Upper case pattern", str: "/src/photo.jpg", pattern: "*.JPG", want: true, wantCS: false},
+		{name: "Folded bracket range", str: "file_b.txt", pattern: "file_[A-C].txt", want: true, wantCS: false},
+		{name: "Folded globstar path", str: "/Src/Vendor/lib.go", pattern: "src/**/*.go", want: true, wantCS: false},
+		{name: "No match in either mode", str: "readme.md", pattern: "*.TXT", want: false, wantCS: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PatternMatch(tt.str, tt.pattern); got != tt.want {
+				t.Errorf("PatternMatch(%q, %q) = %v, want %v", tt.str, tt.pattern, got, tt.want)
+			}
+			if got := PatternMatchCase(tt.str, tt.pattern); got != tt.wantCS {
+				t.Errorf("PatternMatchCase(%q, %q) = %v, want %v", tt.str, tt.pattern, got, tt.wantCS)
+			}
+		})
+	}
+}
+
+func TestCompile(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		paths   map[string]bool
+		wantErr bool
+	}{
+		{
+			name:    "Compiled matcher is reusable",
+			pattern: "**/node_modules/**",
+			paths: map[string]bool{
+				"/node_modules/lodash/index.js":    true,
+				"/web/node_modules/react/index.js": true,
+				"/web/src/node_modules.js":         false,
+				"/node_modules":                    false,
+			},
+		},
+		{
+			name:    "Basename pattern",
+			pattern: "*.[ch]",
+			paths: map[string]bool{
+				"/src/main.c":   true,
+				"/include/io.h": true,
+				"/src/main.go":  false,
+			},
+		},
+		{
+			name:    "Empty pattern",
+			pattern: "",
+			paths: map[string]bool{
+				"":      true,
+				"/":     true,
+				"/file": false,
+			},
+		},
+		{
+			name:    "Unknown character class",
+			pattern: "*.[[:nope:]]",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := Compile(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Compile(%q) error = %v, wantErr %v", tt.pattern, err, tt.wantErr)
+			}
+			for path, want := range tt.paths {
+				if got := m.Match(path); got != want {
+					t.Errorf("Compile(%q).Match(%q) = %v, want %v", tt.pattern, path, got, want)
+				}
+				if got := PatternMatch(path, tt.pattern); got != want {
+					t.Errorf("PatternMatch(%q, %q) = %v, want %v", path, tt.pattern, got, want)
+				}
+			}
+		})
+	}
+}
+
+var (
+	benchmarkPatterns = []string{"**/*.git/**", "**/*.jpg", "**/*.png", "**/*vendor*", "**/bin/*", "*.so", "Cargo.lock", "**/node_modules/**", "src/**/*.test.js", "*.[oa]"}
+	benchmarkPaths    = []string{"/src/main.go", "/web/node_modules/react/index.js", "/assets/img/logo.png", "/src/app/app.test.js", "/pkg/scan/scan.go", "/vendor/github.com/lib/lib.go", "/build/lib/libfoo.a", "/docs/README.md"}
+)
+
+// BenchmarkPatternMatch parses every pattern again for each path
+func BenchmarkPatternMatch(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		for _, path := range benchmarkPaths {
+			for _, pattern := range benchmarkPatterns {
+				PatternMatch(path, pattern)
+			}
+		}
+	}
+}
+
+// BenchmarkCompiledMatch compiles the patterns once, like the file walker does
+func BenchmarkCompiledMatch(b *testing.B) {
+	matchers := make([]Matcher, 0, len(benchmarkPatterns))
+	for _, pattern := range benchmarkPatterns {
+		m, err := Compile(pattern)
+		if err != nil {
+			b.Fatal(err)
+		}
+		matchers = append(matchers, m)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, path := range benchmarkPaths {
+			for _, m := range matchers {
+				m.Match(path)
+			}
+		}
+	}
+}
+
+func TestWildcardPatternMatchEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		str     string
+		pattern string
+		want    bool
+	}{
+		{name: "Escaped star matches a literal star", str: "foo*bar.txt", pattern: `foo\*bar.txt`, want: true},
+		{name: "Escaped star is not a wildcard", str: "foobazbar.txt", pattern: `foo\*bar.txt`, want: false},
+		{name: "Escaped question mark matches a literal question mark", str: "what?.md", pattern: `what\?.md`, want: true},
+		{name: "Escaped question mark is not a wildcard", str: "whats.md", pattern: `what\?.md`, want: false},
+		{name: "Escaped bracket matches a literal bracket", str: "[draft].md", pattern: `\[draft].md`, want: true},
+		{name: "Escaped bracket is not a bracket expression", str: "d.md", pattern: `\[draft].md`, want: false},
+		{name: "Escaped exclamation mark", str: "!keep", pattern: `\!keep`, want: true},
+		{name: "Escaped backslash", str: `back\slash`, pattern: `back\\slash`, want: true},
+		{name: "Trailing backslash matches itself", str: `dir\`, pattern: `dir\`, want: true},
+		{name: "Escaped closing bracket in a bracket expression", str: "a].txt", pattern: `a[\]x].txt`, want: true},
+		{name: "Escaped star in a bracket expression", str: "a*.txt", pattern: `a[\*].txt`, want: true},
+		{name: "Escaped range bounds", str: "b.txt", pattern: `[\a-\c].txt`, want: true},
+		{name: "Escape combined with wildcards", str: "/logs/app*2024.log", pattern: `app\**.log`, want: true},
+		{name: "Escape combined with globstar", str: "/src/deep/foo*bar.txt", pattern: `src/**/foo\*bar.txt`, want: true},
+		{name: "Escape combined with globstar does not match wildcards", str: "/src/deep/fooXbar.txt", pattern: `src/**/foo\*bar.txt`, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PatternMatch(tt.str, tt.pattern); got != tt.want {
+				t.Errorf("PatternMatch(%q, %q) = %v, want %v", tt.str, tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+// fuzzPaths are matched by every pattern of the fuzzer, along with the path of the input, to check the compiled
+// matcher keeps no state from one path to the next
+var fuzzPaths = []string{"", "/", "/src/main.go", "/web/node_modules/react/index.js", "/a/b/c/d/e/f", "file.txt"}
+
+// literalPattern escapes the metacharacters of the path, so the pattern anchored to the root only matches the path itself
+func literalPattern(path string) string {
+	var pattern strings.Builder
+	pattern.WriteString(pathSeparator)
+	for _, r := range path {
+		if strings.ContainsRune(`*?[\`, r) {
+			pattern.WriteRune(escape)
+		}
+		pattern.WriteRune(r)
+	}
+	return pattern.String()
+}
+
+func FuzzPatternMatch(f *testing.F) {
+	seeds := []struct{ str, pattern string }{
+		{"/src/main.go", "*.go"},
+		{"/web/node_modules/react/index.js", "**/node_modules/**"},
+		{"/src/app/app.test.js", "src/**/*.test.js"},
+		{"/build/lib/libfoo.a", "*.[oa]"},
+		{"/docs/[draft].md", "[draft"},
+		{"/docs/[draft].md", "*.["},
+		{"/a]b", "[]]b"},
+		{"/a", "[!"},
+		{"/a", "[^"},
+		{"/7", "[[:digit:"},
+		{"/7", "[[:digit:]]"},
+		{"/7", "[[:nope:]]"},
+		{"/a-b", "[a-]"},
+		{"/z", "[z-a]"},
+		{`/foo\`, `foo\`},
+		{"/foo*bar", `foo\*bar`},
+		{"/a/b/c/d", "**/**/**"},
+		{"/a/b/c/d", "/**/"},
+		{"/main.go", "*****.go"},
+		{"/" + strings.Repeat("a", 40), strings.Repeat("a*", 20) + "b"},
+		{"/" + strings.Repeat("a/", 20), strings.Repeat("**/", 20) + "b"},
+		{"/Thumbs.db", "thumbs.DB"},
+		{"/été/naïve.txt", "*/na?ve.[a-z]xt"},
+		{"", ""},
+		{"/", "/"},
+	}
+	for _, seed := range seeds {
+		f.Add(seed.str, seed.pattern)
+	}
+	f.Fuzz(func(t *testing.T, str, pattern string) {
+		m, err := Compile(pattern)
+		if err != nil {
+			if PatternMatch(str, pattern) {
+				t.Errorf("PatternMatch(%q, %q) = true, want false for a bad pattern", str, pattern)
+			}
+			return
+		}
+		want := PatternMatch(str, pattern)
+		for _, path := range append(fuzzPaths, str) {
+			if got, direct := m.Match(path), PatternMatch(path, pattern); got != direct {
+				t.Errorf("Compile(%q).Match(%q) = %v, PatternMatch() = %v, want them equal", pattern, path, got, direct)
+			}
+		}
+		if got := m.Match(str); got != want {
+			t.Errorf("Compile(%q).Match(%q) = %v after matching other paths, want %v", pattern, str, got, want)
+		}
+		if _, err := CompileCase(pattern); err != nil {
+			t.Errorf("CompileCase(%q) error = %v, Compile() accepted it", pattern, err)
+		}
+		PatternMatchCase(str, pattern)
+
+		if !utf8.ValidString(str) || strings.HasPrefix(str, pathSeparator) {
+			return
+		}
+		if literal := literalPattern(str); !PatternMatch(str, literal) {
+			t.Errorf("PatternMatch(%q, %q) = false, want the escaped path to match itself", str, literal)
+		}
+		if !PatternMatch(str, "/**") {
+			t.Errorf("PatternMatch(%q, \"/**\") = false, want true", str)
+		}
+	})
+}
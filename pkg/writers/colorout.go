@@ -0,0 +1,128 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// WriteColorConsole prints the hits grouped by file followed by a summary, colorizing the severities when the output is a terminal
+func WriteColorConsole(hits <-chan scan.Hit, fileName string, filesScanned int, showFullLine bool) error {
+	if fileName == "" {
+		return writeGroupedConsole(os.Stdout, hits, useColor(os.Stdout), filesScanned, showFullLine)
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := writeGroupedConsole(f, hits, false, filesScanned, showFullLine); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// useColor reports if the output is a terminal and colors weren't disabled with the NO_COLOR environment variable, see https://no-color.org
+func useColor(w io.Writer) bool {
+	if os.Getenv(noColorEnv) != "" {
+		return false
+	}
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// writeGroupedConsole prints the hits of each file together, in the order the files were found
+func writeGroupedConsole(w io.Writer, hits <-chan scan.Hit, color bool, filesScanned int, showFullLine bool) error {
+	var files []string
+	fileHits := make(map[string][]scan.Hit)
+	for hit := range hits {
+		if _, ok := fileHits[hit.Filename]; !ok {
+			files = append(files, hit.Filename)
+		}
+		fileHits[hit.Filename] = append(fileHits[hit.Filename], hit)
+	}
+
+	out := bufio.NewWriter(w)
+	type severityCount struct {
+		name  string
+		id    int
+		count int
+	}
+	var severities []*severityCount
+	counts := make(map[string]*severityCount)
+	total := 0
+	for _, file := range files {
+		fmt.Fprintln(out, colorize(color, ansiBold, file))
+		for _, hit := range fileHits[file] {
+			severity := strings.ToUpper(hit.Severity)
+			fmt.Fprintf(out, "  %s %d %s (line %d)\n", colorize(color, severityColor(hit.Severity), "["+severity+"]"), hit.Code, hit.Caption, hit.Line)
+			fmt.Fprintf(out, "      %s: %s\n", columnValue, printableASCII(hit.MatchValue))
+			if showFullLine {
+				fmt.Fprintf(out, "      %s: %s\n", columnLineValue, printableASCII(hit.LineValue))
+			}
+			fmt.Fprintf(out, "      %s: %s  %s: %s\n", columnConfidence, hit.Confidence, columnCWE, displayCWE(hit.CWE))
+
+			if _, ok := counts[hit.Severity]; !ok {
+				counts[hit.Severity] = &severityCount{name: hit.Severity, id: hit.SeverityID}
+				severities = append(severities, counts[hit.Severity])
+			}
+			counts[hit.Severity].count++
+			total++
+		}
+		fmt.Fprintln(out)
+	}
+
+	//Print out our findings summary, from the most to the least severe
+	sort.SliceStable(severities, func(i, j int) bool { return severities[i].id < severities[j].id })
+	fmt.Fprintln(out, outputTotalIssuesFnd)
+	for _, severity := range severities {
+		fmt.Fprintf(out, "\t%5d %s\n", severity.count, colorize(color, severityColor(severity.name), severity.name))
+	}
+	fmt.Fprintf(out, outputTotalIssues, total)
+	fmt.Fprintf(out, outputFilesSummary, len(files), filesScanned)
+	return out.Flush()
+}
+
+// severityColor is red for critical and high, yellow for medium and blue for the rest
+func severityColor(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return ansiRed
+	case "medium":
+		return ansiYellow
+	default:
+		return ansiBlue
+	}
+}
+
+func colorize(color bool, code, s string) string {
+	if !color {
+		return s
+	}
+	return code + s + ansiReset
+}
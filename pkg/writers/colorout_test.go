@@ -0,0 +1,89 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// colorTestHits sends findings of two files, interleaved
+func colorTestHits() <-chan scan.Hit {
+	hits := make(chan scan.Hit)
+	go func() {
+		defer close(hits)
+		hits <- scan.Hit{Code: 3001, Filename: "config/settings.py", Caption: "Potential password in file", Line: 12, Severity: "high", SeverityID: 2, Confidence: "high"}
+		hits <- scan.Hit{Code: 4001, Filename: "README.md", Caption: "Non-inclusive language", Line: 7, Severity: "low", SeverityID: 4, Confidence: "medium"}
+		hits <- scan.Hit{Code: 3002, Filename: "config/settings.py", Caption: "Potential secret", Line: 20, Severity: "medium", SeverityID: 3, Confidence: "high"}
+	}()
+	return hits
+}
+
+func Test_writeGroupedConsole(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeGroupedConsole(&out, colorTestHits(), useColor(&out), 10, false); err != nil {
+		t.Fatalf("writeGroupedConsole() err = %v", err)
+	}
+	got := out.String()
+	if strings.Contains(got, "\x1b[") {
+		t.Errorf("writeGroupedConsole() = %q, printed escape codes to a writer which isn't a terminal", got)
+	}
+	// The findings of each file are printed together
+	settings, readme := strings.Index(got, "config/settings.py\n"), strings.Index(got, "README.md\n")
+	if settings < 0 || readme < 0 || settings > readme || strings.Index(got, "3002") > readme {
+		t.Errorf("writeGroupedConsole() = %s, want the findings grouped by file", got)
+	}
+	for _, want := range []string{"  [HIGH] 3001 Potential password in file (line 12)", "\t    1 high\n\t    1 medium\n\t    1 low\n", "3 TOTAL ISSUES", "2 of 10 scanned files with findings"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("writeGroupedConsole() = %s, want it to contain %q", got, want)
+		}
+	}
+}
+
+func Test_writeGroupedConsoleColor(t *testing.T) {
+	var out bytes.Buffer
+	if err := writeGroupedConsole(&out, colorTestHits(), true, 10, false); err != nil {
+		t.Fatalf("writeGroupedConsole() err = %v", err)
+	}
+	for _, want := range []string{ansiRed + "[HIGH]" + ansiReset, ansiYellow + "[MEDIUM]" + ansiReset, ansiBlue + "[LOW]" + ansiReset} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeGroupedConsole() = %q, want it to contain %q", out.String(), want)
+		}
+	}
+}
+
+func Test_useColor(t *testing.T) {
+	f, err := os.CreateTemp(t.TempDir(), "report")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if useColor(f) {
+		t.Errorf("useColor() = true for a regular file")
+	}
+	if useColor(&bytes.Buffer{}) {
+		t.Errorf("useColor() = true for a buffer")
+	}
+	t.Setenv(noColorEnv, "1")
+	if useColor(os.Stdout) {
+		t.Errorf("useColor() = true with %s set", noColorEnv)
+	}
+}
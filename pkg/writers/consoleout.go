@@ -26,6 +26,7 @@ import (
 	"strings"
 
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
 )
 
 type issue struct {
@@ -41,15 +42,21 @@ func WriteConsole(hits <-chan scan.Hit, fileName string, showFullLine bool) erro
 	if fileName == "" {
 		// Store a record of the number of each threat found
 		i := 1
+		var suppressed []scan.Hit
 		for hit := range hits {
+			if hit.Suppressed {
+				suppressed = append(suppressed, hit)
+				continue
+			}
 			fmt.Println(hitToConsole(hit, i, showFullLine))
 			issues[hit.Caption]++
 			i++
 		}
+		displaySuppressed(suppressed, showFullLine)
 	} else {
 		err := hitsToFile(hits, fileName, showFullLine)
 		if err != nil {
-			log.Fatal("Failed to write results to file", err)
+			utils.Fatal("Failed to write results to file", err)
 		}
 	}
 	displayIssues()
@@ -72,7 +79,12 @@ func hitsToFile(hits <-chan scan.Hit, fileName string, showFullLine bool) error
 		f.Close()
 	}()
 
+	var suppressed []scan.Hit
 	for hit := range hits {
+		if hit.Suppressed {
+			suppressed = append(suppressed, hit)
+			continue
+		}
 		_, err := writer.WriteString(hitToConsole(hit, i, showFullLine))
 		if err != nil {
 			return err
@@ -81,6 +93,16 @@ func hitsToFile(hits <-chan scan.Hit, fileName string, showFullLine bool) error
 		issues[hit.Caption]++
 		i++
 	}
+	if len(suppressed) > 0 {
+		if _, err := writer.WriteString(outputSuppressed + "\n"); err != nil {
+			return err
+		}
+		for i, hit := range suppressed {
+			if _, err := writer.WriteString(hitToConsole(hit, i+1, showFullLine)); err != nil {
+				return err
+			}
+		}
+	}
 
 	//Get actual file stats for file size
 	fi, err := f.Stat()
@@ -88,10 +110,21 @@ func hitsToFile(hits <-chan scan.Hit, fileName string, showFullLine bool) error
 		log.Println(err)
 		return err
 	}
-	fmt.Println(fi.Size(), outputBytesWritten, fileName)
+	utils.InfoLog.Println(fi.Size(), outputBytesWritten, fileName)
 	return nil
 }
 
+// displaySuppressed lists the findings disabled with an inline comment, which only reach the writer in verbose mode
+func displaySuppressed(suppressed []scan.Hit, showFullLine bool) {
+	if len(suppressed) == 0 {
+		return
+	}
+	fmt.Println(outputSuppressed)
+	for i, hit := range suppressed {
+		fmt.Println(hitToConsole(hit, i+1, showFullLine))
+	}
+}
+
 func displayIssues() {
 	//Sort out values
 	keyvals := make([]issue, 0, len(issues))
@@ -137,6 +170,9 @@ func hitToConsole(hit scan.Hit, progress int, showFullLine bool) string {
 	if hit.Solution != "" {
 		sb.WriteString(outputIndent + columnSolution + ": " + hit.Solution)
 	}
+	if hit.Commit != nil {
+		sb.WriteString(outputIndent + columnCommit + ": " + hit.Commit.Hash + " " + hit.Commit.Author + " " + hit.Commit.Date)
+	}
 	sb.WriteString("\n")
 	return sb.String()
 }
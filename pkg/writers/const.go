@@ -31,9 +31,23 @@ const (
 	columnLabels         string = "Labels"
 	columnCWE            string = "Associated CWEs"
 	columnSolution       string = "Solution"
+	columnCommit         string = "Commit"
 	outputTotalIssuesFnd string = "\t***** Total issues found *****"
 	outputTotalIssues    string = "\t%5d TOTAL ISSUES\n"
 	outputBytesWritten   string = " bytes written to "
 	outputIndent         string = "\n\t"
 	outputNone           string = "None"
+	outputSuppressed     string = "\t***** Suppressed findings *****"
+	outputFilesSummary   string = "\t%5d of %d scanned files with findings\n"
+	noColorEnv           string = "NO_COLOR"
+	ansiReset            string = "\x1b[0m"
+	ansiBold             string = "\x1b[1m"
+	ansiRed              string = "\x1b[31m"
+	ansiYellow           string = "\x1b[33m"
+	ansiBlue             string = "\x1b[34m"
+	sarifSchema          string = "https://json.schemastore.org/sarif-2.1.0.json"
+	sarifVersion         string = "2.1.0"
+	toolName             string = "earlybird"
+	sonarIssueType       string = "VULNERABILITY"
+	sarifToolURI         string = "https://github.com/americanexpress/earlybird"
 )
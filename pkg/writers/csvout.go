@@ -17,13 +17,13 @@
 package writers
 
 import (
-	"fmt"
 	"io"
 	"os"
 
 	"github.com/gocarina/gocsv"
 
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
 )
 
 //WriteCSV outputs Earlybird hit findings from the worker channel to files or console
@@ -57,7 +57,7 @@ func WriteCSV(hits <-chan scan.Hit, fileName string) (err error) {
 	if err != nil {
 		return err
 	}
-	fmt.Println(fi.Size(), " bytes written to ", fileName)
+	utils.InfoLog.Println(fi.Size(), " bytes written to ", fileName)
 	return nil
 }
 
@@ -0,0 +1,87 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// githubLevels maps the Earlybird severities to the GitHub Actions annotation levels
+var githubLevels = map[string]string{
+	"critical": "error",
+	"high":     "error",
+	"medium":   "warning",
+	"low":      "notice",
+	"info":     "notice",
+}
+
+// githubDataEscaper escapes the message of a workflow command, see https://docs.github.com/en/actions/using-workflows/workflow-commands-for-github-actions
+var githubDataEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A")
+
+// githubPropertyEscaper escapes the properties of a workflow command, which are also delimited by the commas and the colons
+var githubPropertyEscaper = strings.NewReplacer("%", "%25", "\r", "%0D", "\n", "%0A", ":", "%3A", ",", "%2C")
+
+// WriteGitHub outputs the hits as GitHub Actions workflow commands, which annotate the files of the pull requests
+func WriteGitHub(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileName string) (err error) {
+	if fileName == "" {
+		return hitsToGitHub(hits, config, os.Stdout)
+	}
+
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return hitsToGitHub(hits, config, f)
+}
+
+func hitsToGitHub(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, output io.Writer) error {
+	w := bufio.NewWriter(output)
+	for hit := range hits {
+		if _, err := w.WriteString(githubCommand(hit, config.SearchDir) + "\n"); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// githubCommand formats the hit as a workflow command, file name findings don't have a line
+func githubCommand(hit scan.Hit, searchDir string) string {
+	var command strings.Builder
+	command.WriteString("::" + githubLevel(hit.Severity) + " file=" + githubPropertyEscaper.Replace(scan.RelativeFileName(hit.Filename, searchDir)))
+	if hit.Line > 0 {
+		command.WriteString(",line=" + strconv.Itoa(hit.Line))
+	}
+	command.WriteString(",title=" + githubPropertyEscaper.Replace("Earlybird "+strconv.Itoa(hit.Code)+" "+hit.Caption))
+	command.WriteString("::" + githubDataEscaper.Replace(hit.Caption+" ("+hit.Severity+" severity, "+hit.Confidence+" confidence)"))
+	return command.String()
+}
+
+// githubLevel maps the severity of the hit to an annotation level, unknown severities are reported as notices
+func githubLevel(severity string) string {
+	if level, ok := githubLevels[severity]; ok {
+		return level
+	}
+	return githubLevels["info"]
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+func TestWriteGitHub(t *testing.T) {
+	hits := make(chan scan.Hit)
+	go func() {
+		defer close(hits)
+		for _, hit := range []scan.Hit{
+			{Code: 1001, Filename: "/builds/app/config/aws.env", Caption: "Potential AWS key", Line: 2, Severity: "critical", Confidence: "high"},
+			{Code: 2002, Filename: "/builds/app/certs/server,old.pem", Caption: "Potential cryptographic key bundle", Severity: "medium", Confidence: "medium"},
+			{Code: 5001, Filename: "/builds/app/main.go", Caption: "Commented out code: 100%", Line: 40, Severity: "low", Confidence: "low"},
+		} {
+			hits <- hit
+		}
+	}()
+
+	output := path.Join(t.TempDir(), "github.txt")
+	if err := WriteGitHub(hits, cfgReader.EarlybirdConfig{SearchDir: "/builds/app"}, output); err != nil {
+		t.Fatalf("WriteGitHub() err = %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "::error file=config/aws.env,line=2,title=Earlybird 1001 Potential AWS key::Potential AWS key (critical severity, high confidence)\n" +
+		"::warning file=certs/server%2Cold.pem,title=Earlybird 2002 Potential cryptographic key bundle::Potential cryptographic key bundle (medium severity, medium confidence)\n" +
+		"::notice file=main.go,line=40,title=Earlybird 5001 Commented out code%3A 100%25::Commented out code: 100%25 (low severity, low confidence)\n"
+	if string(got) != want {
+		t.Errorf("WriteGitHub() = %s, want %s", got, want)
+	}
+}
+
+func Test_githubLevel(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{severity: "critical", want: "error"},
+		{severity: "high", want: "error"},
+		{severity: "medium", want: "warning"},
+		{severity: "low", want: "notice"},
+		{severity: "info", want: "notice"},
+		{severity: "unknown", want: "notice"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			if got := githubLevel(tt.severity); got != tt.want {
+				t.Errorf("githubLevel() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
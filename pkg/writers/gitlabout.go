@@ -0,0 +1,88 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"strconv"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// gitlabSeverities maps the Earlybird severities to the GitLab Code Quality severities
+var gitlabSeverities = map[string]string{
+	"critical": "blocker",
+	"high":     "critical",
+	"medium":   "major",
+	"low":      "minor",
+	"info":     "info",
+}
+
+// gitlabIssue is an issue of the GitLab Code Quality report, see https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+type gitlabIssue struct {
+	Description string         `json:"description"`
+	CheckName   string         `json:"check_name"`
+	Fingerprint string         `json:"fingerprint"`
+	Severity    string         `json:"severity"`
+	Location    gitlabLocation `json:"location"`
+}
+
+type gitlabLocation struct {
+	Path  string      `json:"path"`
+	Lines gitlabLines `json:"lines"`
+}
+
+type gitlabLines struct {
+	Begin int `json:"begin"`
+}
+
+// WriteGitLab outputs the hits as a GitLab Code Quality report to the file or console
+func WriteGitLab(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileName string) (err error) {
+	_, err = reportToJSONWriter(hitsToGitLab(hits, config), fileName)
+	return err
+}
+
+func hitsToGitLab(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig) []gitlabIssue {
+	issues := []gitlabIssue{}
+	for hit := range hits {
+		// File name findings don't have a line, GitLab requires one so they're reported on the first line
+		line := hit.Line
+		if line < 1 {
+			line = 1
+		}
+		issues = append(issues, gitlabIssue{
+			Description: hit.Caption,
+			CheckName:   toolName + "-" + strconv.Itoa(hit.Code),
+			// The baseline fingerprint doesn't change when lines move, so GitLab tracks the finding until it's resolved
+			Fingerprint: scan.Fingerprint(hit, config.SearchDir),
+			Severity:    gitlabSeverity(hit.Severity),
+			Location: gitlabLocation{
+				Path:  scan.RelativeFileName(hit.Filename, config.SearchDir),
+				Lines: gitlabLines{Begin: line},
+			},
+		})
+	}
+	return issues
+}
+
+// gitlabSeverity maps the severity of the hit to a GitLab severity, unknown severities are reported as info
+func gitlabSeverity(severity string) string {
+	if gitlabSeverity, ok := gitlabSeverities[severity]; ok {
+		return gitlabSeverity
+	}
+	return gitlabSeverities["info"]
+}
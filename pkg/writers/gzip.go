@@ -0,0 +1,55 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+)
+
+// GzipExtension is the extension of the report files compressed with gzip by the JSON writers
+const GzipExtension = ".gz"
+
+// gzipFile compresses what's written to the file, closing it flushes the compressed stream before closing the file
+type gzipFile struct {
+	*gzip.Writer
+	file *os.File
+}
+
+// Close completes the gzip stream, so the file is valid, then closes the file
+func (g *gzipFile) Close() error {
+	err := g.Writer.Close()
+	if closeErr := g.file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// createReportFile creates the report file, the report written to it is compressed with gzip when the file name ends
+// with GzipExtension, e.g. report.json.gz
+func createReportFile(fileName string) (io.WriteCloser, error) {
+	f, err := os.Create(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(fileName, GzipExtension) {
+		return f, nil
+	}
+	return &gzipFile{Writer: gzip.NewWriter(f), file: f}, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// gzipTestHits are the findings of the gzipped reports
+var gzipTestHits = []scan.Hit{
+	{Code: 3001, Filename: "/builds/app/config/settings.py", Line: 12, Caption: "Password", Severity: "high", Confidence: "high"},
+	{Code: 2002, Filename: "/builds/app/certs/server.pem", Caption: "Private key", Severity: "medium", Confidence: "medium"},
+}
+
+// sendHits sends the hits to the channel returned, closing it after the last one
+func sendHits(hits []scan.Hit) chan scan.Hit {
+	hitChannel := make(chan scan.Hit)
+	go func() {
+		defer close(hitChannel)
+		for _, hit := range hits {
+			hitChannel <- hit
+		}
+	}()
+	return hitChannel
+}
+
+// readGzip decompresses the file, failing the test when it isn't a complete gzip stream
+func readGzip(t *testing.T, fileName string) []byte {
+	t.Helper()
+	f, err := os.Open(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	reader, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("%s isn't gzipped: %v", fileName, err)
+	}
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("%s is truncated: %v", fileName, err)
+	}
+	return content
+}
+
+func TestWriteJSONGzip(t *testing.T) {
+	output := path.Join(t.TempDir(), "report.json.gz")
+	if err := WriteJSON(sendHits(gzipTestHits), cfgReader.EarlybirdConfig{Version: "4.2.0"}, summaryContext, output); err != nil {
+		t.Fatalf("WriteJSON() err = %v", err)
+	}
+	var report scan.Report
+	if err := json.Unmarshal(readGzip(t, output), &report); err != nil {
+		t.Fatalf("WriteJSON() wrote an invalid JSON report: %v", err)
+	}
+	if !reflect.DeepEqual(report.Hits, gzipTestHits) || report.HitCount != len(gzipTestHits) || report.Version != "4.2.0" {
+		t.Errorf("WriteJSON() report = %+v, want the hits %+v", report, gzipTestHits)
+	}
+}
+
+func TestWriteJSONUncompressed(t *testing.T) {
+	output := path.Join(t.TempDir(), "report.json")
+	if err := WriteJSON(sendHits(gzipTestHits), cfgReader.EarlybirdConfig{}, summaryContext, output); err != nil {
+		t.Fatalf("WriteJSON() err = %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !json.Valid(content) {
+		t.Errorf("WriteJSON() = %s, want a plain JSON report", content)
+	}
+}
+
+func TestWriteNDJSONGzip(t *testing.T) {
+	output := path.Join(t.TempDir(), "findings.ndjson.gz")
+	if err := WriteNDJSON(sendHits(gzipTestHits), output); err != nil {
+		t.Fatalf("WriteNDJSON() err = %v", err)
+	}
+	var got []scan.Hit
+	scanner := bufio.NewScanner(bytes.NewReader(readGzip(t, output)))
+	for scanner.Scan() {
+		var hit scan.Hit
+		if err := json.Unmarshal(scanner.Bytes(), &hit); err != nil {
+			t.Fatalf("WriteNDJSON() line %s isn't a finding: %v", scanner.Bytes(), err)
+		}
+		got = append(got, hit)
+	}
+	if !reflect.DeepEqual(got, gzipTestHits) {
+		t.Errorf("WriteNDJSON() = %+v, want %+v", got, gzipTestHits)
+	}
+}
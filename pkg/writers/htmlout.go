@@ -0,0 +1,154 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"embed"
+	"html/template"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+//go:embed templates/report.html.tmpl
+var templates embed.FS
+
+var reportTemplate = template.Must(template.ParseFS(templates, "templates/report.html.tmpl"))
+
+// htmlReport is the data of the HTML report, the findings are grouped by severity and then by file
+type htmlReport struct {
+	Version     string
+	Generated   string
+	Total       int
+	Confidences []string
+	Severities  []htmlSeverity
+}
+
+type htmlSeverity struct {
+	Name  string
+	id    int
+	Count int
+	// ConfidenceCounts are the number of findings for each of the report Confidences
+	ConfidenceCounts []int
+	Files            []htmlFile
+}
+
+type htmlFile struct {
+	Name     string
+	Findings []htmlFinding
+}
+
+type htmlFinding struct {
+	Code                                 int
+	Line                                 int
+	Caption, Category, Confidence, Value string
+	CWE, Solution                        string
+	Context                              []scan.ContextLine
+}
+
+// WriteHTML outputs the hits as a self-contained HTML report to the file or console
+func WriteHTML(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileName string) error {
+	report := hitsToHTML(hits)
+	report.Version = config.Version
+	report.Generated = time.Now().UTC().Format(time.RFC3339)
+	if fileName == "" {
+		return renderHTML(os.Stdout, report)
+	}
+	f, err := os.Create(fileName)
+	if err != nil {
+		return err
+	}
+	if err := renderHTML(f, report); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func renderHTML(w io.Writer, report htmlReport) error {
+	return reportTemplate.Execute(w, report)
+}
+
+// hitsToHTML groups the hits by severity, from the most to the least severe, then by file in the order they were found
+func hitsToHTML(hits <-chan scan.Hit) (report htmlReport) {
+	type confidence struct {
+		name string
+		id   int
+	}
+	var confidences []confidence
+	seenConfidences := make(map[string]bool)
+	severities := make(map[string]*htmlSeverity)
+	fileIndex := make(map[string]map[string]int) //Severity:Filename:index in the files
+	counts := make(map[string]map[string]int)    //Severity:Confidence:count
+
+	for hit := range hits {
+		severity, ok := severities[hit.Severity]
+		if !ok {
+			severity = &htmlSeverity{Name: hit.Severity, id: hit.SeverityID}
+			severities[hit.Severity] = severity
+			fileIndex[hit.Severity] = make(map[string]int)
+			counts[hit.Severity] = make(map[string]int)
+		}
+		if !seenConfidences[hit.Confidence] {
+			seenConfidences[hit.Confidence] = true
+			confidences = append(confidences, confidence{name: hit.Confidence, id: hit.ConfidenceID})
+		}
+
+		index, ok := fileIndex[hit.Severity][hit.Filename]
+		if !ok {
+			index = len(severity.Files)
+			fileIndex[hit.Severity][hit.Filename] = index
+			severity.Files = append(severity.Files, htmlFile{Name: hit.Filename})
+		}
+		severity.Files[index].Findings = append(severity.Files[index].Findings, htmlFinding{
+			Code:       hit.Code,
+			Line:       hit.Line,
+			Caption:    hit.Caption,
+			Category:   hit.Category,
+			Confidence: hit.Confidence,
+			// The secret was already masked by the scan, unless it's shown
+			Value:    hit.MatchValue,
+			CWE:      strings.Join(hit.CWE, ", "),
+			Solution: hit.Solution,
+			Context:  hit.Context,
+		})
+		severity.Count++
+		counts[hit.Severity][hit.Confidence]++
+		report.Total++
+	}
+
+	sort.SliceStable(confidences, func(i, j int) bool { return confidences[i].id < confidences[j].id })
+	for _, c := range confidences {
+		report.Confidences = append(report.Confidences, c.name)
+	}
+	for _, severity := range severities {
+		for _, c := range confidences {
+			severity.ConfidenceCounts = append(severity.ConfidenceCounts, counts[severity.Name][c.name])
+		}
+		report.Severities = append(report.Severities, *severity)
+	}
+	sort.Slice(report.Severities, func(i, j int) bool {
+		a, b := report.Severities[i], report.Severities[j]
+		return a.id < b.id || a.id == b.id && a.Name < b.Name
+	})
+	return report
+}
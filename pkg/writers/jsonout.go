@@ -29,17 +29,25 @@ import (
 // WriteJSON takes the hits, converts them into JSON report and passing report to reportToJSONWriter().
 func WriteJSON(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileContext file.Context, fileName string) (err error) {
 	start := time.Now()
-	var Hits []scan.Hit
+	var Hits, Suppressed []scan.Hit
 	for hit := range hits {
+		if hit.Suppressed {
+			Suppressed = append(Suppressed, hit)
+			continue
+		}
 		Hits = append(Hits, hit)
 	}
 
+	summary := fileContext.Summary(Hits)
 	report := scan.Report{
 		Hits:          Hits,
 		HitCount:      len(Hits),
+		Suppressed:    Suppressed,
 		Skipped:       fileContext.SkippedFiles,
 		Ignore:        fileContext.IgnorePatterns,
 		Version:       config.Version,
+		Commit:        config.Commit,
+		BuildDate:     config.BuildDate,
 		Modules:       config.EnabledModules,
 		Threshold:     config.SeverityDisplayLevel,
 		FilesScanned:  len(fileContext.Files),
@@ -47,12 +55,14 @@ func WriteJSON(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileConte
 		StartTime:     start.UTC().Format(time.RFC3339),
 		EndTime:       time.Now().UTC().Format(time.RFC3339),
 		Duration:      fmt.Sprintf("%d ms", time.Since(start)/time.Millisecond),
+		Summary:       &summary,
 	}
 	_, err = reportToJSONWriter(report, fileName)
 	return err
 }
 
-//reportToJSONWriter Outputs an object as a JSON blob to an output file or console
+//reportToJSONWriter Outputs an object as a JSON blob to an output file or console, the file is compressed with gzip when
+//its name ends with .gz
 func reportToJSONWriter(v interface{}, fileName string) (s string, err error) {
 	b, err := json.MarshalIndent(v, "", "\t")
 	if err != nil {
@@ -60,9 +70,15 @@ func reportToJSONWriter(v interface{}, fileName string) (s string, err error) {
 	}
 	if fileName == "" {
 		_, err = os.Stdout.Write(b)
-	} else {
-		err = os.WriteFile(fileName, b, 0666)
+		return string(b), err
 	}
-	return string(b), err
-
+	reportFile, err := createReportFile(fileName)
+	if err != nil {
+		return string(b), err
+	}
+	if _, err = reportFile.Write(b); err != nil {
+		reportFile.Close()
+		return string(b), err
+	}
+	return string(b), reportFile.Close()
 }
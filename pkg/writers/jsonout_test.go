@@ -19,12 +19,30 @@ package writers
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/file"
 	"github.com/americanexpress/earlybird/v4/pkg/scan"
 )
 
+// summaryContext is a scan of four files, one of which was skipped as binary, with two files skipped before the scan
+var summaryContext = file.Context{
+	Files: []scan.File{
+		{Name: "settings.py", Path: "/builds/app/config/settings.py"},
+		{Name: "server.pem", Path: "/builds/app/certs/server.pem"},
+		{Name: "deploy.sh", Path: "/builds/app/deploy.sh"},
+		{Name: "logo.img", Path: "/builds/app/logo.img", Skipped: "binary"},
+	},
+	SkippedFiles: []string{"/builds/app/.env.local", "/builds/app/dump.csv"},
+	SkipReasons:  map[string]string{"/builds/app/.env.local": "ignored", "/builds/app/dump.csv": "too_large"},
+}
+
 func TestWriteJSON(t *testing.T) {
 	start := time.Now()
 	Hits := []scan.Hit{
@@ -74,3 +92,136 @@ func TestWriteJSON(t *testing.T) {
 		})
 	}
 }
+
+func TestWriteJSONLocations(t *testing.T) {
+	tests := []struct {
+		name          string
+		hit           scan.Hit
+		wantLocations bool
+	}{
+		{
+			name: "Locations are left out by default",
+			hit:  scan.Hit{Code: 3001, Filename: "settings.py", Line: 12},
+		},
+		{
+			name: "Locations of collapsed duplicates",
+			hit: scan.Hit{Code: 3001, Filename: "settings.py", Line: 12, Locations: []scan.Location{
+				{Filename: "settings.py", Line: 12},
+				{Filename: "local_settings.py", Line: 4},
+			}},
+			wantLocations: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := reportToJSONWriter(scan.Report{Hits: []scan.Hit{tt.hit}, HitCount: 1}, t.TempDir()+"/report.json")
+			if err != nil {
+				t.Fatalf("reportToJSONWriter() err = %v", err)
+			}
+			var report struct {
+				Hits []map[string]json.RawMessage `json:"hits"`
+			}
+			if err := json.Unmarshal([]byte(got), &report); err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := report.Hits[0]["locations"]; ok != tt.wantLocations {
+				t.Errorf("reportToJSONWriter() = %s, want locations %v", got, tt.wantLocations)
+			}
+		})
+	}
+}
+
+func TestWriteJSONContext(t *testing.T) {
+	hit := scan.Hit{Code: 3001, Filename: "settings.py", Line: 2, Context: []scan.ContextLine{
+		{Line: 1, Value: "import os"},
+		{Line: 2, Value: "***************", Match: true},
+	}}
+	got, err := reportToJSONWriter(scan.Report{Hits: []scan.Hit{hit}, HitCount: 1}, t.TempDir()+"/report.json")
+	if err != nil {
+		t.Fatalf("reportToJSONWriter() err = %v", err)
+	}
+	var report struct {
+		Hits []struct {
+			Context []scan.ContextLine `json:"context"`
+		} `json:"hits"`
+	}
+	if err := json.Unmarshal([]byte(got), &report); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(report.Hits[0].Context, hit.Context) {
+		t.Errorf("reportToJSONWriter() = %s, want the context %v", got, hit.Context)
+	}
+	if !strings.Contains(got, `"match": true`) {
+		t.Errorf("reportToJSONWriter() = %s, want the line of the hit marked", got)
+	}
+}
+
+
+func TestWriteJSONSummary(t *testing.T) {
+	hits := make(chan scan.Hit)
+	go func() {
+		defer close(hits)
+		for _, hit := range []scan.Hit{
+			{Code: 3001, Filename: "/builds/app/config/settings.py", Line: 12, Severity: "high", Confidence: "high"},
+			{Code: 2002, Filename: "/builds/app/certs/server.pem", Severity: "medium", Confidence: "medium"},
+			{Code: 3001, Filename: "/builds/app/deploy.sh", Line: 3, Severity: "low", Confidence: "high"},
+			// Suppressed findings aren't reported, so they aren't counted
+			{Code: 3001, Filename: "/builds/app/deploy.sh", Line: 4, Severity: "low", Confidence: "high", Suppressed: true},
+		} {
+			hits <- hit
+		}
+	}()
+
+	output := path.Join(t.TempDir(), "report.json")
+	if err := WriteJSON(hits, cfgReader.EarlybirdConfig{}, summaryContext, output); err != nil {
+		t.Fatalf("WriteJSON() err = %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report scan.Report
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("WriteJSON() = %s, want a JSON report", content)
+	}
+	want := &scan.Summary{
+		FilesScanned:    3,
+		FilesSkipped:    3,
+		FilesIncomplete: 2,
+		SkipReasons:     map[string]int{"binary": 1, "ignored": 1, "too_large": 1},
+		Severities:      map[string]int{"high": 1, "medium": 1, "low": 1},
+		Confidences:     map[string]int{"high": 2, "medium": 1},
+	}
+	if !reflect.DeepEqual(report.Summary, want) {
+		t.Errorf("WriteJSON() summary = %+v, want %+v", report.Summary, want)
+	}
+}
+
+func TestWriteJSONSolution(t *testing.T) {
+	hits := make(chan scan.Hit)
+	go func() {
+		defer close(hits)
+		hits <- scan.Hit{Code: 3001, Filename: "settings.py", Line: 12, Solution: "Store the password in a secrets manager"}
+		hits <- scan.Hit{Code: 3002, Filename: "settings.py", Line: 14}
+	}()
+
+	output := path.Join(t.TempDir(), "report.json")
+	if err := WriteJSON(hits, cfgReader.EarlybirdConfig{}, summaryContext, output); err != nil {
+		t.Fatalf("WriteJSON() err = %v", err)
+	}
+	content, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report scan.Report
+	if err := json.Unmarshal(content, &report); err != nil {
+		t.Fatalf("WriteJSON() = %s, want a JSON report", content)
+	}
+	var got []string
+	for _, hit := range report.Hits {
+		got = append(got, hit.Solution)
+	}
+	if want := []string{"Store the password in a secrets manager", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("WriteJSON() solutions = %q, want %q", got, want)
+	}
+}
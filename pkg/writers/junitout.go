@@ -0,0 +1,98 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// junitTestSuites is the root of a JUnit XML report, every finding is a failed test case
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	Name       string           `xml:"name,attr"`
+	Tests      int              `xml:"tests,attr"`
+	Failures   int              `xml:"failures,attr"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+// junitTestSuite groups the findings of a single file
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string       `xml:"name,attr"`
+	ClassName string       `xml:"classname,attr"`
+	Failure   junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// WriteJUnit outputs the hits as a JUnit XML report to the file or console, with a test suite per file
+func WriteJUnit(hits <-chan scan.Hit, fileName string) error {
+	b, err := xml.MarshalIndent(hitsToJUnit(hits), "", "\t")
+	if err != nil {
+		return err
+	}
+	b = append([]byte(xml.Header), b...)
+	if fileName == "" {
+		_, err = os.Stdout.Write(b)
+		return err
+	}
+	return os.WriteFile(fileName, b, 0666)
+}
+
+// hitsToJUnit builds the test suites in the order their files were first found
+func hitsToJUnit(hits <-chan scan.Hit) junitTestSuites {
+	report := junitTestSuites{Name: toolName}
+	suiteIndex := make(map[string]int) //Filename:index in the test suites
+	for hit := range hits {
+		index, ok := suiteIndex[hit.Filename]
+		if !ok {
+			index = len(report.TestSuites)
+			suiteIndex[hit.Filename] = index
+			report.TestSuites = append(report.TestSuites, junitTestSuite{Name: hit.Filename})
+		}
+		suite := &report.TestSuites[index]
+		suite.TestCases = append(suite.TestCases, junitTestCase{
+			Name:      fmt.Sprintf("%d %s (line %d)", hit.Code, hit.Caption, hit.Line),
+			ClassName: hit.Filename,
+			Failure: junitFailure{
+				Message: hit.Caption,
+				Type:    hit.Category,
+				// The secret was already masked by the scan, unless it's shown
+				Text: fmt.Sprintf("%s:%d: %s", hit.Filename, hit.Line, hit.MatchValue),
+			},
+		})
+		suite.Tests++
+		suite.Failures++
+		report.Tests++
+		report.Failures++
+	}
+	return report
+}
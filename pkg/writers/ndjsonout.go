@@ -0,0 +1,67 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+	"github.com/americanexpress/earlybird/v4/pkg/utils"
+)
+
+// WriteNDJSON writes the hits as newline-delimited JSON, one finding per line, as soon as each of them is received, so
+// the consumers can process the findings of a large scan while it's running.  The findings suppressed with an inline
+// comment are left out.  The file is compressed with gzip when its name ends with .gz.
+func WriteNDJSON(hits <-chan scan.Hit, fileName string) (err error) {
+	if fileName == "" {
+		return hitsToNDJSON(hits, os.Stdout)
+	}
+
+	ndjsonFile, err := createReportFile(fileName)
+	if err != nil {
+		return err
+	}
+	if err = hitsToNDJSON(hits, ndjsonFile); err != nil {
+		ndjsonFile.Close()
+		return err
+	}
+	if err = ndjsonFile.Close(); err != nil {
+		return err
+	}
+	fi, err := os.Stat(fileName)
+	if err != nil {
+		return err
+	}
+	utils.InfoLog.Println(fi.Size(), " bytes written to ", fileName)
+	return nil
+}
+
+// hitsToNDJSON encodes each hit on its own line of the output
+func hitsToNDJSON(hits <-chan scan.Hit, output io.Writer) error {
+	encoder := json.NewEncoder(output)
+	for hit := range hits {
+		if hit.Suppressed {
+			continue
+		}
+		if err := encoder.Encode(hit); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+func TestWriteNDJSON(t *testing.T) {
+	tests := []struct {
+		name      string
+		hits      []scan.Hit
+		wantLines int
+	}{
+		{
+			name: "No findings",
+		},
+		{
+			name:      "One finding per line",
+			hits:      []scan.Hit{{Code: 3003, Line: 1, Filename: "sample.py", MatchValue: "tomcat_password = '123'"}, {Code: 3024, Line: 7, Filename: "aws.env", MatchValue: "AKIA****", LineValue: "key = \"AKIA****\"\nnext"}},
+			wantLines: 2,
+		},
+		{
+			name:      "Suppressed findings are left out",
+			hits:      []scan.Hit{{Code: 3003, Line: 1, Filename: "sample.py"}, {Code: 3003, Line: 2, Filename: "sample.py", Suppressed: true}},
+			wantLines: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hits := make(chan scan.Hit)
+			go func() {
+				defer close(hits)
+				for _, hit := range tt.hits {
+					hits <- hit
+				}
+			}()
+			output := path.Join(t.TempDir(), "findings.ndjson")
+			if err := WriteNDJSON(hits, output); err != nil {
+				t.Fatalf("WriteNDJSON() err = %v", err)
+			}
+			got, err := os.ReadFile(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			lines := strings.Split(strings.TrimSuffix(string(got), "\n"), "\n")
+			if len(got) == 0 {
+				lines = nil
+			}
+			if len(lines) != tt.wantLines {
+				t.Fatalf("WriteNDJSON() wrote %d lines, want %d: %s", len(lines), tt.wantLines, got)
+			}
+			for i, line := range lines {
+				var hit scan.Hit
+				if err := json.Unmarshal([]byte(line), &hit); err != nil {
+					t.Errorf("WriteNDJSON() line %d = %s isn't valid JSON: %v", i+1, line, err)
+				}
+				if hit.Code != tt.hits[i].Code || hit.Line != tt.hits[i].Line || hit.Suppressed {
+					t.Errorf("WriteNDJSON() line %d = %+v, want %+v", i+1, hit, tt.hits[i])
+				}
+			}
+		})
+	}
+}
+
+func Test_hitsToNDJSONStreams(t *testing.T) {
+	hits := make(chan scan.Hit)
+	reader, writer := io.Pipe()
+	done := make(chan error)
+	go func() {
+		done <- hitsToNDJSON(hits, writer)
+		writer.Close()
+	}()
+
+	// Each finding is readable before the next one is found
+	lines := bufio.NewScanner(reader)
+	for i := 1; i <= 3; i++ {
+		hits <- scan.Hit{Code: 3003, Line: i, Filename: "sample.py"}
+		if !lines.Scan() {
+			t.Fatalf("hitsToNDJSON() didn't write finding %d: %v", i, lines.Err())
+		}
+		var hit scan.Hit
+		if err := json.Unmarshal(lines.Bytes(), &hit); err != nil || hit.Line != i {
+			t.Errorf("hitsToNDJSON() line = %s, want the finding of line %d (err %v)", lines.Bytes(), i, err)
+		}
+	}
+	close(hits)
+	if err := <-done; err != nil {
+		t.Errorf("hitsToNDJSON() err = %v", err)
+	}
+	if lines.Scan() {
+		t.Errorf("hitsToNDJSON() wrote an extra line %s", lines.Bytes())
+	}
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"strconv"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/file"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// sarifLog is the root of a SARIF 2.1.0 report, see https://docs.oasis-open.org/sarif/sarif/v2.1.0/sarif-v2.1.0.html
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool       sarifTool          `json:"tool"`
+	Results    []sarifResult      `json:"results"`
+	Properties sarifRunProperties `json:"properties"`
+}
+
+// sarifRunProperties holds the summary of the scan
+type sarifRunProperties struct {
+	Summary scan.Summary `json:"summary"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string                 `json:"name"`
+	Version        string                 `json:"version"`
+	InformationURI string                 `json:"informationUri"`
+	Rules          []sarifRule            `json:"rules"`
+	Properties     *sarifDriverProperties `json:"properties,omitempty"`
+}
+
+// sarifDriverProperties identifies the build of Earlybird which ran the scan
+type sarifDriverProperties struct {
+	Commit    string `json:"commit,omitempty"`
+	BuildDate string `json:"buildDate,omitempty"`
+}
+
+type sarifRule struct {
+	ID               string          `json:"id"`
+	ShortDescription sarifMessage    `json:"shortDescription"`
+	FullDescription  *sarifMessage   `json:"fullDescription,omitempty"`
+	Help             *sarifMessage   `json:"help,omitempty"`
+	Properties       sarifProperties `json:"properties"`
+}
+
+type sarifResult struct {
+	RuleID     string          `json:"ruleId"`
+	RuleIndex  int             `json:"ruleIndex"`
+	Level      string          `json:"level"`
+	Message    sarifMessage    `json:"message"`
+	Locations  []sarifLocation `json:"locations"`
+	Properties sarifProperties `json:"properties"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine int `json:"startLine"`
+}
+
+// sarifProperties holds the Earlybird specific values of a rule or result
+type sarifProperties struct {
+	Category   string   `json:"category,omitempty"`
+	Severity   string   `json:"severity,omitempty"`
+	Confidence string   `json:"confidence,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	Tags       []string `json:"tags,omitempty"`
+}
+
+// WriteSARIF outputs the hits as a SARIF report to the file or console, e.g. for GitHub code scanning
+func WriteSARIF(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileContext file.Context, fileName string) (err error) {
+	_, err = reportToJSONWriter(hitsToSARIF(hits, config, fileContext), fileName)
+	return err
+}
+
+// hitsToSARIF builds a single run with a rule for every rule code found, in the order they were first found, and the
+// summary of the scan in the properties of the run
+func hitsToSARIF(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileContext file.Context) sarifLog {
+	driver := sarifDriver{Name: toolName, Version: config.Version, InformationURI: sarifToolURI, Rules: []sarifRule{}}
+	if config.Commit != "" || config.BuildDate != "" {
+		driver.Properties = &sarifDriverProperties{Commit: config.Commit, BuildDate: config.BuildDate}
+	}
+	results := []sarifResult{}
+	var reported []scan.Hit
+	ruleIndex := make(map[int]int) //Code:index in the rules
+	for hit := range hits {
+		reported = append(reported, hit)
+		index, ok := ruleIndex[hit.Code]
+		if !ok {
+			index = len(driver.Rules)
+			ruleIndex[hit.Code] = index
+			rule := sarifRule{
+				ID:               strconv.Itoa(hit.Code),
+				ShortDescription: sarifMessage{Text: hit.Caption},
+				Properties:       sarifProperties{Category: hit.Category, Tags: hit.CWE},
+			}
+			// The solution tells how to fix the findings of the rule, code scanning tools show the help of the rule
+			if hit.Solution != "" {
+				rule.FullDescription = &sarifMessage{Text: hit.Solution}
+				rule.Help = &sarifMessage{Text: hit.Solution}
+			}
+			driver.Rules = append(driver.Rules, rule)
+		}
+
+		location := sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: scan.RelativeFileName(hit.Filename, config.SearchDir)}}
+		// File name findings don't have a line
+		if hit.Line > 0 {
+			location.Region = &sarifRegion{StartLine: hit.Line}
+		}
+		results = append(results, sarifResult{
+			RuleID:    strconv.Itoa(hit.Code),
+			RuleIndex: index,
+			Level:     sarifLevel(hit.Severity),
+			// The match value isn't included, the report may be uploaded to a third party
+			Message:    sarifMessage{Text: hit.Caption},
+			Locations:  []sarifLocation{{PhysicalLocation: location}},
+			Properties: sarifProperties{Severity: hit.Severity, Confidence: hit.Confidence, Labels: hit.Labels},
+		})
+	}
+	return sarifLog{
+		Schema:  sarifSchema,
+		Version: sarifVersion,
+		Runs:    []sarifRun{{Tool: sarifTool{Driver: driver}, Results: results, Properties: sarifRunProperties{Summary: fileContext.Summary(reported)}}},
+	}
+}
+
+// sarifLevel maps the severity of the hit to the level of the result
+func sarifLevel(severity string) string {
+	switch severity {
+	case "critical", "high":
+		return "error"
+	case "medium":
+		return "warning"
+	default:
+		return "note"
+	}
+}
@@ -0,0 +1,155 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"encoding/json"
+	"os"
+	"path"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+func TestWriteSARIF(t *testing.T) {
+	hits := make(chan scan.Hit)
+	go func() {
+		defer close(hits)
+		for _, hit := range []scan.Hit{
+			{
+				Code:       3001,
+				Filename:   "/builds/app/config/settings.py",
+				Caption:    "Potential password in file",
+				Category:   "password",
+				MatchValue: `password = "SecretValue1673"`,
+				Line:       12,
+				Severity:   "high",
+				Confidence: "high",
+				Labels:     []string{"e1"},
+				CWE:        []string{"CWE-798", "CWE-259"},
+			},
+			{
+				Code:       2002,
+				Filename:   "/builds/app/certs/server.pem",
+				Caption:    "Potential cryptographic key bundle",
+				Category:   "key",
+				Severity:   "medium",
+				Confidence: "medium",
+				Solution:   "Store keys in a secrets manager",
+			},
+			{
+				Code:       3001,
+				Filename:   "/builds/app/deploy.sh",
+				Caption:    "Potential password in file",
+				Category:   "password",
+				Line:       3,
+				Severity:   "low",
+				Confidence: "high",
+				CWE:        []string{"CWE-798", "CWE-259"},
+			},
+		} {
+			hits <- hit
+		}
+	}()
+
+	output := path.Join(t.TempDir(), "report.sarif")
+	if err := WriteSARIF(hits, cfgReader.EarlybirdConfig{Version: "4.0.0-test", SearchDir: "/builds/app"}, summaryContext, output); err != nil {
+		t.Fatalf("WriteSARIF() err = %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("test_data/report.sarif")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("WriteSARIF() = %s, want %s", got, want)
+	}
+	validateSARIF(t, got)
+}
+
+// validateSARIF checks the report has the shape and required properties of the SARIF 2.1.0 schema
+func validateSARIF(t *testing.T, report []byte) {
+	t.Helper()
+	var log struct {
+		Schema  string `json:"$schema"`
+		Version string `json:"version"`
+		Runs    []struct {
+			Tool struct {
+				Driver struct {
+					Name  string `json:"name"`
+					Rules []struct {
+						ID string `json:"id"`
+					} `json:"rules"`
+				} `json:"driver"`
+			} `json:"tool"`
+			Results []struct {
+				RuleID    *string `json:"ruleId"`
+				RuleIndex int     `json:"ruleIndex"`
+				Level     string  `json:"level"`
+				Message   struct {
+					Text *string `json:"text"`
+				} `json:"message"`
+				Locations []struct {
+					PhysicalLocation struct {
+						ArtifactLocation struct {
+							URI string `json:"uri"`
+						} `json:"artifactLocation"`
+						Region *struct {
+							StartLine int `json:"startLine"`
+						} `json:"region"`
+					} `json:"physicalLocation"`
+				} `json:"locations"`
+			} `json:"results"`
+		} `json:"runs"`
+	}
+	if err := json.Unmarshal(report, &log); err != nil {
+		t.Fatalf("SARIF report isn't valid JSON: %v", err)
+	}
+	if log.Version != "2.1.0" || log.Schema == "" || len(log.Runs) != 1 {
+		t.Fatalf("SARIF report version = %q, schema = %q, runs = %d, want a single 2.1.0 run", log.Version, log.Schema, len(log.Runs))
+	}
+	run := log.Runs[0]
+	if run.Tool.Driver.Name == "" {
+		t.Errorf("SARIF tool.driver.name is required")
+	}
+	for i, result := range run.Results {
+		if result.Message.Text == nil || result.RuleID == nil {
+			t.Errorf("SARIF result %d is missing its message or ruleId", i)
+			continue
+		}
+		if result.RuleIndex >= len(run.Tool.Driver.Rules) || run.Tool.Driver.Rules[result.RuleIndex].ID != *result.RuleID {
+			t.Errorf("SARIF result %d ruleIndex %d doesn't point to rule %v", i, result.RuleIndex, *result.RuleID)
+		}
+		switch result.Level {
+		case "none", "note", "warning", "error":
+		default:
+			t.Errorf("SARIF result %d level = %q, want none, note, warning or error", i, result.Level)
+		}
+		for _, location := range result.Locations {
+			if location.PhysicalLocation.ArtifactLocation.URI == "" {
+				t.Errorf("SARIF result %d location is missing its uri", i)
+			}
+			if region := location.PhysicalLocation.Region; region != nil && region.StartLine < 1 {
+				t.Errorf("SARIF result %d startLine = %d, want at least 1", i, region.StartLine)
+			}
+		}
+	}
+}
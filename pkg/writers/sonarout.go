@@ -0,0 +1,89 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"strconv"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+// sonarSeverities maps the Earlybird severities to the SonarQube severities
+var sonarSeverities = map[string]string{
+	"critical": "BLOCKER",
+	"high":     "CRITICAL",
+	"medium":   "MAJOR",
+	"low":      "MINOR",
+	"info":     "INFO",
+}
+
+// sonarReport is the SonarQube generic issue import format, see https://docs.sonarqube.org/latest/analyzing-source-code/importing-external-issues/generic-issue-import-format/
+type sonarReport struct {
+	Issues []sonarIssue `json:"issues"`
+}
+
+type sonarIssue struct {
+	EngineID        string        `json:"engineId"`
+	RuleID          string        `json:"ruleId"`
+	Severity        string        `json:"severity"`
+	Type            string        `json:"type"`
+	PrimaryLocation sonarLocation `json:"primaryLocation"`
+}
+
+type sonarLocation struct {
+	Message   string          `json:"message"`
+	FilePath  string          `json:"filePath"`
+	TextRange *sonarTextRange `json:"textRange,omitempty"`
+}
+
+type sonarTextRange struct {
+	StartLine int `json:"startLine"`
+}
+
+// WriteSonarQube outputs the hits as SonarQube generic issues to the file or console
+func WriteSonarQube(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig, fileName string) (err error) {
+	_, err = reportToJSONWriter(hitsToSonarQube(hits, config), fileName)
+	return err
+}
+
+func hitsToSonarQube(hits <-chan scan.Hit, config cfgReader.EarlybirdConfig) sonarReport {
+	report := sonarReport{Issues: []sonarIssue{}}
+	for hit := range hits {
+		location := sonarLocation{Message: hit.Caption, FilePath: scan.RelativeFileName(hit.Filename, config.SearchDir)}
+		// File name findings don't have a line
+		if hit.Line > 0 {
+			location.TextRange = &sonarTextRange{StartLine: hit.Line}
+		}
+		report.Issues = append(report.Issues, sonarIssue{
+			EngineID:        toolName,
+			RuleID:          strconv.Itoa(hit.Code),
+			Severity:        sonarSeverity(hit.Severity),
+			Type:            sonarIssueType,
+			PrimaryLocation: location,
+		})
+	}
+	return report
+}
+
+// sonarSeverity maps the severity of the hit to a SonarQube severity, unknown severities are reported as INFO
+func sonarSeverity(severity string) string {
+	if sonarSeverity, ok := sonarSeverities[severity]; ok {
+		return sonarSeverity
+	}
+	return sonarSeverities["info"]
+}
@@ -0,0 +1,79 @@
+/*
+ * Copyright 2021 American Express
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ * http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express
+ * or implied. See the License for the specific language governing
+ * permissions and limitations under the License.
+ */
+
+package writers
+
+import (
+	"os"
+	"path"
+	"testing"
+
+	cfgReader "github.com/americanexpress/earlybird/v4/pkg/config"
+	"github.com/americanexpress/earlybird/v4/pkg/scan"
+)
+
+func TestWriteSonarQube(t *testing.T) {
+	hits := make(chan scan.Hit)
+	go func() {
+		defer close(hits)
+		for _, hit := range []scan.Hit{
+			{Code: 1001, Filename: "/builds/app/config/aws.env", Caption: "Potential AWS key", Line: 2, Severity: "critical"},
+			{Code: 3001, Filename: "/builds/app/config/settings.py", Caption: "Potential password in file", Line: 12, Severity: "high"},
+			{Code: 2002, Filename: "/builds/app/certs/server.pem", Caption: "Potential cryptographic key bundle", Severity: "medium"},
+			{Code: 4001, Filename: "/builds/app/README.md", Caption: "Non-inclusive language", Line: 7, Severity: "low"},
+			{Code: 5001, Filename: "/builds/app/main.go", Caption: "Commented out code", Line: 40, Severity: "info"},
+		} {
+			hits <- hit
+		}
+	}()
+
+	output := path.Join(t.TempDir(), "sonarqube.json")
+	if err := WriteSonarQube(hits, cfgReader.EarlybirdConfig{SearchDir: "/builds/app"}, output); err != nil {
+		t.Fatalf("WriteSonarQube() err = %v", err)
+	}
+	got, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile("test_data/sonarqube.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("WriteSonarQube() = %s, want %s", got, want)
+	}
+}
+
+func Test_sonarSeverity(t *testing.T) {
+	tests := []struct {
+		severity string
+		want     string
+	}{
+		{severity: "critical", want: "BLOCKER"},
+		{severity: "high", want: "CRITICAL"},
+		{severity: "medium", want: "MAJOR"},
+		{severity: "low", want: "MINOR"},
+		{severity: "info", want: "INFO"},
+		{severity: "unknown", want: "INFO"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.severity, func(t *testing.T) {
+			if got := sonarSeverity(tt.severity); got != tt.want {
+				t.Errorf("sonarSeverity() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}